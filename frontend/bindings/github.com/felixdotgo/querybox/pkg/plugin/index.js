@@ -7,9 +7,12 @@ export {
     ConnectionTreeResponse,
     DescribeSchemaResponse,
     ExecResponse,
+    GenerateDDLResponse,
     GetCompletionFieldsResponse,
     MutateRowResponse,
-    TestConnectionResponse
+    Setting,
+    TestConnectionResponse,
+    ValidateResponse
 } from "./models.js";
 
 import * as $models from "./models.js";
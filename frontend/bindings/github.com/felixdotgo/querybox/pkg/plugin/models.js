@@ -42,6 +42,12 @@ export const ExecResponse = pluginpb$0.PluginV1_ExecResponse;
  * @typedef {pluginpb$0.PluginV1_ExecResponse} ExecResponse
  */
 
+export const GenerateDDLResponse = pluginpb$0.PluginV1_GenerateDDLResponse;
+
+/**
+ * @typedef {pluginpb$0.PluginV1_GenerateDDLResponse} GenerateDDLResponse
+ */
+
 export const GetCompletionFieldsResponse = pluginpb$0.PluginV1_GetCompletionFieldsResponse;
 
 /**
@@ -58,8 +64,20 @@ export const MutateRowResponse = pluginpb$0.PluginV1_MutateRowResponse;
  * @typedef {pluginpb$0.PluginV1_MutateRowRequest_OperationType} OperationType
  */
 
+export const Setting = pluginpb$0.PluginV1_Setting;
+
+/**
+ * @typedef {pluginpb$0.PluginV1_Setting} Setting
+ */
+
 export const TestConnectionResponse = pluginpb$0.PluginV1_TestConnectionResponse;
 
 /**
  * @typedef {pluginpb$0.PluginV1_TestConnectionResponse} TestConnectionResponse
  */
+
+export const ValidateResponse = pluginpb$0.PluginV1_ValidateResponse;
+
+/**
+ * @typedef {pluginpb$0.PluginV1_ValidateResponse} ValidateResponse
+ */
@@ -11,14 +11,18 @@ export {
     PluginV1_ConnectionTreeNode,
     PluginV1_ConnectionTreeResponse,
     PluginV1_DescribeSchemaResponse,
+    PluginV1_ErrorCode,
     PluginV1_ExecResponse,
     PluginV1_ExecResult,
     PluginV1_FieldInfo,
+    PluginV1_GenerateDDLResponse,
     PluginV1_GetCompletionFieldsResponse,
     PluginV1_IndexSchema,
     PluginV1_MutateRowRequest_OperationType,
     PluginV1_MutateRowResponse,
     PluginV1_NodeType,
+    PluginV1_Setting,
     PluginV1_TableSchema,
-    PluginV1_TestConnectionResponse
+    PluginV1_TestConnectionResponse,
+    PluginV1_ValidateResponse
 } from "./models.js";
@@ -58,7 +58,7 @@ export class PluginV1_AuthField {
         }
         if (/** @type {any} */(false)) {
             /**
-             * for select inputs
+             * for select and multiselect inputs
              * @member
              * @type {string[] | undefined}
              */
@@ -109,6 +109,14 @@ export const PluginV1_AuthField_FieldType = {
     PluginV1_AuthField_CHECKBOX: 4,
     PluginV1_AuthField_SELECT: 5,
     PluginV1_AuthField_FILE_PATH: 6,
+
+    /**
+     * MULTISELECT picks any number of values, from options when given or
+     * typed freely otherwise.  The value is carried in the credential blob
+     * as a JSON array of strings (a comma-separated list is also accepted);
+     * plugins read it with plugin.ParseMultiValue.
+     */
+    PluginV1_AuthField_MULTISELECT: 7,
 };
 
 /**
@@ -278,6 +286,15 @@ export class PluginV1_ConnectionTreeAction {
              */
             this["new_tab"] = undefined;
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * destructive marks actions that lose data (drop, flush, delete).  The
+             * host asks for confirmation before running them.
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["destructive"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -336,6 +353,15 @@ export class PluginV1_ConnectionTreeNode {
              */
             this["node_type"] = undefined;
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * expanded asks the host to show the node open when the tree is first
+             * loaded, e.g. the connection's default database or schema.
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["expanded"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -426,6 +452,51 @@ export class PluginV1_DescribeSchemaResponse {
     }
 }
 
+/**
+ * ErrorCode is a machine-readable classification of a plugin error.
+ * ERROR_CODE_UNKNOWN means the plugin did not (or could not) classify it.
+ * @readonly
+ * @enum {number}
+ */
+export const PluginV1_ErrorCode = {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero: 0,
+
+    PluginV1_ERROR_CODE_UNKNOWN: 0,
+
+    /**
+     * credentials rejected
+     */
+    PluginV1_ERROR_CODE_AUTH_FAILED: 1,
+
+    /**
+     * statement or connection timed out / was cancelled
+     */
+    PluginV1_ERROR_CODE_TIMEOUT: 2,
+
+    /**
+     * query could not be parsed
+     */
+    PluginV1_ERROR_CODE_SYNTAX_ERROR: 3,
+
+    /**
+     * server unreachable or connection dropped
+     */
+    PluginV1_ERROR_CODE_CONNECTION_FAILED: 4,
+
+    /**
+     * database, table or other object does not exist
+     */
+    PluginV1_ERROR_CODE_NOT_FOUND: 5,
+
+    /**
+     * operation not supported by the driver/server
+     */
+    PluginV1_ERROR_CODE_UNSUPPORTED: 6,
+};
+
 /**
  * ExecResponse contains the output of an Exec call,
  * provide a typed, extensible envelope that can represent at least three
@@ -454,6 +525,15 @@ export class PluginV1_ExecResponse {
              */
             this["error"] = undefined;
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * error_code optionally classifies `error` so the host can react to
+             * specific failures (e.g. prompt for credentials on AUTH_FAILED).
+             * @member
+             * @type {PluginV1_ErrorCode | undefined}
+             */
+            this["error_code"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -495,6 +575,27 @@ export class PluginV1_ExecResult {
              */
             this["Payload"] = null;
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * warnings carries non-fatal messages the server raised while running
+             * the statement (MySQL SHOW WARNINGS, PostgreSQL notices), one per
+             * entry, so the UI can flag results that may be incomplete or coerced.
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["warnings"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * metadata carries driver-specific statistics about the statement
+             * (execution time, rows examined, index used, ...) as display strings,
+             * so plugins can report them without a dedicated payload type.  Keys
+             * are free-form; see pkg/plugin for the well-known ones.
+             * @member
+             * @type {{ [_ in string]?: string } | undefined}
+             */
+            this["metadata"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -505,7 +606,15 @@ export class PluginV1_ExecResult {
      * @returns {PluginV1_ExecResult}
      */
     static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType0;
+        const $$createField2_0 = $$createType15;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("warnings" in $$parsedSource) {
+            $$parsedSource["warnings"] = $$createField1_0($$parsedSource["warnings"]);
+        }
+        if ("metadata" in $$parsedSource) {
+            $$parsedSource["metadata"] = $$createField2_0($$parsedSource["metadata"]);
+        }
         return new PluginV1_ExecResult(/** @type {Partial<PluginV1_ExecResult>} */($$parsedSource));
     }
 }
@@ -549,6 +658,53 @@ export class PluginV1_FieldInfo {
     }
 }
 
+/**
+ * GenerateDDLResponse carries the DDL text.  error is set when the object
+ * could not be described; unsupported is set when the plugin cannot
+ * generate DDL at all.
+ */
+export class PluginV1_GenerateDDLResponse {
+    /**
+     * Creates a new PluginV1_GenerateDDLResponse instance.
+     * @param {Partial<PluginV1_GenerateDDLResponse>} [$$source = {}] - The source object to create the PluginV1_GenerateDDLResponse.
+     */
+    constructor($$source = {}) {
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["ddl"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["error"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["unsupported"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PluginV1_GenerateDDLResponse instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {PluginV1_GenerateDDLResponse}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PluginV1_GenerateDDLResponse(/** @type {Partial<PluginV1_GenerateDDLResponse>} */($$parsedSource));
+    }
+}
+
 /**
  * GetCompletionFieldsResponse holds the sampled field names for a collection.
  */
@@ -575,7 +731,7 @@ export class PluginV1_GetCompletionFieldsResponse {
      * @returns {PluginV1_GetCompletionFieldsResponse}
      */
     static createFrom($$source = {}) {
-        const $$createField0_0 = $$createType17;
+        const $$createField0_0 = $$createType18;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("fields" in $$parsedSource) {
             $$parsedSource["fields"] = $$createField0_0($$parsedSource["fields"]);
@@ -740,6 +896,83 @@ export const PluginV1_NodeType = {
     PluginV1_NODE_TYPE_GROUP: 9,
 };
 
+/**
+ * Setting declares one configurable option.  The host sends the user's
+ * value as ExecRequest.options["setting.<key>"]; when absent the plugin
+ * applies default_value.
+ */
+export class PluginV1_Setting {
+    /**
+     * Creates a new PluginV1_Setting instance.
+     * @param {Partial<PluginV1_Setting>} [$$source = {}] - The source object to create the PluginV1_Setting.
+     */
+    constructor($$source = {}) {
+        if (/** @type {any} */(false)) {
+            /**
+             * machine name (lower-case, no spaces)
+             * @member
+             * @type {string | undefined}
+             */
+            this["key"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * human-friendly label
+             * @member
+             * @type {string | undefined}
+             */
+            this["label"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * input to render (TEXT, NUMBER, CHECKBOX, SELECT)
+             * @member
+             * @type {PluginV1_AuthField_FieldType | undefined}
+             */
+            this["type"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["default_value"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * optional help text
+             * @member
+             * @type {string | undefined}
+             */
+            this["description"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * for SELECT inputs
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["options"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PluginV1_Setting instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {PluginV1_Setting}
+     */
+    static createFrom($$source = {}) {
+        const $$createField5_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("options" in $$parsedSource) {
+            $$parsedSource["options"] = $$createField5_0($$parsedSource["options"]);
+        }
+        return new PluginV1_Setting(/** @type {Partial<PluginV1_Setting>} */($$parsedSource));
+    }
+}
+
 /**
  * TableSchema represents the structure of a single table or collection.
  */
@@ -780,8 +1013,8 @@ export class PluginV1_TableSchema {
      * @returns {PluginV1_TableSchema}
      */
     static createFrom($$source = {}) {
-        const $$createField1_0 = $$createType20;
-        const $$createField2_0 = $$createType23;
+        const $$createField1_0 = $$createType21;
+        const $$createField2_0 = $$createType24;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("columns" in $$parsedSource) {
             $$parsedSource["columns"] = $$createField1_0($$parsedSource["columns"]);
@@ -818,6 +1051,24 @@ export class PluginV1_TestConnectionResponse {
              */
             this["message"] = undefined;
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * latency_ms is the round-trip time of the ping, set on success by
+             * plugins that measure it.
+             * @member
+             * @type {number | null | undefined}
+             */
+            this["latency_ms"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * server_info carries best-effort details about the server, e.g.
+             * "version" and "edition".  Absent keys are simply unknown.
+             * @member
+             * @type {{ [_ in string]?: string } | undefined}
+             */
+            this["server_info"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -828,11 +1079,63 @@ export class PluginV1_TestConnectionResponse {
      * @returns {PluginV1_TestConnectionResponse}
      */
     static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType15;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("server_info" in $$parsedSource) {
+            $$parsedSource["server_info"] = $$createField3_0($$parsedSource["server_info"]);
+        }
         return new PluginV1_TestConnectionResponse(/** @type {Partial<PluginV1_TestConnectionResponse>} */($$parsedSource));
     }
 }
 
+/**
+ * ValidateResponse reports whether the query parsed/planned successfully.
+ * message carries the parser/planner error when valid=false.  unsupported
+ * is set when the plugin cannot validate queries; hosts should then treat
+ * the query as neither valid nor invalid.
+ */
+export class PluginV1_ValidateResponse {
+    /**
+     * Creates a new PluginV1_ValidateResponse instance.
+     * @param {Partial<PluginV1_ValidateResponse>} [$$source = {}] - The source object to create the PluginV1_ValidateResponse.
+     */
+    constructor($$source = {}) {
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["valid"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["message"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["unsupported"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PluginV1_ValidateResponse instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {PluginV1_ValidateResponse}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PluginV1_ValidateResponse(/** @type {Partial<PluginV1_ValidateResponse>} */($$parsedSource));
+    }
+}
+
 /**
  * @typedef {any} isPluginV1_ExecResult_Payload
  */
@@ -853,12 +1156,13 @@ const $$createType11 = $Create.Nullable($$createType10);
 const $$createType12 = $Create.Array($$createType11);
 const $$createType13 = PluginV1_ExecResult.createFrom;
 const $$createType14 = $Create.Nullable($$createType13);
-const $$createType15 = PluginV1_FieldInfo.createFrom;
-const $$createType16 = $Create.Nullable($$createType15);
-const $$createType17 = $Create.Array($$createType16);
-const $$createType18 = PluginV1_ColumnSchema.createFrom;
-const $$createType19 = $Create.Nullable($$createType18);
-const $$createType20 = $Create.Array($$createType19);
-const $$createType21 = PluginV1_IndexSchema.createFrom;
-const $$createType22 = $Create.Nullable($$createType21);
-const $$createType23 = $Create.Array($$createType22);
+const $$createType15 = $Create.Map($Create.Any, $Create.Any);
+const $$createType16 = PluginV1_FieldInfo.createFrom;
+const $$createType17 = $Create.Nullable($$createType16);
+const $$createType18 = $Create.Array($$createType17);
+const $$createType19 = PluginV1_ColumnSchema.createFrom;
+const $$createType20 = $Create.Nullable($$createType19);
+const $$createType21 = $Create.Array($$createType20);
+const $$createType22 = PluginV1_IndexSchema.createFrom;
+const $$createType23 = $Create.Nullable($$createType22);
+const $$createType24 = $Create.Array($$createType23);
@@ -10,6 +10,10 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 // @ts-ignore: Unused imports
 import * as application$0 from "../../../wailsapp/wails/v3/pkg/application/models.js";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
 /**
  * CloseConnectionsWindow hides the connections window and sends it to the back.
  * @returns {$CancellablePromise<void>}
@@ -120,14 +124,28 @@ export function NewPluginsWindow() {
 }
 
 /**
- * OpenFileDialog opens a native file picker and returns the selected file path.
- * Returns an empty string if the user cancels.
+ * OpenFileDialog opens a native file picker for SQLite database files and
+ * returns the selected file path. Returns an empty string if the user cancels.
  * @returns {$CancellablePromise<string>}
  */
 export function OpenFileDialog() {
     return $Call.ByID(309694127);
 }
 
+/**
+ * OpenFileDialogWithFilters opens a native file picker with the given title
+ * and type filters (an "All Files" filter is always offered) and returns the
+ * selected file path, so auth form fields other than SQLite files (e.g. a CA
+ * certificate) can reuse the picker. Returns an empty string if the user
+ * cancels.
+ * @param {string} title
+ * @param {$models.FileFilter[]} filters
+ * @returns {$CancellablePromise<string>}
+ */
+export function OpenFileDialogWithFilters(title, filters) {
+    return $Call.ByID(2533273254, title, filters);
+}
+
 /**
  * OpenURL opens the specified URL in the system's default browser.
  * @param {string} url
@@ -147,6 +165,18 @@ export function Quit() {
     return $Call.ByID(2422379594);
 }
 
+/**
+ * SaveResults opens a native save dialog filtered for the given export format
+ * ("csv" or "json") and writes the already-encoded content to the chosen
+ * file. It returns the written path, or an empty string if the user cancels.
+ * @param {string} format
+ * @param {string} content
+ * @returns {$CancellablePromise<string>}
+ */
+export function SaveResults(format, content) {
+    return $Call.ByID(3163216882, format, content);
+}
+
 /**
  * ShowAboutDialog displays a native About dialog for the application.
  * @returns {$CancellablePromise<void>}
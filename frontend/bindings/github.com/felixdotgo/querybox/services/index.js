@@ -4,13 +4,18 @@
 
 import * as App from "./app.js";
 import * as ConnectionService from "./connectionservice.js";
+import * as LogService from "./logservice.js";
 export {
     App,
-    ConnectionService
+    ConnectionService,
+    LogService
 };
 
 export {
     Connection,
+    ConnectionStatus,
+    DeleteConnectionsResult,
+    FileFilter,
     LogEntry,
     LogLevel
 } from "./models.js";
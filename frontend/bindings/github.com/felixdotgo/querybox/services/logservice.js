@@ -0,0 +1,62 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+/**
+ * LogService exposes the retained application log to the frontend.
+ * @module
+ */
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as $models from "./models.js";
+
+/**
+ * DisableFileLogging stops writing to the log file and closes it.
+ * @returns {$CancellablePromise<void>}
+ */
+export function DisableFileLogging() {
+    return $Call.ByID(1856118604);
+}
+
+/**
+ * LogFilePath returns the file log entries are written to, or "" when file
+ * logging is off.
+ * @returns {$CancellablePromise<string>}
+ */
+export function LogFilePath() {
+    return $Call.ByID(315363902);
+}
+
+/**
+ * RecentLogs returns up to limit of the most recent log entries, oldest
+ * first. A non-positive limit returns the whole buffer.
+ * @param {number} limit
+ * @returns {$CancellablePromise<$models.LogEntry[]>}
+ */
+export function RecentLogs(limit) {
+    return $Call.ByID(3067804227, limit).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
+/**
+ * SetFileLogging starts writing log entries at or above level to path as
+ * JSON lines, replacing any previous log file. An empty path uses
+ * DefaultLogFilePath and an empty level keeps every entry. It returns the
+ * path in use.
+ * @param {string} path
+ * @param {$models.LogLevel} level
+ * @returns {$CancellablePromise<string>}
+ */
+export function SetFileLogging(path, level) {
+    return $Call.ByID(2247773544, path, level);
+}
+
+// Private type creation functions
+const $$createType0 = $models.LogEntry.createFrom;
+const $$createType1 = $Create.Array($$createType0);
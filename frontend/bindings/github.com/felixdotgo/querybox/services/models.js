@@ -45,6 +45,33 @@ export class Connection {
              */
             this["credential_key"] = "";
         }
+        if (!("color" in $$source)) {
+            /**
+             * Color is an optional user-chosen label colour (e.g. "#e03131") used to
+             * tell prod/staging/dev apart at a glance. Empty means no colour.
+             * @member
+             * @type {string}
+             */
+            this["color"] = "";
+        }
+        if (!("group" in $$source)) {
+            /**
+             * Group is the folder the connection is filed under in the connections
+             * list. Empty means ungrouped.
+             * @member
+             * @type {string}
+             */
+            this["group"] = "";
+        }
+        if (!("notes" in $$source)) {
+            /**
+             * Notes is optional free text the user attaches to the connection (e.g.
+             * "read replica, do not write"). Empty means no notes.
+             * @member
+             * @type {string}
+             */
+            this["notes"] = "";
+        }
         if (!("created_at" in $$source)) {
             /**
              * @member
@@ -74,6 +101,148 @@ export class Connection {
     }
 }
 
+/**
+ * ConnectionStatus is the last known health of a connection, as reported by
+ * a TestConnection or Warmup run. It lets the connections list show a
+ * green/red indicator without re-testing on every render.
+ */
+export class ConnectionStatus {
+    /**
+     * Creates a new ConnectionStatus instance.
+     * @param {Partial<ConnectionStatus>} [$$source = {}] - The source object to create the ConnectionStatus.
+     */
+    constructor($$source = {}) {
+        if (!("connection_id" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["connection_id"] = "";
+        }
+        if (!("ok" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["ok"] = false;
+        }
+        if (!("message" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["message"] = "";
+        }
+        if (!("checked_at" in $$source)) {
+            /**
+             * RFC3339 UTC
+             * @member
+             * @type {string}
+             */
+            this["checked_at"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ConnectionStatus instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ConnectionStatus}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ConnectionStatus(/** @type {Partial<ConnectionStatus>} */($$parsedSource));
+    }
+}
+
+/**
+ * DeleteConnectionsResult reports the outcome of DeleteConnections.
+ */
+export class DeleteConnectionsResult {
+    /**
+     * Creates a new DeleteConnectionsResult instance.
+     * @param {Partial<DeleteConnectionsResult>} [$$source = {}] - The source object to create the DeleteConnectionsResult.
+     */
+    constructor($$source = {}) {
+        if (!("deleted" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["deleted"] = [];
+        }
+        if (!("failed" in $$source)) {
+            /**
+             * id -> reason
+             * @member
+             * @type {{ [_ in string]?: string }}
+             */
+            this["failed"] = {};
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DeleteConnectionsResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DeleteConnectionsResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType0;
+        const $$createField1_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("deleted" in $$parsedSource) {
+            $$parsedSource["deleted"] = $$createField0_0($$parsedSource["deleted"]);
+        }
+        if ("failed" in $$parsedSource) {
+            $$parsedSource["failed"] = $$createField1_0($$parsedSource["failed"]);
+        }
+        return new DeleteConnectionsResult(/** @type {Partial<DeleteConnectionsResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * FileFilter is one entry of a native open-file dialog's type filter.
+ */
+export class FileFilter {
+    /**
+     * Creates a new FileFilter instance.
+     * @param {Partial<FileFilter>} [$$source = {}] - The source object to create the FileFilter.
+     */
+    constructor($$source = {}) {
+        if (!("name" in $$source)) {
+            /**
+             * label shown in the dialog, e.g. "PEM Certificates"
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("pattern" in $$source)) {
+            /**
+             * ";"-separated globs, e.g. "*.pem;*.crt"
+             * @member
+             * @type {string}
+             */
+            this["pattern"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FileFilter instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {FileFilter}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new FileFilter(/** @type {Partial<FileFilter>} */($$parsedSource));
+    }
+}
+
 /**
  * LogEntry is the payload emitted on the EventAppLog event.
  */
@@ -140,3 +309,7 @@ export const LogLevel = {
     LogLevelWarn: "warn",
     LogLevelError: "error",
 };
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = $Create.Map($Create.Any, $Create.Any);
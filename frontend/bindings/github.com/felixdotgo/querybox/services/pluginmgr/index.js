@@ -8,5 +8,17 @@ export {
 };
 
 export {
-    PluginInfo
+    Diagnostics,
+    PluginInfo,
+    RetryPolicy
 } from "./models.js";
+
+import * as $models from "./models.js";
+
+/**
+ * ConnectionSource looks up stored connections and their credentials, and
+ * keeps their last known health status. *services.ConnectionService
+ * satisfies it; the indirection keeps the plugin manager independent of the
+ * connections database.
+ * @typedef {$models.ConnectionSource} ConnectionSource
+ */
@@ -49,6 +49,40 @@ export function DescribeSchema(name, connection, database, table) {
     }));
 }
 
+/**
+ * Diagnostics reports the directories scanned for plugins, how many were
+ * discovered, the LastError of every plugin whose probe failed, the
+ * directories that could not be created or read and the credential backend
+ * label.
+ * @returns {$CancellablePromise<$models.Diagnostics>}
+ */
+export function Diagnostics() {
+    return $Call.ByID(4116726471).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType2($result);
+    }));
+}
+
+/**
+ * DisablePlugin adds the plugin to the persisted blocklist and removes it
+ * from the registry. Disabled plugins are skipped by subsequent scans and
+ * cannot be invoked until EnablePlugin is called.
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function DisablePlugin(name) {
+    return $Call.ByID(2618184216, name);
+}
+
+/**
+ * EnablePlugin removes the plugin from the blocklist. The plugin reappears in
+ * ListPlugins after the next scan (e.g. Rescan from the Plugins window).
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function EnablePlugin(name) {
+    return $Call.ByID(3573057673, name);
+}
+
 /**
  * ExecPlugin runs the named plugin with the provided connection info, query
  * and optional options map.  Under the hood the manager spawns the binary,
@@ -58,6 +92,9 @@ export function DescribeSchema(name, connection, database, table) {
  * Callers receive the structured `plugin.ExecResponse` (alias for the proto
  * type) or an error.  Historically this returned a raw string; callers may need
  * to examine the `Result` field to access rows, documents, or key/value data.
+ * 
+ * ctx is supplied by the Wails binding layer; cancelling the frontend promise
+ * aborts the running plugin process.
  * @param {string} name
  * @param {{ [_ in string]?: string }} connection
  * @param {string} query
@@ -66,23 +103,62 @@ export function DescribeSchema(name, connection, database, table) {
  */
 export function ExecPlugin(name, connection, query, options) {
     return $Call.ByID(2332402495, name, connection, query, options).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType3($result);
+        return $$createType4($result);
+    }));
+}
+
+/**
+ * ExecPluginPage is ExecPlugin with first-class pagination: offset and limit
+ * are forwarded as ExecRequest.Offset/Limit so grid views page consistently
+ * across drivers.  limit <= 0 requests the full result.
+ * @param {string} name
+ * @param {{ [_ in string]?: string }} connection
+ * @param {string} query
+ * @param {{ [_ in string]?: string }} options
+ * @param {number} offset
+ * @param {number} limit
+ * @returns {$CancellablePromise<plugin$0.ExecResponse | null>}
+ */
+export function ExecPluginPage(name, connection, query, options, offset, limit) {
+    return $Call.ByID(4844612, name, connection, query, options, offset, limit).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType4($result);
     }));
 }
 
 /**
  * ExecTreeAction is a convenience wrapper for executing the query payload
- * attached to a tree node action.  It simply forwards to ExecPlugin and
- * propagates any provided options map (for example "explain-query").
+ * attached to a tree node action.  It forwards to ExecPlugin and propagates
+ * any provided options map (for example "explain-query") and the caller's
+ * context.  actionType is the action's machine name; when a DDL action
+ * (create/drop database or table) succeeds the manager emits tree:invalidate
+ * for connectionID so the frontend drops its cached tree and refetches it.
  * @param {string} name
+ * @param {string} connectionID
  * @param {{ [_ in string]?: string }} connection
+ * @param {string} actionType
  * @param {string} actionQuery
  * @param {{ [_ in string]?: string }} options
  * @returns {$CancellablePromise<plugin$0.ExecResponse | null>}
  */
-export function ExecTreeAction(name, connection, actionQuery, options) {
-    return $Call.ByID(987162126, name, connection, actionQuery, options).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType3($result);
+export function ExecTreeAction(name, connectionID, connection, actionType, actionQuery, options) {
+    return $Call.ByID(987162126, name, connectionID, connection, actionType, actionQuery, options).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType4($result);
+    }));
+}
+
+/**
+ * GenerateDDL asks the named plugin for the DDL recreating the object behind
+ * the connection tree node nodeKey by invoking the `ddl` command.  Plugins
+ * that cannot generate DDL answer with unsupported=true; a plugin-reported
+ * failure is returned as an error alongside the response.
+ * @param {string} name
+ * @param {{ [_ in string]?: string }} connection
+ * @param {string} nodeKey
+ * @returns {$CancellablePromise<plugin$0.GenerateDDLResponse | null>}
+ */
+export function GenerateDDL(name, connection, nodeKey) {
+    return $Call.ByID(3763758058, name, connection, nodeKey).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType6($result);
     }));
 }
 
@@ -99,21 +175,25 @@ export function ExecTreeAction(name, connection, actionQuery, options) {
  */
 export function GetCompletionFields(name, connection, database, collection) {
     return $Call.ByID(2222067792, name, connection, database, collection).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType5($result);
+        return $$createType8($result);
     }));
 }
 
 /**
  * GetConnectionTree asks the named plugin for its connection tree.  The
- * request contains only the connection map; the plugin defines node structure
- * and actions.  A timeout guards misbehaving plugins.
+ * request contains the connection map and maxDepth; the plugin defines node
+ * structure and actions.  maxDepth limits how many levels are returned so the
+ * frontend can fetch only the top of a deep tree (1 = top-level nodes only);
+ * 0 returns the whole tree.  A timeout guards misbehaving plugins; cancelling
+ * ctx aborts the request early.
  * @param {string} name
  * @param {{ [_ in string]?: string }} connection
+ * @param {number} maxDepth
  * @returns {$CancellablePromise<plugin$0.ConnectionTreeResponse | null>}
  */
-export function GetConnectionTree(name, connection) {
-    return $Call.ByID(3147399459, name, connection).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType7($result);
+export function GetConnectionTree(name, connection, maxDepth) {
+    return $Call.ByID(3147399459, name, connection, maxDepth).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType10($result);
     }));
 }
 
@@ -121,23 +201,93 @@ export function GetConnectionTree(name, connection) {
  * GetPluginAuthForms probes the plugin executable for supported authentication
  * forms by invoking `plugin authforms` and decoding the JSON response. If the
  * plugin doesn't implement the command or returns no forms an empty map is
- * returned.
+ * returned. Decoded forms are cached per plugin for authFormsTTL so the
+ * connection dialog can ask repeatedly without spawning the plugin each time.
  * @param {string} name
  * @returns {$CancellablePromise<{ [_ in string]?: plugin$0.AuthForm | null }>}
  */
 export function GetPluginAuthForms(name) {
     return $Call.ByID(545463133, name).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType13($result);
+    }));
+}
+
+/**
+ * GetPluginInfo returns the complete metadata recorded for the named plugin
+ * during the last scan, including capabilities, tags, licence and icon, so
+ * the Plugins window can render a detail panel without re-probing.
+ * @param {string} name
+ * @returns {$CancellablePromise<$models.PluginInfo>}
+ */
+export function GetPluginInfo(name) {
+    return $Call.ByID(1357167648, name).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType14($result);
+    }));
+}
+
+/**
+ * GetPluginSettingValues returns the values stored for the named plugin's
+ * settings (setting key -> value), for the Plugins window's settings panel.
+ * Settings without a stored value are absent; the plugin applies their
+ * default.
+ * @param {string} name
+ * @returns {$CancellablePromise<{ [_ in string]?: string }>}
+ */
+export function GetPluginSettingValues(name) {
+    return $Call.ByID(2914838270, name).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType15($result);
+    }));
+}
+
+/**
+ * GetPluginSettings returns the configurable options the plugin declares via
+ * `plugin settings`, for the plugin-settings panel.  Plugins that predate the
+ * command (exit non-zero without a JSON error, or print nothing) declare no
+ * settings; any other failure, such as a missing plugin or one that cannot be
+ * started, is returned.
+ * @param {string} name
+ * @returns {$CancellablePromise<(plugin$0.Setting | null)[]>}
+ */
+export function GetPluginSettings(name) {
+    return $Call.ByID(3893651457, name).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType18($result);
+    }));
+}
+
+/**
+ * GetStoredConnectionTree is GetConnectionTree for the stored connection
+ * connectionID: like RunQuery, the driver and credential are resolved on the
+ * Go side and the plugin is told which connection it serves.
+ * @param {string} connectionID
+ * @param {number} maxDepth
+ * @returns {$CancellablePromise<plugin$0.ConnectionTreeResponse | null>}
+ */
+export function GetStoredConnectionTree(connectionID, maxDepth) {
+    return $Call.ByID(2130586796, connectionID, maxDepth).then(/** @type {($result: any) => any} */(($result) => {
         return $$createType10($result);
     }));
 }
 
+/**
+ * ListDisabledPlugins returns the names on the blocklist in sorted order so
+ * the Plugins window can offer to re-enable them.
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function ListDisabledPlugins() {
+    return $Call.ByID(2582061055).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType19($result);
+    }));
+}
+
 /**
  * ListPlugins returns the discovered plugins (does not start them).
+ * Executables that failed the handshake are left out; Diagnostics reports
+ * them.
  * @returns {$CancellablePromise<$models.PluginInfo[]>}
  */
 export function ListPlugins() {
     return $Call.ByID(668942975).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType12($result);
+        return $$createType20($result);
     }));
 }
 
@@ -157,10 +307,22 @@ export function ListPlugins() {
  */
 export function MutateRow(name, connection, operation, source, values, filter) {
     return $Call.ByID(3105031897, name, connection, operation, source, values, filter).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType14($result);
+        return $$createType22($result);
     }));
 }
 
+/**
+ * Reload refreshes the registry without discarding it: plugins whose binary
+ * changed on disk (modification time or size) since they were probed are
+ * re-probed so an in-place upgrade updates their version and description,
+ * added and removed binaries are picked up, and unchanged plugins keep their
+ * cached metadata. Like Rescan it fires plugins:ready when done.
+ * @returns {$CancellablePromise<void>}
+ */
+export function Reload() {
+    return $Call.ByID(1319013756);
+}
+
 /**
  * Rescan clears the plugin registry and triggers a full re-probe of the
  * plugins directory. This ensures that any metadata changes to existing
@@ -186,6 +348,22 @@ export function RunCommand(name, command, timeout, req) {
     }));
 }
 
+/**
+ * RunQuery executes query against the stored connection connectionID. The
+ * driver and credential are resolved on the Go side, so the frontend (e.g.
+ * the editor's "Run selection" shortcut) only passes the id instead of
+ * fetching and shaping the credential blob itself.
+ * @param {string} connectionID
+ * @param {string} query
+ * @param {{ [_ in string]?: string }} options
+ * @returns {$CancellablePromise<plugin$0.ExecResponse | null>}
+ */
+export function RunQuery(connectionID, query, options) {
+    return $Call.ByID(3951487142, connectionID, query, options).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType4($result);
+    }));
+}
+
 /**
  * SetApp injects the Wails application reference so the Manager can emit
  * log events to the frontend. Call this after application.New returns.
@@ -197,8 +375,63 @@ export function SetApp(app) {
 }
 
 /**
- * Shutdown is a no-op; there is no background scanner to stop.
- * It is kept so Wails can still call the lifecycle method without error.
+ * SetConnectionSource wires the store RunQuery resolves connection ids
+ * against. Call it once at startup.
+ * @param {$models.ConnectionSource} src
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetConnectionSource(src) {
+    return $Call.ByID(128057558, src);
+}
+
+/**
+ * SetCredentialBackend records the label of the active credential backend
+ * (CredManager.Backend) so Diagnostics can report it; the plugin manager has
+ * no access to the store itself.
+ * @param {string} label
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetCredentialBackend(label) {
+    return $Call.ByID(3561222846, label);
+}
+
+/**
+ * SetMaxConcurrentExecs changes how many ExecPlugin/GetConnectionTree
+ * subprocesses may run at once; n <= 0 removes the limit. Calls already
+ * holding a slot are unaffected.
+ * @param {number} n
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetMaxConcurrentExecs(n) {
+    return $Call.ByID(3392496168, n);
+}
+
+/**
+ * SetPluginSettingValues replaces the stored setting values of the named
+ * plugin. Every later exec request to the plugin carries them as
+ * setting.<key> options (see plugin.SettingOptions); an empty map restores
+ * the defaults.
+ * @param {string} name
+ * @param {{ [_ in string]?: string }} values
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetPluginSettingValues(name, values) {
+    return $Call.ByID(1771422682, name, values);
+}
+
+/**
+ * SetRetryPolicy replaces the manager's retry policy. Retries are disabled
+ * until this is called.
+ * @param {$models.RetryPolicy} p
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetRetryPolicy(p) {
+    return $Call.ByID(4081720525, p);
+}
+
+/**
+ * Shutdown releases the settings database backing the plugin blocklist and
+ * setting values. There is no background scanner to stop.
  * @returns {$CancellablePromise<void>}
  */
 export function Shutdown() {
@@ -218,25 +451,102 @@ export function Shutdown() {
  */
 export function TestConnection(name, connection) {
     return $Call.ByID(2822844201, name, connection).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType16($result);
+        return $$createType24($result);
+    }));
+}
+
+/**
+ * TestConnectionForms tests the same connection once per candidate auth form
+ * (AuthForm.key), setting TestConnectionRequest.Form so the plugin reads the
+ * credential values through that form.  Results are keyed by form.  Forms are
+ * tried in order; an error is returned as soon as the plugin cannot be
+ * invoked, since that failure would repeat for every remaining form.
+ * @param {string} name
+ * @param {{ [_ in string]?: string }} connection
+ * @param {string[]} forms
+ * @returns {$CancellablePromise<{ [_ in string]?: plugin$0.TestConnectionResponse | null }>}
+ */
+export function TestConnectionForms(name, connection, forms) {
+    return $Call.ByID(2971338528, name, connection, forms).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType25($result);
+    }));
+}
+
+/**
+ * ValidatePlugin asks the named plugin to parse/plan `query` without
+ * executing it by invoking the `validate` command.  Plugins built on
+ * ServeCLI that do not implement validation answer with unsupported=true; an
+ * error is only returned when the plugin cannot be invoked at all.
+ * @param {string} name
+ * @param {{ [_ in string]?: string }} connection
+ * @param {string} query
+ * @returns {$CancellablePromise<plugin$0.ValidateResponse | null>}
+ */
+export function ValidatePlugin(name, connection, query) {
+    return $Call.ByID(3991953808, name, connection, query).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType27($result);
+    }));
+}
+
+/**
+ * Warmup connects to the data store ahead of the first query by running the
+ * plugin's test-connection command, so the editor can show the connection
+ * status early. Results, successful or not, are cached for warmupTTL per
+ * driver and connection; calls within that window return the cached response
+ * without starting the plugin. Each call returns its own copy of the
+ * response. An error (plugin missing or failing to run) is returned as-is and
+ * not cached.
+ * @param {string} name
+ * @param {{ [_ in string]?: string }} connection
+ * @returns {$CancellablePromise<plugin$0.TestConnectionResponse | null>}
+ */
+export function Warmup(name, connection) {
+    return $Call.ByID(65783317, name, connection).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType24($result);
+    }));
+}
+
+/**
+ * WarmupConnection is Warmup for the stored connection connectionID: like
+ * RunQuery, the driver and credential are resolved on the Go side. The
+ * outcome is recorded as the connection's status through the
+ * ConnectionSource, so the connections list can show it without testing
+ * again; a plugin that fails to run counts as a failed check.
+ * @param {string} connectionID
+ * @returns {$CancellablePromise<plugin$0.TestConnectionResponse | null>}
+ */
+export function WarmupConnection(connectionID) {
+    return $Call.ByID(2557215273, connectionID).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType24($result);
     }));
 }
 
 // Private type creation functions
 const $$createType0 = pluginpb$0.PluginV1_DescribeSchemaResponse.createFrom;
 const $$createType1 = $Create.Nullable($$createType0);
-const $$createType2 = pluginpb$0.PluginV1_ExecResponse.createFrom;
-const $$createType3 = $Create.Nullable($$createType2);
-const $$createType4 = pluginpb$0.PluginV1_GetCompletionFieldsResponse.createFrom;
-const $$createType5 = $Create.Nullable($$createType4);
-const $$createType6 = pluginpb$0.PluginV1_ConnectionTreeResponse.createFrom;
-const $$createType7 = $Create.Nullable($$createType6);
-const $$createType8 = pluginpb$0.PluginV1_AuthForm.createFrom;
-const $$createType9 = $Create.Nullable($$createType8);
-const $$createType10 = $Create.Map($Create.Any, $$createType9);
-const $$createType11 = $models.PluginInfo.createFrom;
-const $$createType12 = $Create.Array($$createType11);
-const $$createType13 = pluginpb$0.PluginV1_MutateRowResponse.createFrom;
-const $$createType14 = $Create.Nullable($$createType13);
-const $$createType15 = pluginpb$0.PluginV1_TestConnectionResponse.createFrom;
-const $$createType16 = $Create.Nullable($$createType15);
+const $$createType2 = $models.Diagnostics.createFrom;
+const $$createType3 = pluginpb$0.PluginV1_ExecResponse.createFrom;
+const $$createType4 = $Create.Nullable($$createType3);
+const $$createType5 = pluginpb$0.PluginV1_GenerateDDLResponse.createFrom;
+const $$createType6 = $Create.Nullable($$createType5);
+const $$createType7 = pluginpb$0.PluginV1_GetCompletionFieldsResponse.createFrom;
+const $$createType8 = $Create.Nullable($$createType7);
+const $$createType9 = pluginpb$0.PluginV1_ConnectionTreeResponse.createFrom;
+const $$createType10 = $Create.Nullable($$createType9);
+const $$createType11 = pluginpb$0.PluginV1_AuthForm.createFrom;
+const $$createType12 = $Create.Nullable($$createType11);
+const $$createType13 = $Create.Map($Create.Any, $$createType12);
+const $$createType14 = $models.PluginInfo.createFrom;
+const $$createType15 = $Create.Map($Create.Any, $Create.Any);
+const $$createType16 = pluginpb$0.PluginV1_Setting.createFrom;
+const $$createType17 = $Create.Nullable($$createType16);
+const $$createType18 = $Create.Array($$createType17);
+const $$createType19 = $Create.Array($Create.Any);
+const $$createType20 = $Create.Array($$createType14);
+const $$createType21 = pluginpb$0.PluginV1_MutateRowResponse.createFrom;
+const $$createType22 = $Create.Nullable($$createType21);
+const $$createType23 = pluginpb$0.PluginV1_TestConnectionResponse.createFrom;
+const $$createType24 = $Create.Nullable($$createType23);
+const $$createType25 = $Create.Map($Create.Any, $$createType24);
+const $$createType26 = pluginpb$0.PluginV1_ValidateResponse.createFrom;
+const $$createType27 = $Create.Nullable($$createType26);
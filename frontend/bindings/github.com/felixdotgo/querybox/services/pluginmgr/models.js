@@ -6,6 +6,95 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../../../../time/models.js";
+
+/**
+ * ConnectionSource looks up stored connections and their credentials, and
+ * keeps their last known health status. *services.ConnectionService
+ * satisfies it; the indirection keeps the plugin manager independent of the
+ * connections database.
+ * @typedef {any} ConnectionSource
+ */
+
+/**
+ * Diagnostics summarises the plugin manager's state for a support/debug
+ * panel.
+ */
+export class Diagnostics {
+    /**
+     * Creates a new Diagnostics instance.
+     * @param {Partial<Diagnostics>} [$$source = {}] - The source object to create the Diagnostics.
+     */
+    constructor($$source = {}) {
+        if (!("pluginDirs" in $$source)) {
+            /**
+             * scanned in order of precedence
+             * @member
+             * @type {string[]}
+             */
+            this["pluginDirs"] = [];
+        }
+        if (!("pluginCount" in $$source)) {
+            /**
+             * entries in the registry, failed probes included
+             * @member
+             * @type {number}
+             */
+            this["pluginCount"] = 0;
+        }
+        if (!("errors" in $$source)) {
+            /**
+             * plugin ID -> LastError, for plugins that have one
+             * @member
+             * @type {{ [_ in string]?: string }}
+             */
+            this["errors"] = {};
+        }
+        if (!("dirErrors" in $$source)) {
+            /**
+             * plugin dir -> error creating or reading it
+             * @member
+             * @type {{ [_ in string]?: string }}
+             */
+            this["dirErrors"] = {};
+        }
+        if (!("credentialBackend" in $$source)) {
+            /**
+             * "keyring", "sqlite", "memory" or "" when unknown
+             * @member
+             * @type {string}
+             */
+            this["credentialBackend"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Diagnostics instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Diagnostics}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType0;
+        const $$createField2_0 = $$createType1;
+        const $$createField3_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("pluginDirs" in $$parsedSource) {
+            $$parsedSource["pluginDirs"] = $$createField0_0($$parsedSource["pluginDirs"]);
+        }
+        if ("errors" in $$parsedSource) {
+            $$parsedSource["errors"] = $$createField2_0($$parsedSource["errors"]);
+        }
+        if ("dirErrors" in $$parsedSource) {
+            $$parsedSource["dirErrors"] = $$createField3_0($$parsedSource["dirErrors"]);
+        }
+        return new Diagnostics(/** @type {Partial<Diagnostics>} */($$parsedSource));
+    }
+}
+
 /**
  * On Windows the helper hideWindow (implemented in platform-specific files)
  * will configure subprocesses so they do not show a console window. This
@@ -139,6 +228,15 @@ export class PluginInfo {
              */
             this["settings"] = undefined;
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * ExampleQueries are editor snippets declared by the plugin for new
+             * connections.
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["example_queries"] = undefined;
+        }
         if (/** @type {any} */(false)) {
             /**
              * @member
@@ -160,6 +258,7 @@ export class PluginInfo {
         const $$createField10_0 = $$createType0;
         const $$createField14_0 = $$createType1;
         const $$createField15_0 = $$createType1;
+        const $$createField16_0 = $$createType0;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("capabilities" in $$parsedSource) {
             $$parsedSource["capabilities"] = $$createField9_0($$parsedSource["capabilities"]);
@@ -173,10 +272,58 @@ export class PluginInfo {
         if ("settings" in $$parsedSource) {
             $$parsedSource["settings"] = $$createField15_0($$parsedSource["settings"]);
         }
+        if ("example_queries" in $$parsedSource) {
+            $$parsedSource["example_queries"] = $$createField16_0($$parsedSource["example_queries"]);
+        }
         return new PluginInfo(/** @type {Partial<PluginInfo>} */($$parsedSource));
     }
 }
 
+/**
+ * RetryPolicy configures automatic retries of transient connection failures
+ * (connection refused, host unreachable, ...) in ExecPlugin and
+ * TestConnection. Deterministic failures such as syntax errors are never
+ * retried.
+ */
+export class RetryPolicy {
+    /**
+     * Creates a new RetryPolicy instance.
+     * @param {Partial<RetryPolicy>} [$$source = {}] - The source object to create the RetryPolicy.
+     */
+    constructor($$source = {}) {
+        if (!("attempts" in $$source)) {
+            /**
+             * Attempts is the total number of tries including the first; values
+             * below 2 disable retries.
+             * @member
+             * @type {number}
+             */
+            this["attempts"] = 0;
+        }
+        if (!("backoff" in $$source)) {
+            /**
+             * Backoff is the delay before the first retry; it doubles for each
+             * further retry.
+             * @member
+             * @type {time$0.Duration}
+             */
+            this["backoff"] = time$0.Duration.$zero;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RetryPolicy instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RetryPolicy}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RetryPolicy(/** @type {Partial<RetryPolicy>} */($$parsedSource));
+    }
+}
+
 // Private type creation functions
 const $$createType0 = $Create.Array($Create.Any);
 const $$createType1 = $Create.Map($Create.Any, $Create.Any);
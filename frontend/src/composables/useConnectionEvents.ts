@@ -14,8 +14,9 @@ interface ConnectionEventsOptions {
 }
 
 /**
- * Subscribes to backend connection domain events (created, updated, deleted)
- * and keeps local state in sync. Automatically unsubscribes on unmount.
+ * Subscribes to backend connection domain events (created, updated, deleted,
+ * deleted-batch) and keeps local state in sync. Automatically unsubscribes on
+ * unmount.
  */
 export function useConnectionEvents(opts: ConnectionEventsOptions) {
   const {
@@ -55,6 +56,18 @@ export function useConnectionEvents(opts: ConnectionEventsOptions) {
     }
   })
 
+  function forgetConnection(id: string) {
+    delete connectionTrees[id]
+    delete schemaCache[id]
+    if (selectedConnection.value?.id === id)
+      selectedConnection.value = null
+    expandedKeys.value = expandedKeys.value.filter(k => k !== id)
+    Object.keys(loadingNodes.value).forEach((k) => {
+      if (k.startsWith(`${id}:`))
+        delete loadingNodes.value[k]
+    })
+  }
+
   const offConnectionDeleted = Events.On('connection:deleted', async (event: any) => {
     const id = (event?.data ?? event)?.id
     if (!id)
@@ -65,15 +78,20 @@ export function useConnectionEvents(opts: ConnectionEventsOptions) {
     catch (err) {
       console.error('connection:deleted handler loadConnections', err)
     }
-    delete connectionTrees[id]
-    delete schemaCache[id]
-    if (selectedConnection.value?.id === id)
-      selectedConnection.value = null
-    expandedKeys.value = expandedKeys.value.filter(k => k !== id)
-    Object.keys(loadingNodes.value).forEach((k) => {
-      if (k.startsWith(`${id}:`))
-        delete loadingNodes.value[k]
-    })
+    forgetConnection(id)
+  })
+
+  const offConnectionsDeleted = Events.On('connection:deleted-batch', async (event: any) => {
+    const ids: string[] = (event?.data ?? event)?.ids ?? []
+    if (!ids.length)
+      return
+    try {
+      await loadConnections()
+    }
+    catch (err) {
+      console.error('connection:deleted-batch handler loadConnections', err)
+    }
+    ids.forEach(forgetConnection)
   })
 
   const offConnectionUpdated = Events.On('connection:updated', async (event: any) => {
@@ -95,6 +113,8 @@ export function useConnectionEvents(opts: ConnectionEventsOptions) {
       offConnectionCreated()
     if (offConnectionDeleted)
       offConnectionDeleted()
+    if (offConnectionsDeleted)
+      offConnectionsDeleted()
     if (offConnectionUpdated)
       offConnectionUpdated()
   })
@@ -1,7 +1,7 @@
 import type { Ref } from 'vue'
 import { reactive, ref, watch } from 'vue'
 import { GetCredential } from '@/bindings/github.com/felixdotgo/querybox/services/connectionservice'
-import { DescribeSchema, GetConnectionTree } from '@/bindings/github.com/felixdotgo/querybox/services/pluginmgr/manager'
+import { DescribeSchema, GetStoredConnectionTree } from '@/bindings/github.com/felixdotgo/querybox/services/pluginmgr/manager'
 import type { ColumnSchema, Connection, TableSchema, TreeNode } from '@/lib/types'
 
 // global reactive cache mapping connection id -> nodes array
@@ -79,6 +79,20 @@ export function tagWithConnId(nodes: TreeNode[], connId: string, _prefix?: strin
   return out
 }
 
+// Collect the keys of nodes the plugin marked `expanded`, e.g. the default
+// schema.  Pass nodes already run through tagWithConnId so the keys match the
+// tree view's.
+export function expandedNodeKeys(nodes: TreeNode[]): string[] {
+  const keys: string[] = []
+  for (const n of nodes) {
+    if (n.expanded)
+      keys.push(n.key)
+    if (n.children)
+      keys.push(...expandedNodeKeys(n.children))
+  }
+  return keys
+}
+
 function normalizeNodes(nodes: TreeNode[]): TreeNode[] {
   return nodes.map((n) => {
     const type = typeof n.node_type === 'number'
@@ -127,7 +141,7 @@ export function useConnectionTree(connRef?: Ref<Connection | null>) {
       const params: Record<string, string> = {}
       if (cred)
         params.credential_blob = cred
-      const resp = await GetConnectionTree(conn.driver_type, params)
+      const resp = await GetStoredConnectionTree(id, 0)
       treeCache[id] = normalizeNodes((resp?.nodes ?? []).filter(n => n !== null) as unknown as TreeNode[])
       // load schema info in parallel; ignore errors
       try {
@@ -1,4 +1,5 @@
-import { ref } from 'vue'
+import { Events } from '@wailsio/runtime'
+import { onUnmounted, ref } from 'vue'
 import type { ComputedRef, Ref } from 'vue'
 import { useDialog, useNotification } from 'naive-ui'
 import {
@@ -6,9 +7,10 @@ import {
   GetCredential,
 } from '@/bindings/github.com/felixdotgo/querybox/services/connectionservice'
 import {
-  ExecPlugin,
   ExecTreeAction,
+  WarmupConnection,
 } from '@/bindings/github.com/felixdotgo/querybox/services/pluginmgr/manager'
+import { expandedNodeKeys, tagWithConnId } from '@/composables/useConnectionTree'
 import { extractDatabase } from '@/lib/nodeKey'
 import type { Connection, TreeAction, TreeNode } from '@/lib/types'
 
@@ -18,7 +20,10 @@ const INSTANT_SELECT_TYPES = new Set(['table', 'collection', 'key', 'view', 'for
 /** Action types that open a user-input form before execution. */
 const PROMPT_ACTION_TYPES = new Set(['create-database', 'create-table'])
 
-/** Action types that require a destructive confirmation dialog. */
+/**
+ * Action types that require a destructive confirmation dialog even when the
+ * plugin predates the `destructive` flag.
+ */
 const DESTRUCTIVE_ACTION_TYPES = new Set(['drop-database', 'drop-table', 'drop-collection'])
 
 interface UseTreeActionsOptions {
@@ -64,8 +69,10 @@ export function useTreeActions({
     loadingNodes.value[conn.id] = true
     try {
       await loadConnectionTree(conn)
-      if (!expandedKeys.value.includes(conn.id)) {
-        expandedKeys.value = [...expandedKeys.value, conn.id]
+      const open = [conn.id, ...expandedNodeKeys(tagWithConnId(connectionTrees[conn.id] || [], conn.id))]
+        .filter(k => !expandedKeys.value.includes(k))
+      if (open.length) {
+        expandedKeys.value = [...expandedKeys.value, ...open]
       }
     }
     catch (err: unknown) {
@@ -78,13 +85,27 @@ export function useTreeActions({
     }
   }
 
+  // The backend signals that a connection's tree changed shape (e.g. after
+  // DROP TABLE); drop the cached tree and schema and fetch them again.
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any
+  const offTreeInvalidate = Events.On('tree:invalidate', (event: any) => {
+    const id = (event?.data ?? event)?.connection_id
+    const conn = connections.value.find(c => c.id === id)
+    if (!conn)
+      return
+    delete connectionTrees[conn.id]
+    delete schemaCache[conn.id]
+    fetchTreeFor(conn)
+  })
+
+  onUnmounted(() => {
+    if (offTreeInvalidate)
+      offTreeInvalidate()
+  })
+
   async function checkConnection(conn: Connection) {
     try {
-      const cred = await GetCredential(conn.id)
-      const params: Record<string, string> = {}
-      if (cred)
-        params.credential_blob = cred
-      await ExecPlugin(conn.driver_type, params, 'SELECT 1', {})
+      await WarmupConnection(conn.id)
     }
     catch (err: unknown) {
       console.error('connection check', conn.id, err)
@@ -119,20 +140,19 @@ export function useTreeActions({
         }
         const res = await ExecTreeAction(
           conn.driver_type,
+          conn.id,
           params,
+          action.type,
           action.query || '',
           (extras.options as Record<string, string>) || ((extras.explain) ? { 'explain-query': 'yes' } : {}),
         )
         if (!res) return
+        // On success the backend emits tree:invalidate for DDL actions,
+        // which refreshes the tree (see the listener below).
         if (res.error) {
           console.error('runTreeAction [hidden]', action.type, res.error)
           notification.error({ title: 'Action failed', content: res.error, duration: 5000 })
         }
-        else {
-          delete connectionTrees[conn.id]
-          delete schemaCache[conn.id]
-          fetchTreeFor(conn)
-        }
       }
       catch (err: unknown) {
         console.error('runTreeAction [hidden] error', action.type, (err as Error)?.message || err)
@@ -162,7 +182,9 @@ export function useTreeActions({
 
       const res = await ExecTreeAction(
         conn.driver_type,
+        conn.id,
         params,
+        action.type,
         queryToRun,
         (extras.options as Record<string, string>) || ((extras.explain) ? { 'explain-query': 'yes' } : {}),
       )
@@ -227,7 +249,7 @@ export function useTreeActions({
       return
     }
 
-    if (DESTRUCTIVE_ACTION_TYPES.has(action.type)) {
+    if (action.destructive || DESTRUCTIVE_ACTION_TYPES.has(action.type)) {
       dialog.error({
         title: action.title ?? 'Confirm action',
         content: `The following query will be executed — this cannot be undone:\n\n${action.query}`,
@@ -0,0 +1,19 @@
+import type { TestConnectionResponse } from './types'
+
+/**
+ * Build the status line shown after testing a connection: the plugin's
+ * message followed, on success, by the ping latency and server version when
+ * the plugin reported them, e.g. "Connection successful (12 ms, 8.0.36)".
+ */
+export function testConnectionMessage(res: TestConnectionResponse): string {
+  const message = res.message || (res.ok ? 'Connection successful' : 'Connection failed')
+  if (!res.ok)
+    return message
+  const details: string[] = []
+  if (res.latency_ms != null)
+    details.push(`${res.latency_ms} ms`)
+  const version = res.server_info?.version
+  if (version)
+    details.push(version)
+  return details.length ? `${message} (${details.join(', ')})` : message
+}
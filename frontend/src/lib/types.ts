@@ -4,10 +4,21 @@ export interface Connection {
   name: string
   driver_type: string
   credential_key: string
+  color: string
+  group: string
+  notes: string
   created_at: string
   updated_at: string
 }
 
+/** Last known health of a connection (see ConnectionService.GetConnectionStatus). */
+export interface ConnectionStatus {
+  connection_id: string
+  ok: boolean
+  message: string
+  checked_at: string
+}
+
 /** Plugin metadata discovered by the backend plugin manager. */
 export interface PluginInfo {
   id: string
@@ -26,6 +37,7 @@ export interface PluginInfo {
   contact?: string
   metadata?: Record<string, string>
   settings?: Record<string, string>
+  example_queries?: string[]
   lastError?: string
 }
 
@@ -35,6 +47,8 @@ export interface TreeAction {
   title?: string
   query?: string
   new_tab?: boolean
+  /** Set by plugins on actions that lose data; the UI confirms before running. */
+  destructive?: boolean
   fields?: TreeActionField[]
 }
 
@@ -53,6 +67,8 @@ export interface TreeNode {
   node_type: string | number
   children?: TreeNode[]
   actions?: TreeAction[]
+  /** Set by the plugin for nodes to show open on first load. */
+  expanded?: boolean
   /** Injected by tagWithConnId — the owning connection ID. */
   _connectionId?: string
 }
@@ -97,6 +113,10 @@ export interface Tab {
 export interface SqlResult {
   columns?: Column[]
   rows?: Row[]
+  /** Set for INSERT/UPDATE/DELETE; such results have no columns. */
+  rows_affected?: number
+  /** Id generated by the statement, when the driver reports one. */
+  last_insert_id?: number
 }
 
 /** A column descriptor in a SQL result. */
@@ -118,6 +138,8 @@ export interface DocumentResult {
 /** Key-value result payload. */
 export interface KeyValueResult {
   data?: Record<string, string>
+  /** Display order of data's keys; unlisted keys follow sorted. */
+  keys?: string[]
 }
 
 /** Union of possible result payloads from a plugin exec response. */
@@ -192,6 +214,16 @@ export interface SavedCredential {
   values?: Record<string, string>
 }
 
+/** Outcome of a plugin TestConnection call. */
+export interface TestConnectionResponse {
+  ok: boolean
+  message?: string
+  /** Ping round trip, set on success by plugins that measure it. */
+  latency_ms?: number
+  /** Best-effort server details such as "version" and "edition". */
+  server_info?: Record<string, string>
+}
+
 /** Parameters passed to the row editor mutation handler. */
 export interface MutationParams {
   operation: string
@@ -206,9 +238,11 @@ export const EventNames = {
   ConnectionCreated: 'connection:created',
   ConnectionUpdated: 'connection:updated',
   ConnectionDeleted: 'connection:deleted',
+  ConnectionsDeleted: 'connection:deleted-batch',
   MenuLogsToggled: 'menu:logs-toggled',
   ConnectionsWindowClosed: 'connections-window:closed',
   EditConnectionWindowOpened: 'edit-connection-window:opened',
   EditConnectionWindowClosed: 'edit-connection-window:closed',
   PluginsReady: 'plugins:ready',
+  TreeInvalidate: 'tree:invalidate',
 } as const
@@ -44,6 +44,14 @@ func main() {
 		log.Fatalf("failed to initialize connection service: %v", err)
 	}
 	mgr := pluginmgr.New()
+	mgr.SetCredentialBackend(connSvc.CredentialBackend())
+	mgr.SetConnectionSource(connSvc)
+	logSvc := services.NewLogService()
+	// Keep a copy of the application log on disk for bug reports; the
+	// in-memory buffer and app:log events work without it.
+	if _, err := logSvc.SetFileLogging("", services.LogLevelInfo); err != nil {
+		log.Printf("file logging disabled: %v", err)
+	}
 
 	// Create a new Wails application by providing the necessary options.
 	// Variables 'Name' and 'Description' are for application metadata.
@@ -57,6 +65,7 @@ func main() {
 		Services: []application.Service{
 			application.NewService(connSvc),
 			application.NewService(mgr),
+			application.NewService(logSvc),
 			application.NewService(app), // Bind the App struct to allow frontend to call its methods (e.g. ShowConnections)
 		},
 		// Expose App methods (e.g. ShowConnections) to the frontend via bindings.
@@ -66,6 +75,14 @@ func main() {
 		Mac: application.MacOptions{
 			ApplicationShouldTerminateAfterLastWindowClosed: true,
 		},
+		// The connection and plugin services expose Shutdown rather than
+		// Wails' ServiceShutdown hook, so Wails never calls them itself;
+		// release their databases and the log file explicitly on quit.
+		OnShutdown: func() {
+			mgr.Shutdown()
+			connSvc.Shutdown()
+			logSvc.DisableFileLogging()
+		},
 	})
 
 	// Inject the Wails app reference so services can emit log events to the frontend.
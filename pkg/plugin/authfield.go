@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseMultiValue decodes the credential blob value of an AuthFieldMultiSelect
+// field.  The host stores a JSON array of strings; a comma-separated list is
+// accepted too for hand-written blobs and legacy single values.  Entries are
+// trimmed and empty ones dropped, so "" yields no values.
+func ParseMultiValue(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	var raw []string
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &raw); err != nil {
+			return nil, fmt.Errorf("invalid multi-value %q: %w", value, err)
+		}
+	} else {
+		raw = strings.Split(value, ",")
+	}
+	var out []string
+	for _, v := range raw {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// FormatMultiValue encodes values the way the host stores an
+// AuthFieldMultiSelect value: as a JSON array of strings.
+func FormatMultiValue(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+	b, _ := json.Marshal(values)
+	return string(b)
+}
+
+// ValidateMultiValue parses value for the multi-select field f and checks it
+// against the field: a required field needs at least one value and, when the
+// field declares options, every value must be one of them.
+func ValidateMultiValue(f *AuthField, value string) ([]string, error) {
+	vals, err := ParseMultiValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if f.GetRequired() && len(vals) == 0 {
+		return nil, fmt.Errorf("%s: at least one value is required", f.GetName())
+	}
+	if opts := f.GetOptions(); len(opts) > 0 {
+		allowed := make(map[string]bool, len(opts))
+		for _, o := range opts {
+			allowed[o] = true
+		}
+		for _, v := range vals {
+			if !allowed[v] {
+				return nil, fmt.Errorf("%s: %q is not one of %s", f.GetName(), v, strings.Join(opts, ", "))
+			}
+		}
+	}
+	return vals, nil
+}
@@ -0,0 +1,71 @@
+package plugin_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestParseMultiValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"  ", nil, false},
+		{`["a","b"]`, []string{"a", "b"}, false},
+		{`[" a ", "", "b"]`, []string{"a", "b"}, false},
+		{`[]`, nil, false},
+		{"a, b ,,c", []string{"a", "b", "c"}, false},
+		{"single", []string{"single"}, false},
+		{`["a",`, nil, true},
+		{`[1, 2]`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := plugin.ParseMultiValue(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMultiValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMultiValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMultiValueRoundTrip(t *testing.T) {
+	if got := plugin.FormatMultiValue(nil); got != "[]" {
+		t.Errorf("FormatMultiValue(nil) = %q, want []", got)
+	}
+	in := []string{"host-1:26379", "host,2:26379"}
+	got, err := plugin.ParseMultiValue(plugin.FormatMultiValue(in))
+	if err != nil || !reflect.DeepEqual(got, in) {
+		t.Errorf("round trip = %q, %v; want %q", got, err, in)
+	}
+}
+
+func TestValidateMultiValue(t *testing.T) {
+	features := &plugin.AuthField{Type: plugin.AuthFieldMultiSelect, Name: "features", Options: []string{"ssl", "compress"}}
+	if got, err := plugin.ValidateMultiValue(features, `["ssl","compress"]`); err != nil || len(got) != 2 {
+		t.Errorf("valid options = %q, %v", got, err)
+	}
+	if _, err := plugin.ValidateMultiValue(features, `["ssl","gzip"]`); err == nil {
+		t.Error("expected an error for a value outside the options")
+	}
+	if got, err := plugin.ValidateMultiValue(features, ""); err != nil || got != nil {
+		t.Errorf("optional empty value = %q, %v", got, err)
+	}
+
+	hosts := &plugin.AuthField{Type: plugin.AuthFieldMultiSelect, Name: "hosts", Required: true}
+	if _, err := plugin.ValidateMultiValue(hosts, "[]"); err == nil {
+		t.Error("expected an error for a required field without values")
+	}
+	if got, err := plugin.ValidateMultiValue(hosts, "a:1,b:2"); err != nil || len(got) != 2 {
+		t.Errorf("free-form values = %q, %v", got, err)
+	}
+	if _, err := plugin.ValidateMultiValue(hosts, `["a"`); err == nil {
+		t.Error("expected the parse error")
+	}
+}
@@ -27,3 +27,59 @@ func ParseCredentialBlob(connection map[string]string) (CredentialBlob, error) {
 	}
 	return cb, nil
 }
+
+// WithCredentialForm returns a copy of connection whose credential_blob names
+// form instead of its recorded form.  Plugins call it from TestConnection to
+// honour TestConnectionRequest.Form.  An empty form, a missing blob or an
+// unparsable blob leave connection unchanged (and uncopied) so the usual
+// validation errors still surface.
+func WithCredentialForm(connection map[string]string, form string) map[string]string {
+	if form == "" {
+		return connection
+	}
+	cb, err := ParseCredentialBlob(connection)
+	if err != nil || cb.Form == form {
+		return connection
+	}
+	cb.Form = form
+	b, err := json.Marshal(cb)
+	if err != nil {
+		return connection
+	}
+	out := make(map[string]string, len(connection))
+	for k, v := range connection {
+		out[k] = v
+	}
+	out["credential_blob"] = string(b)
+	return out
+}
+
+// ParseConnection returns the auth form and field values described by a
+// connection map, so plugins share one set of rules for reading it.  Values
+// come from the credential_blob JSON when present; every other non-empty key
+// of the map (e.g. a "database" injected while browsing the connection tree,
+// or a legacy flat "dsn") overrides the blob value of the same name.  A map
+// without a blob is treated as flat values with an empty form.  An error is
+// returned for a malformed blob or a map with no parameters at all.
+func ParseConnection(connection map[string]string) (form string, values map[string]string, err error) {
+	values = map[string]string{}
+	if blob := connection["credential_blob"]; blob != "" {
+		cb, err := ParseCredentialBlob(connection)
+		if err != nil {
+			return "", nil, err
+		}
+		form = cb.Form
+		for k, v := range cb.Values {
+			values[k] = v
+		}
+	}
+	for k, v := range connection {
+		if k != "credential_blob" && v != "" {
+			values[k] = v
+		}
+	}
+	if form == "" && len(values) == 0 {
+		return "", nil, fmt.Errorf("missing credential_blob in connection parameters")
+	}
+	return form, values, nil
+}
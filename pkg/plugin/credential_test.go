@@ -0,0 +1,99 @@
+package plugin_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestParseConnection(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection map[string]string
+		wantForm   string
+		wantValues map[string]string
+		wantErr    string
+	}{
+		{
+			name:       "blob",
+			connection: map[string]string{"credential_blob": `{"form":"basic","values":{"host":"db","port":"5432"}}`},
+			wantForm:   "basic",
+			wantValues: map[string]string{"host": "db", "port": "5432"},
+		},
+		{
+			name:       "flat",
+			connection: map[string]string{"dsn": "user:pass@tcp(db:3306)/app"},
+			wantValues: map[string]string{"dsn": "user:pass@tcp(db:3306)/app"},
+		},
+		{
+			name: "flat keys override blob",
+			connection: map[string]string{
+				"credential_blob": `{"form":"basic","values":{"host":"db","database":"app"}}`,
+				"database":        "other",
+			},
+			wantForm:   "basic",
+			wantValues: map[string]string{"host": "db", "database": "other"},
+		},
+		{
+			name: "empty flat value keeps blob value",
+			connection: map[string]string{
+				"credential_blob": `{"form":"basic","values":{"database":"app"}}`,
+				"database":        "",
+			},
+			wantForm:   "basic",
+			wantValues: map[string]string{"database": "app"},
+		},
+		{
+			name:       "blob without values",
+			connection: map[string]string{"credential_blob": `{"form":"dsn"}`},
+			wantForm:   "dsn",
+			wantValues: map[string]string{},
+		},
+		{
+			name:       "empty blob falls back to flat",
+			connection: map[string]string{"credential_blob": "", "file": "/tmp/app.db"},
+			wantValues: map[string]string{"file": "/tmp/app.db"},
+		},
+		{
+			name:       "malformed blob",
+			connection: map[string]string{"credential_blob": `{"form":`, "host": "db"},
+			wantErr:    "invalid credential_blob JSON",
+		},
+		{
+			name:       "values of the wrong type",
+			connection: map[string]string{"credential_blob": `{"form":"basic","values":{"port":5432}}`},
+			wantErr:    "invalid credential_blob JSON",
+		},
+		{
+			name:    "nil map",
+			wantErr: "missing credential_blob",
+		},
+		{
+			name:       "only empty values",
+			connection: map[string]string{"host": ""},
+			wantErr:    "missing credential_blob",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, values, err := plugin.ParseConnection(tt.connection)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseConnection: %v", err)
+			}
+			if form != tt.wantForm {
+				t.Errorf("form = %q, want %q", form, tt.wantForm)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("values = %v, want %v", values, tt.wantValues)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DocumentResult documents are google.protobuf.Struct values, so every number
+// travels as a float64 and driver-specific types (ObjectId, dates, 64-bit
+// integers) have no native representation.  Document plugins therefore encode
+// such values as MongoDB extended-JSON wrappers — {"$oid": ...},
+// {"$date": ...}, {"$numberLong": ...} — which survive the round trip
+// unchanged.  The helpers below build those wrappers and render a result as
+// canonical JSON for export.
+
+// maxSafeInteger is the largest integer a float64 represents exactly (2^53).
+const maxSafeInteger = 1 << 53
+
+// ObjectIDValue wraps a hex object id as {"$oid": hex}.
+func ObjectIDValue(hex string) map[string]interface{} {
+	return map[string]interface{}{"$oid": hex}
+}
+
+// DateValue wraps t as {"$date": "<RFC 3339, UTC, millisecond precision>"}.
+func DateValue(t time.Time) map[string]interface{} {
+	return map[string]interface{}{"$date": t.UTC().Format("2006-01-02T15:04:05.000Z07:00")}
+}
+
+// Int64Value returns n as a plain number when a float64 holds it exactly and
+// as {"$numberLong": "n"} otherwise, so large integers are never rounded.
+func Int64Value(n int64) interface{} {
+	if n > -maxSafeInteger && n < maxSafeInteger {
+		return float64(n)
+	}
+	return map[string]interface{}{"$numberLong": strconv.FormatInt(n, 10)}
+}
+
+// CanonicalDocumentJSON renders res as a JSON array with one object per
+// document.  Object keys are sorted, extended-JSON wrappers are preserved
+// verbatim, integral numbers are written without a fractional part and the
+// output is compact, so equal results always produce identical bytes.  A nil
+// result renders as "[]".
+func CanonicalDocumentJSON(res *DocumentResult) ([]byte, error) {
+	docs := make([]interface{}, 0, len(res.GetDocuments()))
+	for _, d := range res.GetDocuments() {
+		docs = append(docs, canonicalValue(d.AsMap()))
+	}
+	return json.Marshal(docs)
+}
+
+// canonicalValue rewrites integral float64 values as json.Number so they are
+// never rendered in exponent form (encoding/json uses 1e+21 style above 1e21).
+// Maps need no work: encoding/json already sorts their keys.
+func canonicalValue(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vv {
+			vv[k] = canonicalValue(e)
+		}
+		return vv
+	case []interface{}:
+		for i, e := range vv {
+			vv[i] = canonicalValue(e)
+		}
+		return vv
+	case float64:
+		if vv == math.Trunc(vv) && !math.IsInf(vv, 0) && math.Abs(vv) < 1e21 {
+			return json.Number(strconv.FormatFloat(vv, 'f', -1, 64))
+		}
+		return vv
+	}
+	return v
+}
+
+// DocumentsToSqlResult projects heterogeneous documents onto a table so they
+// render in the same grid as SQL results.  The columns are the union of the
+// documents' fields, sorted by name with "_id" always first.  Nested objects
+// are flattened one level into dotted columns ("address.city"); anything
+// deeper, arrays and extended-JSON wrappers such as {"$date": ...} stay whole
+// and are rendered as compact JSON.  An ObjectId "_id" is the exception: it
+// is shown as its plain hex string.  Fields a document lacks are left empty.
+func DocumentsToSqlResult(docs []*structpb.Struct) *SqlResult {
+	flat := make([]map[string]interface{}, len(docs))
+	seen := map[string]bool{}
+	var names []string
+	for i, d := range docs {
+		flat[i] = flattenDocument(d.AsMap())
+		for k := range flat[i] {
+			if !seen[k] {
+				seen[k] = true
+				names = append(names, k)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == idField) != (names[j] == idField) {
+			return names[i] == idField
+		}
+		return names[i] < names[j]
+	})
+
+	res := &SqlResult{Columns: make([]*Column, 0, len(names)), Rows: make([]*Row, 0, len(docs))}
+	for _, n := range names {
+		res.Columns = append(res.Columns, &Column{Name: n})
+	}
+	for _, f := range flat {
+		vals := make([]string, len(names))
+		for i, n := range names {
+			if v, ok := f[n]; ok {
+				if n == idField {
+					v = unwrapObjectID(v)
+				}
+				vals[i] = documentCell(v)
+			}
+		}
+		res.Rows = append(res.Rows, &Row{Values: vals})
+	}
+	return res
+}
+
+// idField is the document primary key, shown as the first table column.
+const idField = "_id"
+
+// unwrapObjectID returns the hex string of an {"$oid": hex} wrapper and any
+// other value unchanged.
+func unwrapObjectID(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+		if hex, ok := m["$oid"].(string); ok {
+			return hex
+		}
+	}
+	return v
+}
+
+// flattenDocument lifts the fields of nested objects one level up as
+// "parent.child" keys.  Extended-JSON wrappers and empty objects are kept as
+// values.
+func flattenDocument(doc map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		nested, ok := v.(map[string]interface{})
+		if !ok || len(nested) == 0 || isExtendedJSON(nested) {
+			out[k] = v
+			continue
+		}
+		for nk, nv := range nested {
+			out[k+"."+nk] = nv
+		}
+	}
+	return out
+}
+
+// isExtendedJSON reports whether m is an extended-JSON wrapper such as
+// {"$oid": ...} or {"$date": ...}, i.e. every key starts with '$'.
+func isExtendedJSON(m map[string]interface{}) bool {
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// documentCell renders a decoded document value for a table cell.
+func documentCell(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return vv
+	}
+	b, err := json.Marshal(canonicalValue(v))
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,133 @@
+package plugin_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestCanonicalDocumentJSON(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("ICT", 7*3600))
+	doc, err := structpb.NewStruct(map[string]interface{}{
+		"name":    "widget",
+		"_id":     plugin.ObjectIDValue("65e1f0c2a1b2c3d4e5f60718"),
+		"created": plugin.DateValue(created),
+		"count":   plugin.Int64Value(42),
+		"big":     plugin.Int64Value(9007199254740993),
+		"tags":    []interface{}{"a", 1.5},
+	})
+	if err != nil {
+		t.Fatalf("NewStruct: %v", err)
+	}
+	res := &plugin.DocumentResult{Documents: []*structpb.Struct{doc}}
+
+	got, err := plugin.CanonicalDocumentJSON(res)
+	if err != nil {
+		t.Fatalf("CanonicalDocumentJSON: %v", err)
+	}
+	want := `[{"_id":{"$oid":"65e1f0c2a1b2c3d4e5f60718"},` +
+		`"big":{"$numberLong":"9007199254740993"},` +
+		`"count":42,` +
+		`"created":{"$date":"2024-03-01T05:30:00.000Z"},` +
+		`"name":"widget",` +
+		`"tags":["a",1.5]}]`
+	if string(got) != want {
+		t.Errorf("canonical JSON mismatch\n got: %s\nwant: %s", got, want)
+	}
+
+	again, _ := plugin.CanonicalDocumentJSON(res)
+	if string(again) != string(got) {
+		t.Error("canonical output is not stable across calls")
+	}
+}
+
+func TestCanonicalDocumentJSONEmpty(t *testing.T) {
+	got, err := plugin.CanonicalDocumentJSON(nil)
+	if err != nil || string(got) != "[]" {
+		t.Errorf("CanonicalDocumentJSON(nil) = %q, %v", got, err)
+	}
+}
+
+func TestDocumentsToSqlResult(t *testing.T) {
+	d1, _ := structpb.NewStruct(map[string]interface{}{
+		"_id":  plugin.ObjectIDValue("65e1f0c2a1b2c3d4e5f60718"),
+		"name": "ada",
+		"address": map[string]interface{}{
+			"city": "London",
+			"geo":  map[string]interface{}{"lat": 51.5},
+		},
+	})
+	d2, _ := structpb.NewStruct(map[string]interface{}{
+		"name":    "grace",
+		"age":     85.0,
+		"tags":    []interface{}{"navy", "cobol"},
+		"address": map[string]interface{}{"zip": "10001"},
+		"empty":   map[string]interface{}{},
+	})
+
+	res := plugin.DocumentsToSqlResult([]*structpb.Struct{d1, d2})
+
+	var cols []string
+	for _, c := range res.Columns {
+		cols = append(cols, c.Name)
+	}
+	wantCols := []string{"_id", "address.city", "address.geo", "address.zip", "age", "empty", "name", "tags"}
+	if !reflect.DeepEqual(cols, wantCols) {
+		t.Fatalf("columns = %v, want %v", cols, wantCols)
+	}
+
+	wantRows := [][]string{
+		{"65e1f0c2a1b2c3d4e5f60718", "London", `{"lat":51.5}`, "", "", "", "ada", ""},
+		{"", "", "", "10001", "85", "{}", "grace", `["navy","cobol"]`},
+	}
+	if len(res.Rows) != len(wantRows) {
+		t.Fatalf("got %d rows, want %d", len(res.Rows), len(wantRows))
+	}
+	for i, r := range res.Rows {
+		if !reflect.DeepEqual(r.Values, wantRows[i]) {
+			t.Errorf("row %d = %q, want %q", i, r.Values, wantRows[i])
+		}
+	}
+}
+
+func TestDocumentsToSqlResultIDColumn(t *testing.T) {
+	d1, _ := structpb.NewStruct(map[string]interface{}{
+		"Name": "ada",
+		"_id":  plugin.ObjectIDValue("65e1f0c2a1b2c3d4e5f60718"),
+		"ref":  plugin.ObjectIDValue("65e1f0c2a1b2c3d4e5f60719"),
+	})
+	// non-ObjectId keys are rendered as usual
+	d2, _ := structpb.NewStruct(map[string]interface{}{"_id": 42.0, "Name": "grace"})
+	d3, _ := structpb.NewStruct(map[string]interface{}{"_id": "custom-key"})
+
+	res := plugin.DocumentsToSqlResult([]*structpb.Struct{d1, d2, d3})
+	var cols []string
+	for _, c := range res.Columns {
+		cols = append(cols, c.Name)
+	}
+	// "_id" leads even though "N" sorts before "_"
+	if want := []string{"_id", "Name", "ref"}; !reflect.DeepEqual(cols, want) {
+		t.Fatalf("columns = %v, want %v", cols, want)
+	}
+	var ids []string
+	for _, r := range res.Rows {
+		ids = append(ids, r.Values[0])
+	}
+	if want := []string{"65e1f0c2a1b2c3d4e5f60718", "42", "custom-key"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("_id cells = %q, want %q", ids, want)
+	}
+	// only _id is unwrapped; other ObjectId fields keep the wrapper
+	if got := res.Rows[0].Values[2]; got != `{"$oid":"65e1f0c2a1b2c3d4e5f60719"}` {
+		t.Errorf("ref cell = %q", got)
+	}
+}
+
+func TestDocumentsToSqlResultEmpty(t *testing.T) {
+	res := plugin.DocumentsToSqlResult(nil)
+	if len(res.Columns) != 0 || len(res.Rows) != 0 {
+		t.Errorf("expected an empty table, got %v", res)
+	}
+}
@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+// ErrorCode classifies an ExecResponse error so the host can react to
+// specific failures instead of pattern-matching free-form messages.
+type ErrorCode = pluginpb.PluginV1_ErrorCode
+
+const (
+	ErrorCodeUnknown          = pluginpb.PluginV1_ERROR_CODE_UNKNOWN
+	ErrorCodeAuthFailed       = pluginpb.PluginV1_ERROR_CODE_AUTH_FAILED
+	ErrorCodeTimeout          = pluginpb.PluginV1_ERROR_CODE_TIMEOUT
+	ErrorCodeSyntaxError      = pluginpb.PluginV1_ERROR_CODE_SYNTAX_ERROR
+	ErrorCodeConnectionFailed = pluginpb.PluginV1_ERROR_CODE_CONNECTION_FAILED
+	ErrorCodeNotFound         = pluginpb.PluginV1_ERROR_CODE_NOT_FOUND
+	ErrorCodeUnsupported      = pluginpb.PluginV1_ERROR_CODE_UNSUPPORTED
+)
+
+// errorMessageCodes maps lower-case message fragments emitted by common
+// drivers to an ErrorCode.  Entries are checked in order, so more specific
+// fragments come first.
+var errorMessageCodes = []struct {
+	fragment string
+	code     ErrorCode
+}{
+	{"password authentication failed", ErrorCodeAuthFailed},
+	{"authentication failed", ErrorCodeAuthFailed},
+	{"access denied", ErrorCodeAuthFailed},
+	{"not authorized", ErrorCodeAuthFailed},
+	{"syntax error", ErrorCodeSyntaxError},
+	{"timeout", ErrorCodeTimeout},
+	{"timed out", ErrorCodeTimeout},
+	{"canceling statement", ErrorCodeTimeout},
+	{"connection refused", ErrorCodeConnectionFailed},
+	{"no such host", ErrorCodeConnectionFailed},
+	{"connection reset", ErrorCodeConnectionFailed},
+	{"broken pipe", ErrorCodeConnectionFailed},
+	{"bad connection", ErrorCodeConnectionFailed},
+	{"no such table", ErrorCodeNotFound},
+	{"no such column", ErrorCodeNotFound},
+	{"does not exist", ErrorCodeNotFound},
+	{"doesn't exist", ErrorCodeNotFound},
+	{"unknown database", ErrorCodeNotFound},
+	{"not supported", ErrorCodeUnsupported},
+	{"unsupported", ErrorCodeUnsupported},
+}
+
+// ClassifyError returns a best-effort ErrorCode for err using only
+// driver-agnostic information: context deadlines, net package errors and
+// well-known message fragments.  Drivers that expose structured codes (e.g.
+// SQLSTATE) should check those first and fall back to ClassifyError.
+func ClassifyError(err error) ErrorCode {
+	if err == nil {
+		return ErrorCodeUnknown
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ErrorCodeTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorCodeTimeout
+		}
+		return ErrorCodeConnectionFailed
+	}
+	msg := strings.ToLower(err.Error())
+	for _, m := range errorMessageCodes {
+		if strings.Contains(msg, m.fragment) {
+			return m.code
+		}
+	}
+	return ErrorCodeUnknown
+}
+
+// dialFailureFragments are message fragments that only appear when a
+// connection could not be established at all, so no statement was sent.
+var dialFailureFragments = []string{
+	"connection refused",
+	"no such host",
+	"dial tcp",
+	"dial udp",
+	"dial unix",
+}
+
+// IsDialFailure reports whether err happened while establishing the
+// connection (refused, unresolvable host, dial timeout), before any
+// statement reached the server.  Unlike ErrorCodeConnectionFailed, which
+// also covers connections dropped mid-statement ("connection reset",
+// "broken pipe", "bad connection"), a dial failure is always safe to retry.
+// err may be a plain errors.New of a plugin's error message.
+func IsDialFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, f := range dialFailureFragments {
+		if strings.Contains(msg, f) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package plugin_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want plugin.ErrorCode
+	}{
+		{"nil", nil, plugin.ErrorCodeUnknown},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), plugin.ErrorCodeTimeout},
+		{"cancelled", context.Canceled, plugin.ErrorCodeTimeout},
+		{"dial refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, plugin.ErrorCodeConnectionFailed},
+		{"dns", &net.DNSError{Err: "no such host", Name: "db.invalid", IsTimeout: false}, plugin.ErrorCodeConnectionFailed},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "db.invalid", IsTimeout: true}, plugin.ErrorCodeTimeout},
+		{"auth message", errors.New(`pq: password authentication failed for user "bob"`), plugin.ErrorCodeAuthFailed},
+		{"sqlite syntax", errors.New(`SQL logic error: near "SELEC": syntax error (1)`), plugin.ErrorCodeSyntaxError},
+		{"sqlite missing table", errors.New("SQL logic error: no such table: users (1)"), plugin.ErrorCodeNotFound},
+		{"unsupported", errors.New("operation not supported"), plugin.ErrorCodeUnsupported},
+		{"unclassified", errors.New("disk I/O error"), plugin.ErrorCodeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plugin.ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDialFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"dial refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"dns", &net.DNSError{Err: "no such host", Name: "db.invalid"}, true},
+		{"dial timeout message", errors.New("dial tcp 10.0.0.1:5432: i/o timeout"), true},
+		{"refused message", errors.New("failed to connect: connect: connection refused"), true},
+		{"read reset", &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, false},
+		{"broken pipe message", errors.New("write tcp 127.0.0.1:5432: broken pipe"), false},
+		{"bad connection message", errors.New("driver: bad connection"), false},
+		{"syntax", errors.New(`syntax error at or near "SELEC"`), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plugin.IsDialFailure(tt.err); got != tt.want {
+				t.Errorf("IsDialFailure(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
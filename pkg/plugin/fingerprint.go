@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// ConnectionFingerprint returns a stable key for a connection map, suitable
+// for pooling database handles across requests.  Identical parameters always
+// produce the same fingerprint regardless of map iteration order, and the
+// JSON in "credential_blob" is canonicalised so that key order inside the
+// blob does not matter either.  The result is a SHA-256 digest, so secrets
+// never appear in the key itself.
+func ConnectionFingerprint(connection map[string]string) string {
+	keys := make([]string, 0, len(connection))
+	for k := range connection {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		v := connection[k]
+		if k == "credential_blob" {
+			v = canonicalJSON(v)
+		}
+		// NUL separators keep ("ab","c") distinct from ("a","bc").
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalJSON re-encodes s with sorted object keys.  Invalid JSON is
+// returned unchanged.
+func canonicalJSON(s string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
@@ -0,0 +1,39 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestConnectionFingerprintStable(t *testing.T) {
+	a := map[string]string{
+		"credential_blob": `{"form":"basic","values":{"host":"db","user":"bob"}}`,
+		"database":        "app",
+	}
+	b := map[string]string{
+		"database":        "app",
+		"credential_blob": `{"values":{"user":"bob","host":"db"},"form":"basic"}`,
+	}
+	fa, fb := plugin.ConnectionFingerprint(a), plugin.ConnectionFingerprint(b)
+	if fa != fb {
+		t.Fatalf("identical connections produced different fingerprints: %s vs %s", fa, fb)
+	}
+	for i := 0; i < 10; i++ {
+		if got := plugin.ConnectionFingerprint(a); got != fa {
+			t.Fatalf("fingerprint changed between calls: %s vs %s", got, fa)
+		}
+	}
+
+	c := map[string]string{
+		"credential_blob": `{"form":"basic","values":{"host":"db","user":"alice"}}`,
+		"database":        "app",
+	}
+	if plugin.ConnectionFingerprint(c) == fa {
+		t.Error("different credentials produced the same fingerprint")
+	}
+	// key/value boundaries must not be ambiguous
+	if plugin.ConnectionFingerprint(map[string]string{"ab": "c"}) == plugin.ConnectionFingerprint(map[string]string{"a": "bc"}) {
+		t.Error("fingerprint does not separate keys from values")
+	}
+}
@@ -0,0 +1,146 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+// OptionFormat is the ExecRequest.Options key selecting the encoding of the
+// exec output.  It is honoured by ServeCLI, so plugins get it for free; when
+// absent each plugin answers in its native shape (SQL plugins a SqlResult
+// table, document plugins a DocumentResult).
+const OptionFormat = "format"
+
+// Values accepted for OptionFormat.
+const (
+	// FormatJSON is the ExecResponse envelope in the plugin's native result
+	// shape — the default.
+	FormatJSON = "json"
+	// FormatNDJSON replaces the envelope with newline-delimited JSON: one
+	// object per row, document or key/value entry.  Intended for export and
+	// streaming; an error is still reported through the envelope.
+	FormatNDJSON = "ndjson"
+	// FormatTable is the ExecResponse envelope with the result converted to
+	// a SqlResult so it renders in the grid whatever the driver type.
+	FormatTable = "table"
+)
+
+// RequestedFormat returns the output format asked for in options, defaulting
+// to FormatJSON.  Unknown values are returned as given so callers can reject
+// them.
+func RequestedFormat(options map[string]string) string {
+	f := strings.ToLower(strings.TrimSpace(options[OptionFormat]))
+	if f == "" {
+		return FormatJSON
+	}
+	return f
+}
+
+// TabularResult returns res with its payload converted to a SqlResult.  SQL
+// results are returned unchanged; key/value results become a key/value
+// table in KeyValueOrder; documents are projected by DocumentsToSqlResult.
+// Warnings and metadata are carried over.
+func TabularResult(res *ExecResult) *ExecResult {
+	switch {
+	case res.GetKv() != nil:
+		data := res.GetKv().GetData()
+		keys := KeyValueOrder(res.GetKv())
+		rows := make([]*Row, 0, len(keys))
+		for _, k := range keys {
+			rows = append(rows, &Row{Values: []string{k, data[k]}})
+		}
+		out := sqlExecResult([]*Column{{Name: "key"}, {Name: "value"}}, rows)
+		out.Warnings = res.GetWarnings()
+		out.Metadata = res.GetMetadata()
+		return out
+	case res.GetDocument() != nil:
+		return &ExecResult{
+			Payload:  &pluginpb.PluginV1_ExecResult_Sql{Sql: DocumentsToSqlResult(res.GetDocument().GetDocuments())},
+			Warnings: res.GetWarnings(),
+			Metadata: res.GetMetadata(),
+		}
+	}
+	return res
+}
+
+func sqlExecResult(cols []*Column, rows []*Row) *ExecResult {
+	return &ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Sql{Sql: &SqlResult{Columns: cols, Rows: rows}}}
+}
+
+// WriteNDJSON writes res as newline-delimited JSON.  SQL rows become objects
+// keyed by column name in column order, documents are written as-is and
+// key/value entries become {"key": ..., "value": ...} objects in
+// KeyValueOrder.
+func WriteNDJSON(w io.Writer, res *ExecResult) error {
+	bw := bufio.NewWriter(w)
+	writeLine := func(b []byte) {
+		bw.Write(b)
+		bw.WriteByte('\n')
+	}
+	switch {
+	case res.GetSql() != nil:
+		cols := res.GetSql().GetColumns()
+		for _, r := range res.GetSql().GetRows() {
+			line, err := sqlRowJSON(cols, r.GetValues())
+			if err != nil {
+				return err
+			}
+			writeLine(line)
+		}
+	case res.GetDocument() != nil:
+		for _, d := range res.GetDocument().GetDocuments() {
+			b, err := json.Marshal(canonicalValue(d.AsMap()))
+			if err != nil {
+				return err
+			}
+			writeLine(b)
+		}
+	case res.GetKv() != nil:
+		data := res.GetKv().GetData()
+		for _, k := range KeyValueOrder(res.GetKv()) {
+			b, err := json.Marshal(struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			}{k, data[k]})
+			if err != nil {
+				return err
+			}
+			writeLine(b)
+		}
+	}
+	return bw.Flush()
+}
+
+// sqlRowJSON encodes one row as a JSON object, preserving column order
+// (encoding a map would sort the keys).
+func sqlRowJSON(cols []*Column, values []string) ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		name := fmt.Sprintf("column_%d", i+1)
+		if i < len(cols) {
+			name = cols[i].GetName()
+		}
+		k, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,122 @@
+package plugin_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestRequestedFormat(t *testing.T) {
+	tests := []struct {
+		options map[string]string
+		want    string
+	}{
+		{nil, plugin.FormatJSON},
+		{map[string]string{"explain-query": "yes"}, plugin.FormatJSON},
+		{map[string]string{plugin.OptionFormat: " NDJSON "}, plugin.FormatNDJSON},
+		{map[string]string{plugin.OptionFormat: "table"}, plugin.FormatTable},
+		{map[string]string{plugin.OptionFormat: "csv"}, "csv"},
+	}
+	for _, tt := range tests {
+		if got := plugin.RequestedFormat(tt.options); got != tt.want {
+			t.Errorf("RequestedFormat(%v) = %q, want %q", tt.options, got, tt.want)
+		}
+	}
+}
+
+func sqlResult(cols []string, rows ...[]string) *plugin.ExecResult {
+	res := &plugin.SqlResult{}
+	for _, c := range cols {
+		res.Columns = append(res.Columns, &plugin.Column{Name: c})
+	}
+	for _, r := range rows {
+		res.Rows = append(res.Rows, &plugin.Row{Values: r})
+	}
+	return &plugin.ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Sql{Sql: res}}
+}
+
+func cellsOf(res *plugin.ExecResult) (cols []string, rows [][]string) {
+	for _, c := range res.GetSql().GetColumns() {
+		cols = append(cols, c.GetName())
+	}
+	for _, r := range res.GetSql().GetRows() {
+		rows = append(rows, r.GetValues())
+	}
+	return cols, rows
+}
+
+func TestTabularResult(t *testing.T) {
+	sql := sqlResult([]string{"id"}, []string{"1"})
+	if got := plugin.TabularResult(sql); got != sql {
+		t.Error("sql results should be returned unchanged")
+	}
+
+	kv := &plugin.ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Kv{
+		Kv: &plugin.KeyValueResult{Data: map[string]string{"b": "2", "a": "1"}},
+	}, Warnings: []string{"Warning 1265: Data truncated"}, Metadata: map[string]string{"duration_ms": "4"}}
+	table := plugin.TabularResult(kv)
+	cols, rows := cellsOf(table)
+	if !reflect.DeepEqual(cols, []string{"key", "value"}) || !reflect.DeepEqual(rows, [][]string{{"a", "1"}, {"b", "2"}}) {
+		t.Errorf("kv table = %v %v", cols, rows)
+	}
+	if !reflect.DeepEqual(table.GetWarnings(), kv.GetWarnings()) {
+		t.Errorf("warnings = %v, want them carried over", table.GetWarnings())
+	}
+	if !reflect.DeepEqual(table.GetMetadata(), kv.GetMetadata()) {
+		t.Errorf("metadata = %v, want it carried over", table.GetMetadata())
+	}
+
+	d1, _ := structpb.NewStruct(map[string]interface{}{"name": "a", "n": 1.0})
+	d2, _ := structpb.NewStruct(map[string]interface{}{"name": "b", "tags": []interface{}{"x"}})
+	docs := &plugin.ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Document{
+		Document: &plugin.DocumentResult{Documents: []*structpb.Struct{d1, d2}},
+	}}
+	cols, rows = cellsOf(plugin.TabularResult(docs))
+	if !reflect.DeepEqual(cols, []string{"n", "name", "tags"}) {
+		t.Errorf("document columns = %v", cols)
+	}
+	want := [][]string{{"1", "a", ""}, {"", "b", `["x"]`}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("document rows = %q, want %q", rows, want)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	// column order is preserved rather than sorted
+	res := sqlResult([]string{"z", "a"}, []string{"1", "x"}, []string{"2", `say "hi"`})
+	if err := plugin.WriteNDJSON(&buf, res); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	want := `{"z":"1","a":"x"}` + "\n" + `{"z":"2","a":"say \"hi\""}` + "\n"
+	if buf.String() != want {
+		t.Errorf("sql ndjson:\n got %q\nwant %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	doc, _ := structpb.NewStruct(map[string]interface{}{"b": 2.0, "a": "x"})
+	docs := &plugin.ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Document{
+		Document: &plugin.DocumentResult{Documents: []*structpb.Struct{doc}},
+	}}
+	if err := plugin.WriteNDJSON(&buf, docs); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	if buf.String() != `{"a":"x","b":2}`+"\n" {
+		t.Errorf("document ndjson = %q", buf.String())
+	}
+
+	buf.Reset()
+	kv := &plugin.ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Kv{
+		Kv: &plugin.KeyValueResult{Data: map[string]string{"k": "v"}},
+	}}
+	if err := plugin.WriteNDJSON(&buf, kv); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	if buf.String() != `{"key":"k","value":"v"}`+"\n" {
+		t.Errorf("kv ndjson = %q", buf.String())
+	}
+}
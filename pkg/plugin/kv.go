@@ -0,0 +1,47 @@
+package plugin
+
+import "sort"
+
+// NewKeyValueResult builds a KeyValueResult from alternating key, value
+// arguments, recording the keys in the order given so the UI shows fields as
+// the plugin listed them (e.g. the sections of a server INFO reply).  A
+// repeated key keeps its first position and its last value; a trailing key
+// without a value maps to "".
+func NewKeyValueResult(pairs ...string) *KeyValueResult {
+	kv := &KeyValueResult{Data: make(map[string]string, (len(pairs)+1)/2)}
+	for i := 0; i < len(pairs); i += 2 {
+		k, v := pairs[i], ""
+		if i+1 < len(pairs) {
+			v = pairs[i+1]
+		}
+		if _, dup := kv.Data[k]; !dup {
+			kv.Keys = append(kv.Keys, k)
+		}
+		kv.Data[k] = v
+	}
+	return kv
+}
+
+// KeyValueOrder returns the keys of kv.Data in display order: those listed in
+// kv.Keys first, then any others sorted.  Listed keys absent from the data
+// and duplicates are skipped, so results from older plugins that never set
+// Keys simply come back sorted.
+func KeyValueOrder(kv *KeyValueResult) []string {
+	data := kv.GetData()
+	out := make([]string, 0, len(data))
+	seen := make(map[string]bool, len(data))
+	for _, k := range kv.GetKeys() {
+		if _, ok := data[k]; ok && !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	rest := make([]string, 0, len(data)-len(out))
+	for k := range data {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(out, rest...)
+}
@@ -0,0 +1,64 @@
+package plugin_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+func TestNewKeyValueResult(t *testing.T) {
+	kv := plugin.NewKeyValueResult("server", "a", "clients", "b", "memory", "c", "clients", "d", "tail")
+	if want := []string{"server", "clients", "memory", "tail"}; !reflect.DeepEqual(kv.Keys, want) {
+		t.Errorf("keys = %v, want %v", kv.Keys, want)
+	}
+	want := map[string]string{"server": "a", "clients": "d", "memory": "c", "tail": ""}
+	if !reflect.DeepEqual(kv.Data, want) {
+		t.Errorf("data = %v, want %v", kv.Data, want)
+	}
+}
+
+func TestKeyValueOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		kv   *plugin.KeyValueResult
+		want []string
+	}{
+		{"nil", nil, []string{}},
+		{"unordered sorts", &plugin.KeyValueResult{Data: map[string]string{"b": "", "a": "", "c": ""}}, []string{"a", "b", "c"}},
+		{"listed first", &plugin.KeyValueResult{
+			Data: map[string]string{"z": "", "y": "", "b": "", "a": ""},
+			Keys: []string{"z", "missing", "y", "z"},
+		}, []string{"z", "y", "a", "b"}},
+	}
+	for _, tt := range tests {
+		if got := plugin.KeyValueOrder(tt.kv); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: KeyValueOrder = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKeyValueOrderPreservedInOutputs(t *testing.T) {
+	res := &plugin.ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Kv{
+		Kv: plugin.NewKeyValueResult("version", "7.2", "uptime", "3600", "role", "master"),
+	}}
+
+	_, rows := cellsOf(plugin.TabularResult(res))
+	want := [][]string{{"version", "7.2"}, {"uptime", "3600"}, {"role", "master"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("table rows = %v, want %v", rows, want)
+	}
+
+	var buf bytes.Buffer
+	if err := plugin.WriteNDJSON(&buf, res); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	wantNDJSON := `{"key":"version","value":"7.2"}` + "\n" +
+		`{"key":"uptime","value":"3600"}` + "\n" +
+		`{"key":"role","value":"master"}` + "\n"
+	if buf.String() != wantNDJSON {
+		t.Errorf("ndjson:\n got %q\nwant %q", buf.String(), wantNDJSON)
+	}
+}
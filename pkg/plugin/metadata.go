@@ -0,0 +1,28 @@
+package plugin
+
+import (
+	"strconv"
+	"time"
+)
+
+// Well-known ExecResult.Metadata keys.  Plugins may attach any other
+// driver-specific keys; the UI lists them all in the result details.
+const (
+	// MetadataDurationMS is the wall-clock time the statement took on the
+	// plugin side, in whole milliseconds.
+	MetadataDurationMS = "duration_ms"
+)
+
+// SetMetadata records key=value on res.Metadata, allocating the map on first
+// use.
+func SetMetadata(res *ExecResult, key, value string) {
+	if res.Metadata == nil {
+		res.Metadata = map[string]string{}
+	}
+	res.Metadata[key] = value
+}
+
+// SetDuration records the time elapsed since start under MetadataDurationMS.
+func SetDuration(res *ExecResult, start time.Time) {
+	SetMetadata(res, MetadataDurationMS, strconv.FormatInt(time.Since(start).Milliseconds(), 10))
+}
@@ -0,0 +1,24 @@
+package plugin_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestSetMetadata(t *testing.T) {
+	res := &plugin.ExecResult{}
+	plugin.SetMetadata(res, "index_used", "PRIMARY")
+	plugin.SetMetadata(res, "index_used", "idx_email")
+	if got := res.GetMetadata()["index_used"]; got != "idx_email" {
+		t.Errorf("index_used = %q, want the last value", got)
+	}
+
+	plugin.SetDuration(res, time.Now().Add(-1500*time.Millisecond))
+	ms, err := strconv.Atoi(res.GetMetadata()[plugin.MetadataDurationMS])
+	if err != nil || ms < 1500 {
+		t.Errorf("duration_ms = %q, want at least 1500", res.GetMetadata()[plugin.MetadataDurationMS])
+	}
+}
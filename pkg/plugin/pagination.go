@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApplyLimitOffset appends `LIMIT <limit> OFFSET <offset>` to a SQL query so
+// SQL plugins honour ExecRequest.Limit/Offset consistently.  The query is
+// returned unchanged when limit <= 0, when it is not a SELECT/WITH statement
+// (EXPLAIN, DML and DDL are never paginated), or when it already has a
+// top-level LIMIT clause; a LIMIT inside a subquery or CTE does not count.
+// Trailing comments and semicolons are dropped first so the clause is not
+// swallowed by a "-- ..." comment.  The LIMIT ... OFFSET form is understood
+// by PostgreSQL, MySQL and SQLite alike.
+func ApplyLimitOffset(query string, limit, offset int64) string {
+	if limit <= 0 {
+		return query
+	}
+	trimmed := trimStatementTail(strings.TrimSpace(query))
+	upper := strings.ToUpper(trimmed)
+	if !strings.HasPrefix(upper, "SELECT") && !strings.HasPrefix(upper, "WITH") {
+		return query
+	}
+	if hasTopLevelLimit(trimmed) {
+		return query
+	}
+	if offset > 0 {
+		return fmt.Sprintf("%s LIMIT %d OFFSET %d", trimmed, limit, offset)
+	}
+	return fmt.Sprintf("%s LIMIT %d", trimmed, limit)
+}
+
+// trimStatementTail drops the whitespace, semicolons and comments that
+// follow the last token of query.  Comment markers inside string literals
+// and quoted identifiers are left alone.
+func trimStatementTail(query string) string {
+	end := 0
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(query, i)
+			end = i
+		case strings.HasPrefix(query[i:], "--") || strings.HasPrefix(query[i:], "/*"):
+			i = skipComment(query, i)
+		case c == ';' || c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f':
+			i++
+		default:
+			i++
+			end = i
+		}
+	}
+	return query[:end]
+}
+
+// hasTopLevelLimit reports whether query has a LIMIT clause outside any
+// parentheses, ignoring the word inside string literals, quoted identifiers
+// and comments.
+func hasTopLevelLimit(query string) bool {
+	depth := 0
+	for i := 0; ; {
+		tok, next := nextToken(query, i)
+		switch tok {
+		case "":
+			return false
+		case "(":
+			depth++
+		case ")":
+			depth--
+		default:
+			if depth <= 0 && strings.EqualFold(tok, "LIMIT") {
+				return true
+			}
+		}
+		i = next
+	}
+}
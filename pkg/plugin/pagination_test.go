@@ -0,0 +1,50 @@
+package plugin_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestApplyLimitOffset(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		limit, offset int64
+		want          string
+	}{
+		{"no limit", "SELECT * FROM t", 0, 20, "SELECT * FROM t"},
+		{"limit only", "SELECT * FROM t;", 50, 0, "SELECT * FROM t LIMIT 50"},
+		{"limit and offset", "select * from t", 50, 100, "select * from t LIMIT 50 OFFSET 100"},
+		{"cte", "WITH x AS (SELECT 1) SELECT * FROM x", 10, 0, "WITH x AS (SELECT 1) SELECT * FROM x LIMIT 10"},
+		{"existing limit", "SELECT * FROM t limit 5", 50, 0, "SELECT * FROM t limit 5"},
+		{"trailing comment", "SELECT * FROM t -- all rows", 50, 0, "SELECT * FROM t LIMIT 50"},
+		{"trailing block comment", "SELECT * FROM t; /* done */", 50, 0, "SELECT * FROM t LIMIT 50"},
+		{"comment marker in literal", "SELECT '--' AS x", 50, 0, "SELECT '--' AS x LIMIT 50"},
+		{"subquery limit", "SELECT * FROM (SELECT * FROM t LIMIT 5) s", 50, 0, "SELECT * FROM (SELECT * FROM t LIMIT 5) s LIMIT 50"},
+		{"limit in literal", "SELECT * FROM t WHERE note = 'limit 5'", 50, 0, "SELECT * FROM t WHERE note = 'limit 5' LIMIT 50"},
+		{"dml", "DELETE FROM t", 50, 0, "DELETE FROM t"},
+		{"explain", "EXPLAIN SELECT * FROM t", 50, 0, "EXPLAIN SELECT * FROM t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plugin.ApplyLimitOffset(tt.query, tt.limit, tt.offset); got != tt.want {
+				t.Errorf("ApplyLimitOffset(%q, %d, %d) = %q, want %q", tt.query, tt.limit, tt.offset, got, tt.want)
+			}
+		})
+	}
+}
+
+// The host encodes requests with encoding/json; plugins decode them into the
+// generated ExecRequest.  Offset and Limit must survive that round trip.
+func TestExecRequestPaginationRoundTrip(t *testing.T) {
+	in := []byte(`{"connection":{"dsn":"x"},"query":"SELECT 1","offset":40,"limit":20}`)
+	var req plugin.ExecRequest
+	if err := json.Unmarshal(in, &req); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if req.GetOffset() != 40 || req.GetLimit() != 20 {
+		t.Fatalf("offset/limit = %d/%d, want 40/20", req.GetOffset(), req.GetLimit())
+	}
+}
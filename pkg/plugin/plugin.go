@@ -1,11 +1,14 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
 	"unicode/utf8"
 
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
@@ -62,6 +65,9 @@ type Row = pluginpb.PluginV1_Row
 
 type DocumentResult = pluginpb.PluginV1_DocumentResult
 
+// DocumentBatch is one frame of a streamed document exec; see stream.go.
+type DocumentBatch = pluginpb.PluginV1_DocumentBatch
+
 type KeyValueResult = pluginpb.PluginV1_KeyValueResult
 
 // MutateRow aliases – request, response, and operation enum.
@@ -110,15 +116,37 @@ type DescribeSchemaRequest = pluginpb.PluginV1_DescribeSchemaRequest
 type TestConnectionRequest = pluginpb.PluginV1_TestConnectionRequest
 type TestConnectionResponse = pluginpb.PluginV1_TestConnectionResponse
 
+// ValidateRequest / ValidateResponse back the optional `validate` command,
+// which parses or plans a query without executing it.
+type ValidateRequest = pluginpb.PluginV1_ValidateRequest
+type ValidateResponse = pluginpb.PluginV1_ValidateResponse
+
+// GenerateDDLRequest / GenerateDDLResponse back the optional `ddl` command,
+// which returns the DDL recreating a tree node's object.
+type GenerateDDLRequest = pluginpb.PluginV1_GenerateDDLRequest
+type GenerateDDLResponse = pluginpb.PluginV1_GenerateDDLResponse
+
+// SettingsRequest / SettingsResponse back the optional `settings` command,
+// which declares the plugin's configurable options; see settings.go.
+type SettingsRequest = pluginpb.PluginV1_SettingsRequest
+type SettingsResponse = pluginpb.PluginV1_SettingsResponse
+type Setting = pluginpb.PluginV1_Setting
+
+// Protocol is the handshake marker ServeCLI writes into InfoResponse.Protocol.
+// The host refuses to register an executable whose `info` output lacks it, so
+// stray binaries in the plugins directory are never run as drivers.
+const Protocol = "querybox.plugin.v1"
+
 const (
 	TypeDriver DriverType = pluginpb.PluginV1_DRIVER
 
-	AuthFieldText     = pluginpb.PluginV1_AuthField_TEXT
-	AuthFieldNumber   = pluginpb.PluginV1_AuthField_NUMBER
-	AuthFieldPassword = pluginpb.PluginV1_AuthField_PASSWORD
-	AuthFieldSelect   = pluginpb.PluginV1_AuthField_SELECT
-	AuthFieldCheckbox = pluginpb.PluginV1_AuthField_CHECKBOX
-	AuthFieldFilePath = pluginpb.PluginV1_AuthField_FILE_PATH
+	AuthFieldText        = pluginpb.PluginV1_AuthField_TEXT
+	AuthFieldNumber      = pluginpb.PluginV1_AuthField_NUMBER
+	AuthFieldPassword    = pluginpb.PluginV1_AuthField_PASSWORD
+	AuthFieldSelect      = pluginpb.PluginV1_AuthField_SELECT
+	AuthFieldCheckbox    = pluginpb.PluginV1_AuthField_CHECKBOX
+	AuthFieldFilePath    = pluginpb.PluginV1_AuthField_FILE_PATH
+	AuthFieldMultiSelect = pluginpb.PluginV1_AuthField_MULTISELECT
 
 	// common action types for ConnectionTree nodes.  Plugins should use
 	// these constants rather than hardcoding strings to avoid typos and to
@@ -155,7 +183,9 @@ const (
 // invokes the corresponding RPC-style methods on the provided server object.
 //
 // Supported commands are identical to the previous Go-only helper but now use
-// protojson for marshalling so communication is language-agnostic.
+// protojson for marshalling so communication is language-agnostic.  Every
+// method receives a context that is cancelled when the process gets SIGINT
+// or SIGTERM.
 func ServeCLI(s pluginpb.PluginServiceServer) {
 	args := os.Args[1:]
 	if len(args) == 0 {
@@ -163,71 +193,108 @@ func ServeCLI(s pluginpb.PluginServiceServer) {
 		os.Exit(2)
 	}
 
+	// SIGINT/SIGTERM from the host (a Stop in the UI, a timeout) cancel the
+	// request context so a plugin can stop its statement cleanly, e.g. with a
+	// server-side cancel, before the host falls back to killing the process.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	switch args[0] {
 	case "info":
-		info, err := s.Info(context.Background(), &pluginpb.PluginV1_InfoRequest{})
+		info, err := s.Info(ctx, &pluginpb.PluginV1_InfoRequest{})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: info error: %v\n", err)
-			os.Exit(1)
+			exitWithError("info error: %v", err)
 		}
+		info.Protocol = Protocol
 		b, _ := protojson.Marshal(info)
 		_, _ = os.Stdout.Write(b)
 	case "exec":
 		in, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: failed to read stdin: %v\n", err)
-			os.Exit(1)
+			exitWithError("failed to read stdin: %v", err)
 		}
 		var req pluginpb.PluginV1_ExecRequest
 		if err := json.Unmarshal(in, &req); err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: invalid request json: %v\n", err)
-			os.Exit(1)
+			exitWithError("invalid request json: %v", err)
 		}
-		res, err := s.Exec(context.Background(), &req)
+		if RequestedFormat(req.Options) == FormatDocumentBatches {
+			if err := serveDocumentBatches(ctx, s, &req, os.Stdout); err != nil {
+				exitWithError("document batch write error: %v", err)
+			}
+			return
+		}
+		res, err := s.Exec(ctx, &req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: exec error: %v\n", err)
-			os.Exit(1)
+			exitWithError("exec error: %v", err)
+		}
+		// shape the output according to the requested format; errors always
+		// travel in the envelope so the host can report them.
+		switch format := RequestedFormat(req.Options); {
+		case res.GetError() != "" || format == FormatJSON:
+		case format == FormatTable:
+			res.Result = TabularResult(res.GetResult())
+		case format == FormatNDJSON:
+			if err := WriteNDJSON(os.Stdout, res.GetResult()); err != nil {
+				exitWithError("ndjson encode error: %v", err)
+			}
+			return
+		default:
+			res = &pluginpb.PluginV1_ExecResponse{
+				Error:     fmt.Sprintf("unsupported output format %q", format),
+				ErrorCode: ErrorCodeUnsupported,
+			}
 		}
 		b, _ := protojson.Marshal(res)
 		_, _ = os.Stdout.Write(b)
+	case "settings":
+		res, err := s.Settings(ctx, &pluginpb.PluginV1_SettingsRequest{})
+		if err != nil || res == nil {
+			// optional: a plugin without settings declares none
+			res = &pluginpb.PluginV1_SettingsResponse{}
+		}
+		b, _ := protojson.Marshal(res)
+		_, _ = os.Stdout.Write(b)
+	case "schema":
+		b, err := json.MarshalIndent(JSONSchema(), "", "  ")
+		if err != nil {
+			exitWithError("schema error: %v", err)
+		}
+		_, _ = os.Stdout.Write(b)
 	case "authforms":
-		res, err := s.AuthForms(context.Background(), &pluginpb.PluginV1_AuthFormsRequest{})
+		res, err := s.AuthForms(ctx, &pluginpb.PluginV1_AuthFormsRequest{})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: authforms error: %v\n", err)
-			os.Exit(1)
+			exitWithError("authforms error: %v", err)
 		}
 		b, _ := protojson.Marshal(res)
 		_, _ = os.Stdout.Write(b)
 	case "connection-tree", "tree":
 		in, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: failed to read stdin: %v\n", err)
-			os.Exit(1)
+			exitWithError("failed to read stdin: %v", err)
 		}
 		var req pluginpb.PluginV1_ConnectionTreeRequest
 		if err := json.Unmarshal(in, &req); err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: invalid tree request json: %v\n", err)
-			os.Exit(1)
+			exitWithError("invalid tree request json: %v", err)
 		}
-		res, err := s.ConnectionTree(context.Background(), &req)
+		res, err := s.ConnectionTree(ctx, &req)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: connection-tree error: %v\n", err)
-			os.Exit(1)
+			exitWithError("connection-tree error: %v", err)
+		}
+		if res != nil {
+			res.Nodes = PruneConnectionTree(res.Nodes, req.GetMaxDepth())
 		}
 		b, _ := protojson.Marshal(res)
 		_, _ = os.Stdout.Write(b)
 	case "test-connection":
 		in, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: failed to read stdin: %v\n", err)
-			os.Exit(1)
+			exitWithError("failed to read stdin: %v", err)
 		}
 		var req pluginpb.PluginV1_TestConnectionRequest
 		if err := json.Unmarshal(in, &req); err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: invalid test-connection request json: %v\n", err)
-			os.Exit(1)
+			exitWithError("invalid test-connection request json: %v", err)
 		}
-		res, err := s.TestConnection(context.Background(), &req)
+		res, err := s.TestConnection(ctx, &req)
 		if err != nil {
 			res = &pluginpb.PluginV1_TestConnectionResponse{Ok: false, Message: err.Error()}
 		}
@@ -236,15 +303,13 @@ func ServeCLI(s pluginpb.PluginServiceServer) {
 	case "describe-schema":
 		in, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: failed to read stdin: %v\n", err)
-			os.Exit(1)
+			exitWithError("failed to read stdin: %v", err)
 		}
 		var req pluginpb.PluginV1_DescribeSchemaRequest
 		if err := json.Unmarshal(in, &req); err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: invalid describe-schema request json: %v\n", err)
-			os.Exit(1)
+			exitWithError("invalid describe-schema request json: %v", err)
 		}
-		res, err := s.DescribeSchema(context.Background(), &req)
+		res, err := s.DescribeSchema(ctx, &req)
 		if err != nil {
 			// older plugins may return an error; wrap in a response so the
 			// host can distinguish between a plugin-level failure and a
@@ -256,15 +321,13 @@ func ServeCLI(s pluginpb.PluginServiceServer) {
 	case "completion-fields":
 		in, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: failed to read stdin: %v\n", err)
-			os.Exit(1)
+			exitWithError("failed to read stdin: %v", err)
 		}
 		var req pluginpb.PluginV1_GetCompletionFieldsRequest
 		if err := json.Unmarshal(in, &req); err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: invalid completion-fields request json: %v\n", err)
-			os.Exit(1)
+			exitWithError("invalid completion-fields request json: %v", err)
 		}
-		res, err := s.GetCompletionFields(context.Background(), &req)
+		res, err := s.GetCompletionFields(ctx, &req)
 		if err != nil || res == nil {
 			res = &pluginpb.PluginV1_GetCompletionFieldsResponse{}
 		}
@@ -273,27 +336,89 @@ func ServeCLI(s pluginpb.PluginServiceServer) {
 case "mutate-row":
 		in, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: failed to read stdin: %v\n", err)
-			os.Exit(1)
+			exitWithError("failed to read stdin: %v", err)
 		}
 		var req pluginpb.PluginV1_MutateRowRequest
 		if err := json.Unmarshal(in, &req); err != nil {
-			fmt.Fprintf(os.Stderr, "plugin: invalid mutate-row request json: %v\n", err)
-			os.Exit(1)
+			exitWithError("invalid mutate-row request json: %v", err)
 		}
-		res, err := s.MutateRow(context.Background(), &req)
+		res, err := s.MutateRow(ctx, &req)
 		if err != nil {
 			// wrap error in response so failures are distinguishable
 			res = &pluginpb.PluginV1_MutateRowResponse{Success: false, Error: err.Error()}
 		}
 		b, _ := protojson.Marshal(res)
 		_, _ = os.Stdout.Write(b)
+	case "validate":
+		in, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			exitWithError("failed to read stdin: %v", err)
+		}
+		var req pluginpb.PluginV1_ValidateRequest
+		if err := json.Unmarshal(in, &req); err != nil {
+			exitWithError("invalid validate request json: %v", err)
+		}
+		res, err := s.Validate(ctx, &req)
+		if err != nil || res == nil {
+			// plugins embedding UnimplementedPluginServiceServer land here;
+			// report the capability as missing rather than failing the call.
+			res = &pluginpb.PluginV1_ValidateResponse{Unsupported: true, Message: "validation unsupported"}
+		}
+		b, _ := protojson.Marshal(res)
+		_, _ = os.Stdout.Write(b)
+	case "ddl":
+		in, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			exitWithError("failed to read stdin: %v", err)
+		}
+		var req pluginpb.PluginV1_GenerateDDLRequest
+		if err := json.Unmarshal(in, &req); err != nil {
+			exitWithError("invalid ddl request json: %v", err)
+		}
+		res, err := s.GenerateDDL(ctx, &req)
+		if err != nil || res == nil {
+			// as with validate, a missing implementation is reported as an
+			// unsupported capability rather than a failure.
+			res = &pluginpb.PluginV1_GenerateDDLResponse{Unsupported: true, Error: "ddl generation unsupported"}
+		}
+		b, _ := protojson.Marshal(res)
+		_, _ = os.Stdout.Write(b)
 	default:
 		usage()
 		os.Exit(2)
 	}
 }
 
+// CLIError is the envelope ServeCLI writes to stdout when a command fails
+// before a response could be produced.  The process still exits non-zero, but
+// the host can decode the plugin's own message instead of reporting an opaque
+// exit status.  The shape deliberately matches the `error` field of
+// ExecResponse so older hosts parsing an exec failure see the same message.
+type CLIError struct {
+	Error string `json:"error"`
+}
+
+// ParseCLIError extracts the message from a CLIError envelope written by a
+// failing plugin.  It reports false when out is not such an envelope.
+func ParseCLIError(out []byte) (string, bool) {
+	var e CLIError
+	if err := json.Unmarshal(bytes.TrimSpace(out), &e); err != nil || e.Error == "" {
+		return "", false
+	}
+	return e.Error, true
+}
+
+// exitWithError reports a fatal command error on stderr (for humans running
+// the plugin by hand) and as a CLIError on stdout (for the host), then exits
+// with status 1.
+func exitWithError(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "plugin: %s\n", msg)
+	b, _ := json.Marshal(CLIError{Error: msg})
+	_, _ = os.Stdout.Write(b)
+	os.Exit(1)
+}
+
 func usage() {
-	fmt.Fprintln(os.Stderr, "Usage: <plugin> info | exec | authforms | connection-tree | test-connection | describe-schema | completion-fields | mutate-row (request on stdin as JSON)")
+	fmt.Fprintln(os.Stderr, "Usage: <plugin> info | exec | authforms | connection-tree | test-connection | describe-schema | completion-fields | mutate-row | validate | ddl | settings | schema (request on stdin as JSON)")
 }
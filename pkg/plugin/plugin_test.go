@@ -1,13 +1,18 @@
 package plugin_test
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/felixdotgo/querybox/pkg/plugin"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
@@ -39,18 +44,31 @@ func TestFormatSQLValue(t *testing.T) {
         })
     }
 }
-// TestServeCLI_DescribeSchema builds a small plugin binary using the
-// package helper and exercises the "describe-schema" command.  This
-// guards against regressions when ServeCLI is modified.
-func TestServeCLI_DescribeSchema(t *testing.T) {
-    // create a temporary directory for source and executable
+
+// buildTestPlugin writes src, a main package serving a plugin with ServeCLI,
+// to a temporary directory, builds it and returns the executable's path.
+func buildTestPlugin(t *testing.T, src string) string {
+    t.Helper()
     dir := t.TempDir()
-    src := filepath.Join(dir, "main.go")
+    main := filepath.Join(dir, "main.go")
     bin := filepath.Join(dir, "testplugin")
     if runtime.GOOS == "windows" {
         bin += ".exe"
     }
+    if err := os.WriteFile(main, []byte(src), 0o644); err != nil {
+        t.Fatalf("write source: %v", err)
+    }
+    cmd := exec.Command("go", "build", "-o", bin, main)
+    if out, err := cmd.CombinedOutput(); err != nil {
+        t.Fatalf("go build failed: %v\n%s", err, string(out))
+    }
+    return bin
+}
 
+// TestServeCLI_DescribeSchema builds a small plugin binary using the
+// package helper and exercises the "describe-schema" command.  This
+// guards against regressions when ServeCLI is modified.
+func TestServeCLI_DescribeSchema(t *testing.T) {
     // source implements a minimal PluginServiceServer that returns a fixed
     // schema.  We use the same package imports as the real plugins so the
     // compiled binary is representative of production.
@@ -83,21 +101,13 @@ func main() {
 }
 `
 
-    if err := os.WriteFile(src, []byte(program), 0o644); err != nil {
-        t.Fatalf("write source: %v", err)
-    }
-
-    // build the test plugin
-    cmd := exec.Command("go", "build", "-o", bin, src)
-    if out, err := cmd.CombinedOutput(); err != nil {
-        t.Fatalf("go build failed: %v\n%s", err, string(out))
-    }
+    bin := buildTestPlugin(t, program)
 
     // prepare input JSON for describe-schema
     req := plugin.DescribeSchemaRequest{Connection: map[string]string{"foo": "bar"}}
     in, _ := json.Marshal(&req)
 
-    cmd = exec.Command(bin, "describe-schema")
+    cmd := exec.Command(bin, "describe-schema")
     cmd.Stdin = bytes.NewReader(in)
     out, err := cmd.CombinedOutput()
     if err != nil {
@@ -117,13 +127,6 @@ func main() {
 // marshals a MutateRowRequest and decodes the response. It mirrors
 // TestServeCLI_DescribeSchema above but exercises the new command.
 func TestServeCLI_MutateRow(t *testing.T) {
-    dir := t.TempDir()
-    src := filepath.Join(dir, "main.go")
-    bin := filepath.Join(dir, "testplugin")
-    if runtime.GOOS == "windows" {
-        bin += ".exe"
-    }
-
     const program = `package main
 
 import (
@@ -153,14 +156,7 @@ func main() {
 }
 `
 
-    if err := os.WriteFile(src, []byte(program), 0o644); err != nil {
-        t.Fatalf("write source: %v", err)
-    }
-
-    cmd := exec.Command("go", "build", "-o", bin, src)
-    if out, err := cmd.CombinedOutput(); err != nil {
-        t.Fatalf("go build failed: %v\n%s", err, string(out))
-    }
+    bin := buildTestPlugin(t, program)
 
     req := plugin.MutateRowRequest{
         Connection: map[string]string{"foo": "bar"},
@@ -171,7 +167,7 @@ func main() {
     }
     in, _ := json.Marshal(&req)
 
-    cmd = exec.Command(bin, "mutate-row")
+    cmd := exec.Command(bin, "mutate-row")
     cmd.Stdin = bytes.NewReader(in)
     out, err := cmd.CombinedOutput()
     if err != nil {
@@ -185,4 +181,464 @@ func main() {
     if !resp.Success {
         t.Errorf("expected success response, got %+v", resp)
     }
-}
\ No newline at end of file
+}
+
+// TestServeCLI_DDLDefaultsToUnsupported verifies that plugins which do not
+// implement GenerateDDL answer the `ddl` command with unsupported=true
+// instead of failing.
+func TestServeCLI_DDLDefaultsToUnsupported(t *testing.T) {
+    const program = `package main
+
+import (
+    "github.com/felixdotgo/querybox/pkg/plugin"
+    pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+type server struct {
+    pluginpb.UnimplementedPluginServiceServer
+}
+
+func main() {
+    plugin.ServeCLI(&server{})
+}
+`
+
+    bin := buildTestPlugin(t, program)
+
+    in, _ := json.Marshal(&plugin.GenerateDDLRequest{NodeKey: "public.users"})
+    cmd := exec.Command(bin, "ddl")
+    cmd.Stdin = bytes.NewReader(in)
+    out, err := cmd.Output()
+    if err != nil {
+        t.Fatalf("plugin exited with error: %v\nstdout:\n%s", err, string(out))
+    }
+
+    var resp plugin.GenerateDDLResponse
+    if err := protojson.Unmarshal(out, &resp); err != nil {
+        t.Fatalf("unmarshal ddl response: %v", err)
+    }
+    if !resp.Unsupported || resp.Ddl != "" {
+        t.Errorf("expected unsupported response, got %+v", &resp)
+    }
+}
+
+// TestServeCLI_ExecFormat verifies that ServeCLI honours the `format` exec
+// option without any support from the plugin itself.
+func TestServeCLI_ExecFormat(t *testing.T) {
+    const program = `package main
+
+import (
+    "context"
+
+    "github.com/felixdotgo/querybox/pkg/plugin"
+    pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+type server struct {
+    pluginpb.UnimplementedPluginServiceServer
+}
+
+func (s *server) Exec(ctx context.Context, req *pluginpb.PluginV1_ExecRequest) (*pluginpb.PluginV1_ExecResponse, error) {
+    return &pluginpb.PluginV1_ExecResponse{Result: &pluginpb.PluginV1_ExecResult{
+        Payload: &pluginpb.PluginV1_ExecResult_Kv{Kv: &pluginpb.PluginV1_KeyValueResult{Data: map[string]string{"k": "v"}}},
+    }}, nil
+}
+
+func main() {
+    plugin.ServeCLI(&server{})
+}
+`
+
+    bin := buildTestPlugin(t, program)
+
+    run := func(format string) []byte {
+        t.Helper()
+        in, _ := json.Marshal(&plugin.ExecRequest{Query: "q", Options: map[string]string{plugin.OptionFormat: format}})
+        cmd := exec.Command(bin, "exec")
+        cmd.Stdin = bytes.NewReader(in)
+        out, err := cmd.Output()
+        if err != nil {
+            t.Fatalf("exec (format %q) failed: %v\n%s", format, err, out)
+        }
+        return out
+    }
+
+    if got := string(run(plugin.FormatNDJSON)); got != `{"key":"k","value":"v"}`+"\n" {
+        t.Errorf("ndjson output = %q", got)
+    }
+
+    var resp plugin.ExecResponse
+    if err := protojson.Unmarshal(run(plugin.FormatTable), &resp); err != nil {
+        t.Fatalf("unmarshal table response: %v", err)
+    }
+    if sql := resp.GetResult().GetSql(); sql == nil || len(sql.Rows) != 1 || sql.Rows[0].Values[1] != "v" {
+        t.Errorf("expected kv converted to a table, got %v", resp.GetResult())
+    }
+
+    resp.Reset()
+    if err := protojson.Unmarshal(run("csv"), &resp); err != nil {
+        t.Fatalf("unmarshal response: %v", err)
+    }
+    if resp.ErrorCode != plugin.ErrorCodeUnsupported {
+        t.Errorf("expected unsupported error for unknown format, got %v", &resp)
+    }
+
+    // document batches frame only document results; a kv plugin gets a
+    // single final frame reporting the mismatch
+    var batches []*plugin.DocumentBatch
+    err := plugin.ReadDocumentBatches(bytes.NewReader(run(plugin.FormatDocumentBatches)), func(b *plugin.DocumentBatch) error {
+        batches = append(batches, b)
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("read document batches: %v", err)
+    }
+    if len(batches) != 1 || batches[0].ErrorCode != plugin.ErrorCodeUnsupported {
+        t.Errorf("expected one unsupported final frame, got %v", batches)
+    }
+}
+
+// TestServeCLI_Schema verifies that the `schema` subcommand prints a valid
+// JSON Schema document whose references all resolve.
+func TestServeCLI_Schema(t *testing.T) {
+    const program = `package main
+
+import (
+    "github.com/felixdotgo/querybox/pkg/plugin"
+    pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+type server struct {
+    pluginpb.UnimplementedPluginServiceServer
+}
+
+func main() {
+    plugin.ServeCLI(&server{})
+}
+`
+
+    bin := buildTestPlugin(t, program)
+
+    out, err := exec.Command(bin, "schema").Output()
+    if err != nil {
+        t.Fatalf("schema failed: %v\n%s", err, out)
+    }
+    var doc struct {
+        Schema   string `json:"$schema"`
+        Commands map[string]struct {
+            Request struct {
+                Properties map[string]json.RawMessage `json:"properties"`
+            } `json:"request"`
+            Response struct {
+                Ref string `json:"$ref"`
+            } `json:"response"`
+        } `json:"commands"`
+        Defs map[string]struct {
+            Properties map[string]json.RawMessage `json:"properties"`
+        } `json:"$defs"`
+    }
+    if err := json.Unmarshal(out, &doc); err != nil {
+        t.Fatalf("schema output is not valid JSON: %v\n%s", err, out)
+    }
+    if doc.Schema == "" || len(doc.Commands) == 0 {
+        t.Fatalf("schema document incomplete: %s", out)
+    }
+
+    // requests use proto names (decoded with encoding/json), responses the
+    // protojson camelCase names
+    if _, ok := doc.Commands["ddl"].Request.Properties["node_key"]; !ok {
+        t.Errorf("ddl request should describe node_key, got %v", doc.Commands["ddl"].Request.Properties)
+    }
+    if _, ok := doc.Defs["InfoResponse"].Properties["iconUrl"]; !ok {
+        t.Errorf("InfoResponse should describe iconUrl, got %v", doc.Defs["InfoResponse"].Properties)
+    }
+
+    for name, c := range doc.Commands {
+        def := strings.TrimPrefix(c.Response.Ref, "#/$defs/")
+        if _, ok := doc.Defs[def]; !ok {
+            t.Errorf("%s: response ref %q does not resolve", name, c.Response.Ref)
+        }
+    }
+    for _, ref := range regexp.MustCompile(`"#/\$defs/(\w+)"`).FindAllStringSubmatch(string(out), -1) {
+        if _, ok := doc.Defs[ref[1]]; !ok {
+            t.Errorf("dangling reference to %s", ref[1])
+        }
+    }
+}
+
+// TestServeCLI_Settings verifies that declared settings survive the trip
+// through the `settings` subcommand.
+func TestServeCLI_Settings(t *testing.T) {
+    const program = `package main
+
+import (
+    "context"
+
+    "github.com/felixdotgo/querybox/pkg/plugin"
+    pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+type server struct {
+    pluginpb.UnimplementedPluginServiceServer
+}
+
+func (s *server) Settings(ctx context.Context, _ *plugin.SettingsRequest) (*plugin.SettingsResponse, error) {
+    return &plugin.SettingsResponse{Settings: []*plugin.Setting{
+        {Key: "row-cap", Label: "Default row cap", Type: plugin.AuthFieldNumber, DefaultValue: "1000"},
+        {Key: "mode", Label: "Mode", Type: plugin.AuthFieldSelect, DefaultValue: "fast", Options: []string{"fast", "safe"}},
+    }}, nil
+}
+
+func main() {
+    plugin.ServeCLI(&server{})
+}
+`
+
+    bin := buildTestPlugin(t, program)
+
+    out, err := exec.Command(bin, "settings").Output()
+    if err != nil {
+        t.Fatalf("settings failed: %v\n%s", err, out)
+    }
+    var resp plugin.SettingsResponse
+    if err := protojson.Unmarshal(out, &resp); err != nil {
+        t.Fatalf("unmarshal settings: %v\n%s", err, out)
+    }
+    if len(resp.Settings) != 2 {
+        t.Fatalf("expected 2 settings, got %v", resp.Settings)
+    }
+    rowCap, mode := resp.Settings[0], resp.Settings[1]
+    if rowCap.Key != "row-cap" || rowCap.Type != plugin.AuthFieldNumber || rowCap.DefaultValue != "1000" {
+        t.Errorf("row-cap setting = %v", rowCap)
+    }
+    if mode.Type != plugin.AuthFieldSelect || len(mode.Options) != 2 {
+        t.Errorf("mode setting = %v", mode)
+    }
+    if got := plugin.SettingValue(plugin.SettingOptions(map[string]string{"row-cap": "50"}), rowCap); got != "50" {
+        t.Errorf("configured row-cap = %q, want 50", got)
+    }
+}
+
+// TestServeCLI_ConnectionTreeMaxDepth checks that max_depth from the request
+// reaches the plugin and that ServeCLI prunes levels the plugin returned
+// anyway.
+func TestServeCLI_ConnectionTreeMaxDepth(t *testing.T) {
+    // the plugin ignores the limit and echoes it in the root label
+    const program = `package main
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/felixdotgo/querybox/pkg/plugin"
+    pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+type server struct {
+    pluginpb.UnimplementedPluginServiceServer
+}
+
+func (s *server) ConnectionTree(ctx context.Context, req *plugin.ConnectionTreeRequest) (*plugin.ConnectionTreeResponse, error) {
+    return &plugin.ConnectionTreeResponse{Nodes: []*plugin.ConnectionTreeNode{
+        {Key: "app", Label: fmt.Sprintf("depth %d", req.GetMaxDepth()), Children: []*plugin.ConnectionTreeNode{
+            {Key: "app.public", Children: []*plugin.ConnectionTreeNode{{Key: "app.public.users"}}},
+        }},
+    }}, nil
+}
+
+func main() {
+    plugin.ServeCLI(&server{})
+}
+`
+
+    bin := buildTestPlugin(t, program)
+
+    for _, tt := range []struct {
+        maxDepth int32
+        levels   int
+    }{{0, 3}, {1, 1}, {2, 2}} {
+        in, _ := json.Marshal(&plugin.ConnectionTreeRequest{MaxDepth: tt.maxDepth})
+        cmd := exec.Command(bin, "connection-tree")
+        cmd.Stdin = bytes.NewReader(in)
+        out, err := cmd.Output()
+        if err != nil {
+            t.Fatalf("connection-tree failed: %v\n%s", err, out)
+        }
+        var resp plugin.ConnectionTreeResponse
+        if err := protojson.Unmarshal(out, &resp); err != nil {
+            t.Fatalf("unmarshal tree: %v\n%s", err, out)
+        }
+        if len(resp.Nodes) != 1 {
+            t.Fatalf("max depth %d: unexpected nodes %v", tt.maxDepth, resp.Nodes)
+        }
+        if want := fmt.Sprintf("depth %d", tt.maxDepth); resp.Nodes[0].Label != want {
+            t.Errorf("plugin saw %q, want %q", resp.Nodes[0].Label, want)
+        }
+        if got := treeDepth(resp.Nodes); got != tt.levels {
+            t.Errorf("max depth %d: tree has %d levels, want %d", tt.maxDepth, got, tt.levels)
+        }
+    }
+}
+
+// TestServeCLI_InfoErrorJSON verifies that a failing command still exits
+// non-zero but reports the plugin's own message as a JSON envelope on stdout
+// so the host can surface it.
+func TestServeCLI_InfoErrorJSON(t *testing.T) {
+    const program = `package main
+
+import (
+    "context"
+    "errors"
+
+    "github.com/felixdotgo/querybox/pkg/plugin"
+    pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+type server struct {
+    pluginpb.UnimplementedPluginServiceServer
+}
+
+func (s *server) Info(ctx context.Context, _ *pluginpb.PluginV1_InfoRequest) (*plugin.InfoResponse, error) {
+    return nil, errors.New("driver library missing")
+}
+
+func main() {
+    plugin.ServeCLI(&server{})
+}
+`
+
+    bin := buildTestPlugin(t, program)
+
+    cmd := exec.Command(bin, "info")
+    var stdout bytes.Buffer
+    cmd.Stdout = &stdout
+    err := cmd.Run()
+    exitErr, ok := err.(*exec.ExitError)
+    if !ok || exitErr.ExitCode() != 1 {
+        t.Fatalf("expected exit status 1, got %v", err)
+    }
+
+    msg, ok := plugin.ParseCLIError(stdout.Bytes())
+    if !ok {
+        t.Fatalf("stdout is not a CLIError envelope: %q", stdout.String())
+    }
+    if msg != "info error: driver library missing" {
+        t.Errorf("unexpected error message: %q", msg)
+    }
+}
+
+func TestParseCLIErrorRejectsOtherPayloads(t *testing.T) {
+    for _, in := range []string{"", "not json", `{"name":"x"}`, `{"error":""}`} {
+        if msg, ok := plugin.ParseCLIError([]byte(in)); ok {
+            t.Errorf("ParseCLIError(%q) = %q, true; want false", in, msg)
+        }
+    }
+}
+
+func TestWithCredentialForm(t *testing.T) {
+	conn := map[string]string{
+		"credential_blob": `{"form":"basic","values":{"host":"db"}}`,
+		"tls":             "true",
+	}
+
+	if got := plugin.WithCredentialForm(conn, ""); got["credential_blob"] != conn["credential_blob"] {
+		t.Errorf("empty form should leave the blob unchanged, got %q", got["credential_blob"])
+	}
+
+	got := plugin.WithCredentialForm(conn, "dsn")
+	cb, err := plugin.ParseCredentialBlob(got)
+	if err != nil {
+		t.Fatalf("ParseCredentialBlob: %v", err)
+	}
+	if cb.Form != "dsn" || cb.Values["host"] != "db" {
+		t.Errorf("unexpected blob %+v", cb)
+	}
+	if got["tls"] != "true" {
+		t.Errorf("other keys should be preserved, got %v", got)
+	}
+	if conn["credential_blob"] != `{"form":"basic","values":{"host":"db"}}` {
+		t.Error("input map must not be modified")
+	}
+
+	bad := map[string]string{"credential_blob": "{"}
+	if got := plugin.WithCredentialForm(bad, "dsn"); got["credential_blob"] != "{" {
+		t.Errorf("invalid blob should pass through, got %q", got["credential_blob"])
+	}
+}
+
+// TestServeCLI_ExecCancelledBySignal verifies that SIGINT, which the host
+// sends when a request is stopped, cancels the context ServeCLI passes to
+// Exec, so a plugin can abort its statement and still answer.
+func TestServeCLI_ExecCancelledBySignal(t *testing.T) {
+    if runtime.GOOS == "windows" {
+        t.Skip("os.Interrupt cannot be sent to a process on Windows")
+    }
+
+    const program = `package main
+
+import (
+    "context"
+    "fmt"
+    "os"
+
+    "github.com/felixdotgo/querybox/pkg/plugin"
+    pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+)
+
+type server struct {
+    pluginpb.UnimplementedPluginServiceServer
+}
+
+func (s *server) Exec(ctx context.Context, req *pluginpb.PluginV1_ExecRequest) (*pluginpb.PluginV1_ExecResponse, error) {
+    fmt.Fprintln(os.Stderr, "running")
+    <-ctx.Done()
+    return &pluginpb.PluginV1_ExecResponse{Error: "stopped: " + ctx.Err().Error()}, nil
+}
+
+func main() {
+    plugin.ServeCLI(&server{})
+}
+`
+
+    bin := buildTestPlugin(t, program)
+
+    in, _ := json.Marshal(&plugin.ExecRequest{Query: "SELECT pg_sleep(60)"})
+    cmd := exec.Command(bin, "exec")
+    cmd.Stdin = bytes.NewReader(in)
+    var stdout bytes.Buffer
+    cmd.Stdout = &stdout
+    stderr, err := cmd.StderrPipe()
+    if err != nil {
+        t.Fatalf("stderr pipe: %v", err)
+    }
+    if err := cmd.Start(); err != nil {
+        t.Fatalf("start: %v", err)
+    }
+    // wait until Exec is running so the signal is not delivered too early
+    if line, err := bufio.NewReader(stderr).ReadString('\n'); err != nil || line != "running\n" {
+        t.Fatalf("plugin did not start Exec: %q %v", line, err)
+    }
+    if err := cmd.Process.Signal(os.Interrupt); err != nil {
+        t.Fatalf("signal: %v", err)
+    }
+
+    done := make(chan error, 1)
+    go func() { done <- cmd.Wait() }()
+    select {
+    case err := <-done:
+        if err != nil {
+            t.Fatalf("plugin exited with %v; want a clean exit after cancelling", err)
+        }
+    case <-time.After(10 * time.Second):
+        _ = cmd.Process.Kill()
+        t.Fatal("plugin did not return after SIGINT")
+    }
+
+    var resp pluginpb.PluginV1_ExecResponse
+    if err := protojson.Unmarshal(stdout.Bytes(), &resp); err != nil {
+        t.Fatalf("decode %q: %v", stdout.String(), err)
+    }
+    if resp.GetError() != "stopped: context canceled" {
+        t.Errorf("error = %q, want the cancelled context reported", resp.GetError())
+    }
+}
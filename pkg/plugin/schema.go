@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// commandMessages maps each ServeCLI command to the request it reads from
+// stdin and the response it writes to stdout.
+var commandMessages = []struct {
+	command       string
+	request, resp proto.Message
+}{
+	{"info", &pluginpb.PluginV1_InfoRequest{}, &pluginpb.PluginV1_InfoResponse{}},
+	{"exec", &pluginpb.PluginV1_ExecRequest{}, &pluginpb.PluginV1_ExecResponse{}},
+	{"authforms", &pluginpb.PluginV1_AuthFormsRequest{}, &pluginpb.PluginV1_AuthFormsResponse{}},
+	{"connection-tree", &pluginpb.PluginV1_ConnectionTreeRequest{}, &pluginpb.PluginV1_ConnectionTreeResponse{}},
+	{"test-connection", &pluginpb.PluginV1_TestConnectionRequest{}, &pluginpb.PluginV1_TestConnectionResponse{}},
+	{"describe-schema", &pluginpb.PluginV1_DescribeSchemaRequest{}, &pluginpb.PluginV1_DescribeSchemaResponse{}},
+	{"completion-fields", &pluginpb.PluginV1_GetCompletionFieldsRequest{}, &pluginpb.PluginV1_GetCompletionFieldsResponse{}},
+	{"mutate-row", &pluginpb.PluginV1_MutateRowRequest{}, &pluginpb.PluginV1_MutateRowResponse{}},
+	{"validate", &pluginpb.PluginV1_ValidateRequest{}, &pluginpb.PluginV1_ValidateResponse{}},
+	{"ddl", &pluginpb.PluginV1_GenerateDDLRequest{}, &pluginpb.PluginV1_GenerateDDLResponse{}},
+	{"settings", &pluginpb.PluginV1_SettingsRequest{}, &pluginpb.PluginV1_SettingsResponse{}},
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) document describing the
+// stdin/stdout payloads of every ServeCLI command, derived from the proto
+// descriptors.  It is what the `schema` subcommand prints.
+//
+// The two directions are encoded differently on the wire and the schema
+// follows suit.  Requests are decoded with encoding/json, so their properties
+// use the proto field names ("node_key") and enums are numbers.  Responses
+// are written with protojson: lowerCamelCase names ("nodeKey"), enums by name
+// and 64-bit integers as strings.  Requests are therefore inlined under
+// "commands" while response messages are shared through "$defs", keyed by
+// message name.
+// Neither side sets additionalProperties, since newer peers may add fields.
+func JSONSchema() map[string]interface{} {
+	defs := map[string]interface{}{}
+	commands := map[string]interface{}{}
+	for _, c := range commandMessages {
+		commands[c.command] = map[string]interface{}{
+			"request":  messageSchema(c.request.ProtoReflect().Descriptor(), false, nil),
+			"response": messageRef(c.resp.ProtoReflect().Descriptor(), defs),
+		}
+	}
+	return map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"$id":      Protocol,
+		"title":    "QueryBox plugin CLI protocol",
+		"commands": commands,
+		"$defs":    defs,
+	}
+}
+
+// messageRef registers md's response-side schema in defs (once) and returns a
+// $ref to it.
+func messageRef(md protoreflect.MessageDescriptor, defs map[string]interface{}) map[string]interface{} {
+	name := string(md.Name())
+	if _, ok := defs[name]; !ok {
+		defs[name] = nil // reserve the name first so recursive messages terminate
+		defs[name] = messageSchema(md, true, defs)
+	}
+	return map[string]interface{}{"$ref": "#/$defs/" + name}
+}
+
+// messageSchema describes md as a JSON object.  protojsonNames selects the
+// response encoding (see JSONSchema); nested messages are then referenced
+// through defs, otherwise they are inlined.
+func messageSchema(md protoreflect.MessageDescriptor, protojsonNames bool, defs map[string]interface{}) map[string]interface{} {
+	if s := wellKnownSchema(md); s != nil {
+		return s
+	}
+	props := map[string]interface{}{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := string(fd.Name())
+		if protojsonNames {
+			name = fd.JSONName()
+		}
+		props[name] = fieldSchema(fd, protojsonNames, defs)
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, protojsonNames bool, defs map[string]interface{}) map[string]interface{} {
+	switch {
+	case fd.IsMap():
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": singularSchema(fd.MapValue(), protojsonNames, defs),
+		}
+	case fd.IsList():
+		return map[string]interface{}{"type": "array", "items": singularSchema(fd, protojsonNames, defs)}
+	}
+	return singularSchema(fd, protojsonNames, defs)
+}
+
+func singularSchema(fd protoreflect.FieldDescriptor, protojsonNames bool, defs map[string]interface{}) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if protojsonNames {
+			// protojson writes 64-bit integers as strings
+			return map[string]interface{}{"type": []string{"string", "integer"}}
+		}
+		return map[string]interface{}{"type": "integer"}
+	case protoreflect.EnumKind:
+		if !protojsonNames {
+			return map[string]interface{}{"type": "integer"}
+		}
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if protojsonNames {
+			if s := wellKnownSchema(fd.Message()); s != nil {
+				return s
+			}
+			return messageRef(fd.Message(), defs)
+		}
+		return messageSchema(fd.Message(), false, nil)
+	}
+	// remaining kinds are the 32-bit integers
+	return map[string]interface{}{"type": "integer"}
+}
+
+// wellKnownSchema returns the JSON shape of the google.protobuf types used by
+// the contract, or nil for any other message.
+func wellKnownSchema(md protoreflect.MessageDescriptor) map[string]interface{} {
+	switch md.FullName() {
+	case "google.protobuf.Struct":
+		return map[string]interface{}{"type": "object"}
+	case "google.protobuf.ListValue":
+		return map[string]interface{}{"type": "array"}
+	case "google.protobuf.Value":
+		return map[string]interface{}{}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package plugin
+
+// SettingOptionPrefix prefixes the ExecRequest.Options keys carrying the
+// values of declared settings: the value of setting "row-cap" travels as
+// options["setting.row-cap"].
+const SettingOptionPrefix = "setting."
+
+// SettingOptions returns values (setting key → value) as ExecRequest.Options
+// entries, ready to be merged into a request's options.  The host stores the
+// values the user sets in the Plugins window and merges them into every exec
+// request this way.
+func SettingOptions(values map[string]string) map[string]string {
+	opts := make(map[string]string, len(values))
+	for k, v := range values {
+		opts[SettingOptionPrefix+k] = v
+	}
+	return opts
+}
+
+// SettingValue returns the value the host passed for setting s in options,
+// or s.DefaultValue when the user has not configured it.
+func SettingValue(options map[string]string, s *Setting) string {
+	if v, ok := options[SettingOptionPrefix+s.GetKey()]; ok {
+		return v
+	}
+	return s.GetDefaultValue()
+}
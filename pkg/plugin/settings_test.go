@@ -0,0 +1,30 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestSettingRoundTrip(t *testing.T) {
+	rowCap := &plugin.Setting{Key: "row-cap", Type: plugin.AuthFieldNumber, DefaultValue: "1000"}
+	banner := &plugin.Setting{Key: "banner", Type: plugin.AuthFieldText, DefaultValue: "hello"}
+
+	// nothing configured: defaults apply
+	if got := plugin.SettingValue(nil, rowCap); got != "1000" {
+		t.Errorf("default row-cap = %q, want 1000", got)
+	}
+
+	// the host turns stored values into options; the plugin reads them back
+	opts := plugin.SettingOptions(map[string]string{"row-cap": "50", "banner": ""})
+	if opts["setting.row-cap"] != "50" {
+		t.Errorf("options = %v", opts)
+	}
+	if got := plugin.SettingValue(opts, rowCap); got != "50" {
+		t.Errorf("row-cap = %q, want 50", got)
+	}
+	// an explicitly empty value is not replaced by the default
+	if got := plugin.SettingValue(opts, banner); got != "" {
+		t.Errorf("banner = %q, want it cleared", got)
+	}
+}
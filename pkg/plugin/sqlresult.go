@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// dmlKeywords are the leading keywords of statements that modify rows.
+var dmlKeywords = map[string]bool{
+	"INSERT":  true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"REPLACE": true,
+	"MERGE":   true,
+}
+
+// IsDMLStatement reports whether query is an INSERT, UPDATE, DELETE, REPLACE
+// or MERGE statement without a RETURNING clause, i.e. one that SQL plugins
+// should run with Exec and answer with AffectedRowsResult rather than scan
+// as a result set.  The statement is found as by LeadingKeyword, so a
+// leading comment or WITH clause does not hide it.
+func IsDMLStatement(query string) bool {
+	kw, stmt := mainStatement(query)
+	return dmlKeywords[kw] && !hasReturning(stmt)
+}
+
+// AffectedRowsResult builds the ExecResult for a statement run with Exec: a
+// SqlResult without columns whose RowsAffected and LastInsertId come from
+// res.  Either is left unset when the driver cannot report it (lib/pq, for
+// example, has no LastInsertId); LastInsertId is also left unset when the
+// statement generated no id (MySQL and SQLite report 0).
+func AffectedRowsResult(res sql.Result) *ExecResult {
+	out := &SqlResult{}
+	if n, err := res.RowsAffected(); err == nil {
+		out.RowsAffected = proto.Int64(n)
+	}
+	if id, err := res.LastInsertId(); err == nil && id > 0 {
+		out.LastInsertId = proto.Int64(id)
+	}
+	return &ExecResult{Payload: &pluginpb.PluginV1_ExecResult_Sql{Sql: out}}
+}
+
+// ValueFormatter renders one scanned value for display given its column's
+// database type name (as reported by sql.ColumnType.DatabaseTypeName, empty
+// when the driver does not report it).
+type ValueFormatter func(typeName string, v interface{}) string
+
+// SQLRowsToResult reads rows into a SqlResult using FormatSQLValue for every
+// value.  See SQLRowsToResultFunc.
+func SQLRowsToResult(rows *sql.Rows, maxRows int) (*ExecResult, error) {
+	return SQLRowsToResultFunc(rows, maxRows, nil)
+}
+
+// SQLRowsToResultFunc reads rows into a SqlResult: one Column per result
+// column, carrying the database type name when the driver reports it, and
+// one Row per record with every value rendered by format (FormatSQLValue
+// when format is nil).  At most maxRows rows are read when maxRows > 0.
+// Errors are returned prefixed "cols error:" or "scan error:" and wrap the
+// driver error so plugins can still classify it.  rows is not closed.
+func SQLRowsToResultFunc(rows *sql.Rows, maxRows int, format ValueFormatter) (*ExecResult, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("cols error: %w", err)
+	}
+	// when the driver can't report type names every column falls back to
+	// the generic formatter
+	typeNames := make([]string, len(cols))
+	if colTypes, err := rows.ColumnTypes(); err == nil && len(colTypes) == len(cols) {
+		for i, ct := range colTypes {
+			typeNames[i] = ct.DatabaseTypeName()
+		}
+	}
+	colMeta := make([]*Column, len(cols))
+	for i, c := range cols {
+		colMeta[i] = &Column{Name: c, Type: typeNames[i]}
+	}
+
+	var rowResults []*Row
+	for (maxRows <= 0 || len(rowResults) < maxRows) && rows.Next() {
+		vals := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		strs := make([]string, len(cols))
+		for i, v := range vals {
+			if format != nil {
+				strs[i] = format(typeNames[i], v)
+			} else {
+				strs[i] = FormatSQLValue(v)
+			}
+		}
+		rowResults = append(rowResults, &Row{Values: strs})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan error: %w", err)
+	}
+	return sqlExecResult(colMeta, rowResults), nil
+}
+
+// PingLatency pings db and returns the round trip in milliseconds, for
+// TestConnectionResponse.LatencyMs.
+func PingLatency(ctx context.Context, db *sql.DB) (int64, error) {
+	start := time.Now()
+	if err := db.PingContext(ctx); err != nil {
+		return 0, err
+	}
+	return time.Since(start).Milliseconds(), nil
+}
+
+// SQLServerInfo runs query, which must return a single row, and maps its
+// columns in order onto keys for TestConnectionResponse.ServerInfo.  Server
+// info is best-effort: a failing query yields nil and empty values are left
+// out.
+func SQLServerInfo(ctx context.Context, db *sql.DB, query string, keys ...string) map[string]string {
+	vals := make([]sql.NullString, len(keys))
+	dest := make([]interface{}, len(keys))
+	for i := range vals {
+		dest[i] = &vals[i]
+	}
+	if err := db.QueryRowContext(ctx, query).Scan(dest...); err != nil {
+		return nil
+	}
+	info := make(map[string]string, len(keys))
+	for i, k := range keys {
+		if vals[i].Valid && vals[i].String != "" {
+			info[k] = vals[i].String
+		}
+	}
+	return info
+}
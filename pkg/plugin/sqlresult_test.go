@@ -0,0 +1,232 @@
+package plugin_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestIsDMLStatement(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"INSERT INTO t VALUES (1)", true},
+		{"  update t set a = 1", true},
+		{"DELETE FROM t", true},
+		{"REPLACE INTO t VALUES (1)", true},
+		{"INSERT INTO t VALUES (1) RETURNING id", false},
+		{"SELECT * FROM t", false},
+		{"CREATE TABLE t (id int)", false},
+		{"", false},
+		{"-- purge\nDELETE FROM t", true},
+		{"WITH src AS (SELECT 1) INSERT INTO t SELECT * FROM src", true},
+		{"WITH d AS (DELETE FROM t RETURNING id) INSERT INTO log SELECT id FROM d", true},
+		{"WITH src AS (SELECT 1) INSERT INTO t SELECT * FROM src RETURNING id", false},
+		{"UPDATE t SET note = 'returning soon'", true},
+		{"WITH replace AS (SELECT 1) SELECT * FROM replace", false},
+	}
+	for _, tt := range tests {
+		if got := plugin.IsDMLStatement(tt.query); got != tt.want {
+			t.Errorf("IsDMLStatement(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+// noInsertID reports a row count but, like lib/pq, no last insert id.
+type noInsertID int64
+
+func (n noInsertID) LastInsertId() (int64, error) { return 0, errors.New("not supported") }
+func (n noInsertID) RowsAffected() (int64, error) { return int64(n), nil }
+
+func TestAffectedRowsResult(t *testing.T) {
+	res := plugin.AffectedRowsResult(driver.Result(driver.RowsAffected(0))).GetSql()
+	if res.RowsAffected == nil || res.GetRowsAffected() != 0 {
+		t.Errorf("a zero count must still be set, got %v", res.RowsAffected)
+	}
+
+	res = plugin.AffectedRowsResult(noInsertID(4)).GetSql()
+	if res.GetRowsAffected() != 4 || res.LastInsertId != nil {
+		t.Errorf("rows affected = %v, last insert id = %v", res.RowsAffected, res.LastInsertId)
+	}
+}
+
+// fakeDriver serves a fixed result set for any query so SQLRowsToResult can
+// be exercised against real *sql.Rows.
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+type fakeRows struct {
+	cols  []string
+	types []string
+	data  [][]driver.Value
+	err   error // returned once data is exhausted
+}
+
+var fakeResult fakeRows
+
+func init() { sql.Register("plugintest-fake", fakeDriver{}) }
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	r := fakeResult
+	return &r, nil
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) ColumnTypeDatabaseTypeName(i int) string {
+	return r.types[i]
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.data) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.data[0])
+	r.data = r.data[1:]
+	return nil
+}
+
+func queryFake(t *testing.T, result fakeRows) *sql.Rows {
+	t.Helper()
+	fakeResult = result
+	db, err := sql.Open("plugintest-fake", "")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	t.Cleanup(func() { rows.Close() })
+	return rows
+}
+
+func TestSQLRowsToResult(t *testing.T) {
+	sample := fakeRows{
+		cols:  []string{"id", "name", "doc"},
+		types: []string{"INT4", "TEXT", "JSON"},
+		data: [][]driver.Value{
+			{int64(1), []byte("alice"), []byte(`{"a":1}`)},
+			{int64(2), nil, []byte{0xff}},
+			{int64(3), "carol", nil},
+		},
+	}
+
+	res, err := plugin.SQLRowsToResult(queryFake(t, sample), 0)
+	if err != nil {
+		t.Fatalf("SQLRowsToResult: %v", err)
+	}
+	var cols []string
+	for _, c := range res.GetSql().GetColumns() {
+		cols = append(cols, c.GetName()+":"+c.GetType())
+	}
+	if !reflect.DeepEqual(cols, []string{"id:INT4", "name:TEXT", "doc:JSON"}) {
+		t.Errorf("columns = %v", cols)
+	}
+	_, rows := cellsOf(res)
+	want := [][]string{{"1", "alice", `{"a":1}`}, {"2", "", "0xff"}, {"3", "carol", ""}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+
+	// the row cap stops reading early
+	res, err = plugin.SQLRowsToResult(queryFake(t, sample), 2)
+	if err != nil {
+		t.Fatalf("SQLRowsToResult: %v", err)
+	}
+	if n := len(res.GetSql().GetRows()); n != 2 {
+		t.Errorf("capped result has %d rows, want 2", n)
+	}
+
+	// a custom formatter sees each column's type name
+	upper := func(typeName string, v interface{}) string {
+		if typeName == "TEXT" {
+			return strings.ToUpper(plugin.FormatSQLValue(v))
+		}
+		return plugin.FormatSQLValue(v)
+	}
+	res, err = plugin.SQLRowsToResultFunc(queryFake(t, sample), 1, upper)
+	if err != nil {
+		t.Fatalf("SQLRowsToResultFunc: %v", err)
+	}
+	if _, rows := cellsOf(res); !reflect.DeepEqual(rows, [][]string{{"1", "ALICE", `{"a":1}`}}) {
+		t.Errorf("formatted rows = %q", rows)
+	}
+}
+
+func TestSQLRowsToResultIterationError(t *testing.T) {
+	broken := errors.New("connection reset")
+	rows := queryFake(t, fakeRows{
+		cols:  []string{"id"},
+		types: []string{"INT4"},
+		data:  [][]driver.Value{{int64(1)}},
+		err:   broken,
+	})
+	_, err := plugin.SQLRowsToResult(rows, 0)
+	if !errors.Is(err, broken) || !strings.HasPrefix(err.Error(), "scan error: ") {
+		t.Errorf("err = %v, want a wrapped scan error", err)
+	}
+}
+
+func TestPingLatency(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectPing().WillDelayFor(20 * time.Millisecond)
+	ms, err := plugin.PingLatency(context.Background(), db)
+	if err != nil {
+		t.Fatalf("PingLatency: %v", err)
+	}
+	if ms < 20 {
+		t.Errorf("latency = %dms, want at least the 20ms ping delay", ms)
+	}
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	if _, err := plugin.PingLatency(context.Background(), db); err == nil {
+		t.Error("expected the ping error")
+	}
+}
+
+func TestSQLServerInfo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT VERSION").WillReturnRows(
+		sqlmock.NewRows([]string{"version", "comment"}).AddRow("8.0.36", ""))
+	got := plugin.SQLServerInfo(context.Background(), db, "SELECT VERSION(), @@version_comment", "version", "edition")
+	if !reflect.DeepEqual(got, map[string]string{"version": "8.0.36"}) {
+		t.Errorf("server info = %v", got)
+	}
+
+	mock.ExpectQuery("SELECT VERSION").WillReturnError(errors.New("denied"))
+	if got := plugin.SQLServerInfo(context.Background(), db, "SELECT VERSION()", "version"); got != nil {
+		t.Errorf("server info on error = %v, want nil", got)
+	}
+}
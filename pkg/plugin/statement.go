@@ -0,0 +1,198 @@
+package plugin
+
+import "strings"
+
+// rowKeywords are the leading keywords of statements that always produce a
+// result set.  "WITH" stands for a WITH clause whose main statement could not
+// be found; it is treated as a query, as it usually is one.
+var rowKeywords = map[string]bool{
+	"SELECT":   true,
+	"VALUES":   true,
+	"TABLE":    true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"EXPLAIN":  true,
+	"PRAGMA":   true,
+	"WITH":     true,
+}
+
+// cteMainKeywords are the keywords that can start the statement following a
+// WITH clause.
+var cteMainKeywords = map[string]bool{
+	"SELECT":  true,
+	"VALUES":  true,
+	"TABLE":   true,
+	"INSERT":  true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"MERGE":   true,
+	"REPLACE": true,
+}
+
+// StatementReturnsRows reports whether query produces a result set and so
+// should be run with Query rather than Exec: SELECT, VALUES, TABLE, SHOW,
+// DESCRIBE, EXPLAIN and PRAGMA statements, and DML with a RETURNING clause.
+// Classification uses LeadingKeyword, so leading comments and WITH clauses
+// do not hide the statement ("WITH t AS (...) INSERT ..." is DML).
+func StatementReturnsRows(query string) bool {
+	kw, stmt := mainStatement(query)
+	if dmlKeywords[kw] {
+		return hasReturning(stmt)
+	}
+	return rowKeywords[kw]
+}
+
+// LeadingKeyword returns the upper-cased first keyword of query's main
+// statement.  Leading whitespace, comments and opening parentheses are
+// skipped, as is a WITH clause, whose common table expressions are passed
+// over with string literals, quoted identifiers and comments inside them
+// taken into account.  A WITH clause with no recognisable main statement
+// yields "WITH"; an empty query yields "".
+func LeadingKeyword(query string) string {
+	kw, _ := mainStatement(query)
+	return kw
+}
+
+// mainStatement returns LeadingKeyword(query) and the text of the main
+// statement starting at that keyword.  After WITH, a keyword only starts the
+// main statement when it directly follows the closing parenthesis of a CTE,
+// so a CTE named like a keyword ("WITH replace AS (...)") is not mistaken
+// for it.
+func mainStatement(query string) (keyword, stmt string) {
+	s := skipSpaceAndComments(query)
+	kw := strings.ToUpper(s[:wordEnd(s, 0)])
+	if kw != "WITH" {
+		return kw, s
+	}
+	depth := 0
+	// afterBody is set once a CTE's parenthesised body (or column list) has
+	// closed, and cleared by the comma that introduces the next CTE name.
+	afterBody := false
+	for i := len(kw); ; {
+		tok, next := nextToken(s, i)
+		switch tok {
+		case "":
+			return kw, s
+		case "(":
+			depth++
+		case ")":
+			depth--
+			afterBody = depth == 0
+		case ",":
+			if depth == 0 {
+				afterBody = false
+			}
+		default:
+			if w := strings.ToUpper(tok); depth == 0 && afterBody && cteMainKeywords[w] {
+				return w, s[next-len(tok):]
+			}
+		}
+		i = next
+	}
+}
+
+// hasReturning reports whether the DML statement stmt has a RETURNING clause
+// of its own, ignoring the word inside string literals, quoted identifiers,
+// comments and parentheses.
+func hasReturning(stmt string) bool {
+	depth := 0
+	for i := 0; ; {
+		tok, next := nextToken(stmt, i)
+		switch tok {
+		case "":
+			return false
+		case "(":
+			depth++
+		case ")":
+			depth--
+		default:
+			if depth <= 0 && strings.EqualFold(tok, "RETURNING") {
+				return true
+			}
+		}
+		i = next
+	}
+}
+
+// nextToken returns the first token at or after s[i] (a word, or one of "(",
+// ")" and ",") and the index just past it.  Whitespace, other punctuation,
+// string literals, quoted identifiers and comments are skipped.  tok is ""
+// once s is exhausted.
+func nextToken(s string, i int) (tok string, next int) {
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == '(' || c == ')' || c == ',':
+			return s[i : i+1], i + 1
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(s, i)
+		case strings.HasPrefix(s[i:], "--") || strings.HasPrefix(s[i:], "/*"):
+			i = skipComment(s, i)
+		case isWordByte(c):
+			j := wordEnd(s, i)
+			return s[i:j], j
+		default:
+			i++
+		}
+	}
+	return "", len(s)
+}
+
+// skipSpaceAndComments drops leading whitespace, comments and opening
+// parentheses (as in "(SELECT ...) UNION ...") from s.
+func skipSpaceAndComments(s string) string {
+	for {
+		s = strings.TrimLeft(s, " \t\r\n\f(")
+		if !strings.HasPrefix(s, "--") && !strings.HasPrefix(s, "/*") {
+			return s
+		}
+		s = s[skipComment(s, 0):]
+	}
+}
+
+// skipComment returns the index just past the "--" or "/* */" comment that
+// starts at s[i], or len(s) when it is not terminated.
+func skipComment(s string, i int) int {
+	if strings.HasPrefix(s[i:], "--") {
+		if n := strings.IndexByte(s[i:], '\n'); n >= 0 {
+			return i + n + 1
+		}
+		return len(s)
+	}
+	if n := strings.Index(s[i+2:], "*/"); n >= 0 {
+		return i + 2 + n + 2
+	}
+	return len(s)
+}
+
+// skipQuoted returns the index just past the string literal or quoted
+// identifier that starts at s[i].  A doubled quote character is an escaped
+// quote.  An unterminated literal runs to the end of s.
+func skipQuoted(s string, i int) int {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		if s[j] != q {
+			continue
+		}
+		if j+1 < len(s) && s[j+1] == q {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(s)
+}
+
+// wordEnd returns the index just past the identifier or keyword that starts
+// at s[i].
+func wordEnd(s string, i int) int {
+	for i < len(s) && isWordByte(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
@@ -0,0 +1,79 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+func TestStatementReturnsRows(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    bool
+		keyword string
+	}{
+		{"SELECT 1", true, "SELECT"},
+		{"  select * from t;", true, "SELECT"},
+		{"VALUES (1), (2)", true, "VALUES"},
+		{"TABLE users", true, "TABLE"},
+		{"SHOW TABLES", true, "SHOW"},
+		{"DESCRIBE users", true, "DESCRIBE"},
+		{"EXPLAIN SELECT * FROM t", true, "EXPLAIN"},
+		{"EXPLAIN ANALYZE DELETE FROM t", true, "EXPLAIN"},
+		{"PRAGMA table_info(users)", true, "PRAGMA"},
+		{"(SELECT 1) UNION (SELECT 2)", true, "SELECT"},
+
+		// comments before the statement
+		{"-- latest first\nSELECT * FROM t ORDER BY id DESC", true, "SELECT"},
+		{"/* report */ /* v2 */ SELECT 1", true, "SELECT"},
+		{"-- cleanup\nDELETE FROM t", false, "DELETE"},
+
+		// DML returns rows only with RETURNING
+		{"INSERT INTO t VALUES (1)", false, "INSERT"},
+		{"UPDATE t SET a = 1", false, "UPDATE"},
+		{"delete from t where id = 1 returning id", true, "DELETE"},
+		{"REPLACE INTO t VALUES (1)", false, "REPLACE"},
+		{"MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN DELETE", false, "MERGE"},
+
+		// leading CTEs are skipped to reach the main statement
+		{"WITH t AS (SELECT 1) SELECT * FROM t", true, "SELECT"},
+		{"WITH RECURSIVE r(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r WHERE n < 5) SELECT n FROM r", true, "SELECT"},
+		{"WITH src AS (SELECT * FROM staging) INSERT INTO t SELECT * FROM src", false, "INSERT"},
+		{"WITH a AS (SELECT 1), b AS MATERIALIZED (SELECT 2) UPDATE t SET x = 1 FROM a, b", false, "UPDATE"},
+		{"WITH src AS (SELECT 1) INSERT INTO t SELECT * FROM src RETURNING id", true, "INSERT"},
+		{"WITH d AS (DELETE FROM t RETURNING id) SELECT count(*) FROM d", true, "SELECT"},
+		{"WITH d AS (DELETE FROM t RETURNING id) INSERT INTO log SELECT id FROM d", false, "INSERT"},
+		{"WITH \"select\" AS (SELECT ')' AS p /* ( */) DELETE FROM t", false, "DELETE"},
+		{"WITH t AS (SELECT 'it''s (' AS s) SELECT * FROM t", true, "SELECT"},
+		{"WITH broken AS (SELECT 1", true, "WITH"},
+
+		// CTEs named like a main-statement keyword
+		{"WITH replace AS (SELECT 1) SELECT * FROM replace", true, "SELECT"},
+		{"WITH a AS (SELECT 1), update AS (SELECT 2) SELECT * FROM a, update", true, "SELECT"},
+		{"WITH insert(n) AS (VALUES (1)) DELETE FROM t USING insert", false, "DELETE"},
+
+		// RETURNING only counts outside literals, identifiers and comments
+		{"SELECT 'returning'", true, "SELECT"},
+		{"UPDATE t SET a = 'returning'", false, "UPDATE"},
+		{"UPDATE t SET \"returning\" = 1 -- returning\n", false, "UPDATE"},
+		{"INSERT INTO t VALUES ('x') /* no returning */", false, "INSERT"},
+		{"UPDATE t SET a = 'it''s' RETURNING a", true, "UPDATE"},
+
+		// DDL and session statements return no rows
+		{"CREATE TABLE t (id int)", false, "CREATE"},
+		{"DROP TABLE t", false, "DROP"},
+		{"SET search_path TO public", false, "SET"},
+		{"BEGIN", false, "BEGIN"},
+		{"", false, ""},
+		{"-- only a comment", false, ""},
+		{"/* unterminated", false, ""},
+	}
+	for _, tt := range tests {
+		if got := plugin.StatementReturnsRows(tt.query); got != tt.want {
+			t.Errorf("StatementReturnsRows(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+		if got := plugin.LeadingKeyword(tt.query); got != tt.keyword {
+			t.Errorf("LeadingKeyword(%q) = %q, want %q", tt.query, got, tt.keyword)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Streamed document results.
+//
+// With the exec option format=document-batches a plugin answers with a
+// sequence of DocumentBatch frames, one protojson object per line, instead of
+// a single ExecResponse.  Every frame but the last holds exactly batch-size
+// documents (default DefaultBatchSize); the last has Final set, holds the
+// remainder (possibly none) and reports any error.  A stream that ends
+// without a final frame was cut short.
+//
+// Plugins that implement DocumentStreamer emit batches as their cursor
+// drains, so the first rows of a slow scan reach the UI early.  For any other
+// plugin ServeCLI runs Exec and frames its DocumentResult, so hosts can rely
+// on the format regardless of driver.
+
+// FormatDocumentBatches is the OptionFormat value requesting streamed
+// DocumentBatch frames.
+const FormatDocumentBatches = "document-batches"
+
+// OptionBatchSize is the ExecRequest.Options key setting how many documents
+// each streamed frame carries.
+const OptionBatchSize = "batch-size"
+
+// DefaultBatchSize is used when OptionBatchSize is absent or invalid.
+const DefaultBatchSize = 100
+
+// maxBatchLine bounds a single frame when reading a stream.
+const maxBatchLine = 64 << 20
+
+// DocumentStreamer is implemented by document plugins that can emit results
+// incrementally.  ExecDocuments calls emit with documents as they are read;
+// emit may be called with any number of documents and returns an error once
+// the host has gone away, at which point the scan should stop.  A returned
+// error is reported in the final frame.
+type DocumentStreamer interface {
+	ExecDocuments(ctx context.Context, req *ExecRequest, emit func([]*structpb.Struct) error) error
+}
+
+// BatchSize returns the frame size requested in options, defaulting to
+// DefaultBatchSize for missing, non-numeric or non-positive values.
+func BatchSize(options map[string]string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(options[OptionBatchSize]))
+	if err != nil || n <= 0 {
+		return DefaultBatchSize
+	}
+	return n
+}
+
+// DocumentBatchWriter frames documents into DocumentBatch lines of a fixed
+// size.  Call Close (or Fail) exactly once to write the final frame.
+type DocumentBatchWriter struct {
+	w       *bufio.Writer
+	size    int
+	pending []*structpb.Struct
+}
+
+// NewDocumentBatchWriter returns a writer emitting frames of size documents
+// to w; a non-positive size means DefaultBatchSize.
+func NewDocumentBatchWriter(w io.Writer, size int) *DocumentBatchWriter {
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	return &DocumentBatchWriter{w: bufio.NewWriter(w), size: size}
+}
+
+// Add queues docs, writing a frame each time batch-size documents are
+// pending.  It has the signature of DocumentStreamer's emit callback.
+func (bw *DocumentBatchWriter) Add(docs []*structpb.Struct) error {
+	bw.pending = append(bw.pending, docs...)
+	for len(bw.pending) >= bw.size {
+		if err := bw.write(&DocumentBatch{Documents: bw.pending[:bw.size]}); err != nil {
+			return err
+		}
+		bw.pending = bw.pending[bw.size:]
+	}
+	return nil
+}
+
+// Close writes the final frame with the remaining documents.
+func (bw *DocumentBatchWriter) Close() error {
+	return bw.write(&DocumentBatch{Documents: bw.pending, Final: true})
+}
+
+// Fail writes the final frame with the remaining documents and an error.
+func (bw *DocumentBatchWriter) Fail(msg string, code ErrorCode) error {
+	return bw.write(&DocumentBatch{Documents: bw.pending, Final: true, Error: msg, ErrorCode: code})
+}
+
+func (bw *DocumentBatchWriter) write(b *DocumentBatch) error {
+	line, err := protojson.Marshal(b)
+	if err != nil {
+		return err
+	}
+	bw.w.Write(line)
+	bw.w.WriteByte('\n')
+	// flush every frame so the host sees it immediately
+	return bw.w.Flush()
+}
+
+// ReadDocumentBatches decodes DocumentBatch frames from r, calling fn for each
+// in order, and returns after the final frame.  It returns
+// io.ErrUnexpectedEOF if the stream ends before a final frame and stops early
+// with fn's error if it returns one.
+func ReadDocumentBatches(r io.Reader, fn func(*DocumentBatch) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxBatchLine)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var b DocumentBatch
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(line, &b); err != nil {
+			return fmt.Errorf("invalid document batch: %w", err)
+		}
+		if err := fn(&b); err != nil {
+			return err
+		}
+		if b.GetFinal() {
+			return nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// serveDocumentBatches answers an exec request in FormatDocumentBatches,
+// streaming through DocumentStreamer when the server implements it and
+// framing a regular Exec result otherwise.
+func serveDocumentBatches(ctx context.Context, s pluginpb.PluginServiceServer, req *ExecRequest, w io.Writer) error {
+	bw := NewDocumentBatchWriter(w, BatchSize(req.GetOptions()))
+	if ds, ok := s.(DocumentStreamer); ok {
+		if err := ds.ExecDocuments(ctx, req, bw.Add); err != nil {
+			return bw.Fail(err.Error(), ClassifyError(err))
+		}
+		return bw.Close()
+	}
+
+	res, err := s.Exec(ctx, req)
+	switch {
+	case err != nil:
+		return bw.Fail(fmt.Sprintf("exec error: %v", err), ClassifyError(err))
+	case res.GetError() != "":
+		return bw.Fail(res.GetError(), res.GetErrorCode())
+	case res.GetResult() != nil && res.GetResult().GetDocument() == nil:
+		return bw.Fail("document batches require a document result", ErrorCodeUnsupported)
+	}
+	if err := bw.Add(res.GetResult().GetDocument().GetDocuments()); err != nil {
+		return err
+	}
+	return bw.Close()
+}
@@ -0,0 +1,134 @@
+package plugin_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func numberedDocs(n int) []*structpb.Struct {
+	docs := make([]*structpb.Struct, n)
+	for i := range docs {
+		docs[i], _ = structpb.NewStruct(map[string]interface{}{"n": float64(i)})
+	}
+	return docs
+}
+
+func readBatches(t *testing.T, r io.Reader) []*plugin.DocumentBatch {
+	t.Helper()
+	var got []*plugin.DocumentBatch
+	if err := plugin.ReadDocumentBatches(r, func(b *plugin.DocumentBatch) error {
+		got = append(got, b)
+		return nil
+	}); err != nil {
+		t.Fatalf("ReadDocumentBatches: %v", err)
+	}
+	return got
+}
+
+func TestBatchSize(t *testing.T) {
+	tests := []struct {
+		options map[string]string
+		want    int
+	}{
+		{nil, plugin.DefaultBatchSize},
+		{map[string]string{plugin.OptionBatchSize: "25"}, 25},
+		{map[string]string{plugin.OptionBatchSize: "0"}, plugin.DefaultBatchSize},
+		{map[string]string{plugin.OptionBatchSize: "lots"}, plugin.DefaultBatchSize},
+	}
+	for _, tt := range tests {
+		if got := plugin.BatchSize(tt.options); got != tt.want {
+			t.Errorf("BatchSize(%v) = %d, want %d", tt.options, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentBatchRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := plugin.NewDocumentBatchWriter(&buf, 2)
+	// uneven emits are regrouped into fixed-size frames
+	docs := numberedDocs(5)
+	if err := w.Add(docs[:3]); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := w.Add(docs[3:]); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Fatalf("wrote %d frames, want 3:\n%s", lines, buf.String())
+	}
+
+	batches := readBatches(t, &buf)
+	sizes := []int{2, 2, 1}
+	if len(batches) != len(sizes) {
+		t.Fatalf("read %d batches, want %d", len(batches), len(sizes))
+	}
+	n := 0.0
+	for i, b := range batches {
+		if len(b.GetDocuments()) != sizes[i] {
+			t.Errorf("batch %d has %d documents, want %d", i, len(b.GetDocuments()), sizes[i])
+		}
+		if b.GetFinal() != (i == len(batches)-1) {
+			t.Errorf("batch %d final = %v", i, b.GetFinal())
+		}
+		for _, d := range b.GetDocuments() {
+			if got := d.AsMap()["n"]; got != n {
+				t.Errorf("document order: got n=%v, want %v", got, n)
+			}
+			n++
+		}
+	}
+}
+
+func TestDocumentBatchFail(t *testing.T) {
+	var buf bytes.Buffer
+	w := plugin.NewDocumentBatchWriter(&buf, 10)
+	w.Add(numberedDocs(3))
+	if err := w.Fail("cursor killed", plugin.ErrorCodeTimeout); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	batches := readBatches(t, &buf)
+	if len(batches) != 1 {
+		t.Fatalf("read %d batches, want 1", len(batches))
+	}
+	b := batches[0]
+	if !b.GetFinal() || b.GetError() != "cursor killed" || b.GetErrorCode() != plugin.ErrorCodeTimeout {
+		t.Errorf("final frame = %v", b)
+	}
+	if len(b.GetDocuments()) != 3 {
+		t.Errorf("documents read before the error should be kept, got %d", len(b.GetDocuments()))
+	}
+}
+
+func TestReadDocumentBatchesTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	w := plugin.NewDocumentBatchWriter(&buf, 1)
+	w.Add(numberedDocs(2))
+	// no Close: the plugin died mid-stream
+	err := plugin.ReadDocumentBatches(&buf, func(*plugin.DocumentBatch) error { return nil })
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("err = %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	stop := errors.New("stop")
+	buf.Reset()
+	w = plugin.NewDocumentBatchWriter(&buf, 1)
+	w.Add(numberedDocs(2))
+	w.Close()
+	calls := 0
+	err = plugin.ReadDocumentBatches(&buf, func(*plugin.DocumentBatch) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("callback error should stop reading: err=%v calls=%d", err, calls)
+	}
+}
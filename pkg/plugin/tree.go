@@ -0,0 +1,22 @@
+package plugin
+
+// PruneConnectionTree drops every node deeper than maxDepth levels from
+// nodes, in place, and returns nodes.  A maxDepth of 1 keeps only the
+// top-level nodes; a non-positive maxDepth leaves the tree untouched.
+// ServeCLI applies it to every ConnectionTree response, so plugins that build
+// their whole tree still honour ConnectionTreeRequest.MaxDepth; plugins with
+// expensive levels should also check the depth themselves and skip loading
+// them.
+func PruneConnectionTree(nodes []*ConnectionTreeNode, maxDepth int32) []*ConnectionTreeNode {
+	if maxDepth <= 0 {
+		return nodes
+	}
+	for _, n := range nodes {
+		if maxDepth == 1 {
+			n.Children = nil
+			continue
+		}
+		PruneConnectionTree(n.GetChildren(), maxDepth-1)
+	}
+	return nodes
+}
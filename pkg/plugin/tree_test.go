@@ -0,0 +1,51 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+)
+
+// treeDepth returns the number of levels in nodes.
+func treeDepth(nodes []*plugin.ConnectionTreeNode) int {
+	depth := 0
+	for _, n := range nodes {
+		if d := 1 + treeDepth(n.GetChildren()); d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+func sampleTree() []*plugin.ConnectionTreeNode {
+	return []*plugin.ConnectionTreeNode{
+		{Key: "app", Children: []*plugin.ConnectionTreeNode{
+			{Key: "app.public", Children: []*plugin.ConnectionTreeNode{
+				{Key: "app.public.users", Children: []*plugin.ConnectionTreeNode{{Key: "app.public.users.id"}}},
+			}},
+		}},
+		{Key: "__create_database__"},
+	}
+}
+
+func TestPruneConnectionTree(t *testing.T) {
+	for _, tt := range []struct {
+		maxDepth int32
+		want     int
+	}{
+		{0, 4},
+		{-1, 4},
+		{1, 1},
+		{2, 2},
+		{3, 3},
+		{10, 4},
+	} {
+		nodes := plugin.PruneConnectionTree(sampleTree(), tt.maxDepth)
+		if len(nodes) != 2 {
+			t.Fatalf("max depth %d: top-level nodes dropped: %v", tt.maxDepth, nodes)
+		}
+		if got := treeDepth(nodes); got != tt.want {
+			t.Errorf("max depth %d: tree has %d levels, want %d", tt.maxDepth, got, tt.want)
+		}
+	}
+}
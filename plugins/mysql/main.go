@@ -1,13 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/felixdotgo/querybox/pkg/certs"
 	"github.com/felixdotgo/querybox/pkg/plugin"
@@ -29,10 +36,15 @@ func (m *mysqlPlugin) Info(ctx context.Context, _ *pluginpb.PluginV1_InfoRequest
 		Description: "MySQL database driver",
 		Url:         "https://www.mysql.com/",
 		Author:      "Oracle",
-		Capabilities: []string{"query", "explain-query", "mutate-row", "describe-schema"},
+		Capabilities: []string{"query", "explain-query", "mutate-row", "describe-schema", "ddl"},
 		Tags:        []string{"sql", "relational"},
 		License:     "GPL-2.0",
 		IconUrl:     "https://www.mysql.com/common/logos/logo-mysql-170x115.png",
+		ExampleQueries: []string{
+			"SHOW DATABASES;",
+			"SHOW TABLES;",
+			"SELECT * FROM `table_name` LIMIT 100;",
+		},
 	}, nil
 }
 
@@ -44,11 +56,13 @@ func (m *mysqlPlugin) AuthForms(ctx context.Context, _ *plugin.AuthFormsRequest)
 		Fields: []*plugin.AuthField{
 			{Type: plugin.AuthFieldText, Name: "host", Label: "Host", Required: true, Placeholder: "127.0.0.1", Value: "127.0.0.1"},
 			{Type: plugin.AuthFieldNumber, Name: "port", Label: "Port", Placeholder: "3306", Value: "3306"},
+			{Type: plugin.AuthFieldFilePath, Name: "socket", Label: "Unix socket (overrides host/port)", Placeholder: "/var/run/mysqld/mysqld.sock"},
 			{Type: plugin.AuthFieldText, Name: "user", Label: "User", Value: "root"},
 			{Type: plugin.AuthFieldPassword, Name: "password", Label: "Password"},
 			{Type: plugin.AuthFieldText, Name: "database", Label: "Database name"},
 			// allow users to specify extra params such as tls=skip-verify
-			{Type: plugin.AuthFieldSelect, Name: "tls", Label: "TLS mode (e.g. skip-verify)", Options: []string{"skip-verify", "true", "false", "preferred"}, Value: "skip-verify"},
+			{Type: plugin.AuthFieldSelect, Name: "tls", Label: "TLS mode (e.g. skip-verify)", Options: []string{"skip-verify", "true", "false", "preferred", "verify-ca", "verify-full"}, Value: "skip-verify"},
+			{Type: plugin.AuthFieldFilePath, Name: "ca_cert", Label: "CA certificate (PEM, for verify-ca/verify-full)"},
 			{Type: plugin.AuthFieldText, Name: "params", Label: "Extra params", Placeholder: "charset=utf8&parseTime=true"},
 		},
 	}
@@ -74,78 +88,142 @@ func init() {
     }
 }
 
+// registerVerifyTLSConfig registers a certificate-verifying TLS config with
+// the MySQL driver and returns its name for the DSN `tls` parameter.  The
+// trust store is the embedded root bundle plus, when caPath is set, the
+// user's CA certificate (PEM).  "verify-full" checks the chain and the
+// hostname (the driver fills in ServerName from the DSN host); "verify-ca"
+// checks the chain only, mirroring the PostgreSQL sslmode of the same name.
+func registerVerifyTLSConfig(mode, caPath string) (string, error) {
+	pool := x509.NewCertPool()
+	if roots, err := certs.RootCertPool(); err == nil && roots != nil {
+		pool = roots.Clone()
+	}
+	if caPath != "" {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return "", fmt.Errorf("read CA certificate: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("CA certificate %s contains no PEM certificates", caPath)
+		}
+	}
+
+	cfg := &tls.Config{RootCAs: pool}
+	if mode == "verify-ca" {
+		// Skip the built-in verification (which includes the hostname) and
+		// verify the chain against our pool ourselves.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			leaf, err := x509.ParseCertificate(rawCerts[0])
+			if err != nil {
+				return err
+			}
+			inter := x509.NewCertPool()
+			for _, raw := range rawCerts[1:] {
+				if c, err := x509.ParseCertificate(raw); err == nil {
+					inter.AddCert(c)
+				}
+			}
+			_, err = leaf.Verify(x509.VerifyOptions{Roots: pool, Intermediates: inter})
+			return err
+		}
+	}
+
+	sum := sha256.Sum256([]byte(caPath))
+	name := fmt.Sprintf("querybox-%s-%x", mode, sum[:4])
+	if err := mysql.RegisterTLSConfig(name, cfg); err != nil {
+		return "", fmt.Errorf("register TLS config: %w", err)
+	}
+	return name, nil
+}
+
+// buildDSN assembles a go-sql-driver DSN from the connection values read by
+// plugin.ParseConnection, so a flat map and a credential_blob follow the same
+// precedence rules.  A "dsn" value (legacy) is used as is; otherwise one is
+// built from host/port (or socket), user, password and database.  Any other
+// non-empty values, including tls, are appended as query parameters.  This
+// lets callers configure SSL (tls=skip-verify, tls=true, etc) or other driver
+// options.
 func buildDSN(connection map[string]string) (string, error) {
-    // Accept either a full DSN under key "dsn" (legacy) or a credential blob
-    // JSON (recommended) stored under "credential_blob" containing: {"form":"basic","values": { ... }}
-    // Additionally we allow arbitrary extra parameters (including tls) which
-    // are appended as query parameters.  This lets callers configure SSL
-    // (tls=skip-verify, tls=true, etc) or other driver options.
-    dsn, ok := connection["dsn"]
-    if !ok || dsn == "" {
-        // try credential_blob
-        if cred, err := plugin.ParseCredentialBlob(connection); err == nil {
-                // if plugin stored a dsn inside values, prefer that
-                if v, ok := cred.Values["dsn"]; ok && v != "" {
-                    dsn = v
-                } else {
-                    // build a simple DSN from common keys
-                    host := cred.Values["host"]
-                    user := cred.Values["user"]
-                    pass := cred.Values["password"]
-                    port := cred.Values["port"]
-                    dbname := cred.Values["database"]
-                    if port == "" {
-                        port = "3306"
-                    }
-                    if host != "" {
-                        dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", user, pass, host, port, dbname)
-                    }
-                }
-                // append any extra parameters as query string
-                if dsn != "" {
-                    params := url.Values{}
-                    for k, v := range cred.Values {
-                        switch k {
-                        case "host", "user", "password", "port", "database", "dsn":
-                            // already handled above
-                            continue
-                        }
-                        if v != "" {
-                            params.Add(k, v)
-                        }
-                    }
-                    // convert generic tls flags to our registered config
-                    if t := params.Get("tls"); t == "true" || t == "preferred" {
-                        params.Set("tls", "querybox")
-                    }
-                    if len(params) > 0 {
-                        // ensure we always have a reasonable connection timeout so the
-                        // plugin can't hang indefinitely (30s context is managed by
-                        // caller).  driver accepts values like "5s".
-                        if params.Get("timeout") == "" {
-                            params.Set("timeout", "5s")
-                        }
-                        sep := "?"
-                        if strings.Contains(dsn, "?") {
-                            sep = "&"
-                        }
-                        dsn = dsn + sep + params.Encode()
-                    }
-                }
-        }
-    }
-    // If the caller forwarded a specific database (e.g. from the tree-node
-    // context), override the DBName in the parsed DSN so the connection opens
-    // against the correct database regardless of the saved credential.
-    if dbOverride, ok := connection["database"]; ok && dbOverride != "" && dsn != "" {
-        if cfg, err2 := mysql.ParseDSN(dsn); err2 == nil {
-            cfg.DBName = dbOverride
-            if rebuilt := cfg.FormatDSN(); rebuilt != "" {
-                dsn = rebuilt
-            }
-        }
-    }
-    return dsn, nil
+	_, values, err := plugin.ParseConnection(connection)
+	if err != nil {
+		return "", err
+	}
+	dsn := values["dsn"]
+	if dsn == "" {
+		// build a simple DSN from common keys
+		port := values["port"]
+		if port == "" {
+			port = "3306"
+		}
+		// a socket path takes precedence over host/port
+		if socket := values["socket"]; socket != "" {
+			dsn = fmt.Sprintf("%s:%s@unix(%s)/%s", values["user"], values["password"], socket, values["database"])
+		} else if host := values["host"]; host != "" {
+			dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", values["user"], values["password"], host, port, values["database"])
+		}
+	}
+	if dsn == "" {
+		return "", nil
+	}
+
+	// append any extra parameters as query string
+	params := url.Values{}
+	for k, v := range values {
+		switch k {
+		case "host", "user", "password", "port", "database", "dsn", "ca_cert", "socket":
+			// already handled above (ca_cert feeds the TLS config below)
+			continue
+		case "tls":
+			if values["socket"] != "" {
+				// local socket traffic never leaves the host
+				continue
+			}
+		}
+		if v != "" {
+			params.Add(k, v)
+		}
+	}
+	// convert generic tls flags to our registered config
+	if t := params.Get("tls"); t == "true" || t == "preferred" {
+		params.Set("tls", "querybox")
+	} else if t == "verify-ca" || t == "verify-full" {
+		name, err := registerVerifyTLSConfig(t, values["ca_cert"])
+		if err != nil {
+			return "", err
+		}
+		params.Set("tls", name)
+	}
+	if len(params) > 0 {
+		// ensure we always have a reasonable connection timeout so the
+		// plugin can't hang indefinitely (30s context is managed by
+		// caller).  driver accepts values like "5s".
+		if params.Get("timeout") == "" {
+			params.Set("timeout", "5s")
+		}
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn = dsn + sep + params.Encode()
+	}
+
+	// A "database" value (e.g. forwarded from the tree-node context) overrides
+	// the DBName of a supplied DSN so the connection opens against the correct
+	// database regardless of the saved credential.
+	if dbOverride := values["database"]; dbOverride != "" && values["dsn"] != "" {
+		if cfg, err2 := mysql.ParseDSN(dsn); err2 == nil {
+			cfg.DBName = dbOverride
+			if rebuilt := cfg.FormatDSN(); rebuilt != "" {
+				dsn = rebuilt
+			}
+		}
+	}
+	return dsn, nil
 }
 
 // getDatabaseFromConn returns the database name the connection will use, or
@@ -267,11 +345,57 @@ func applySortMySQL(query, column, direction string) string {
 	return fmt.Sprintf("SELECT * FROM (%s) AS _sort ORDER BY `%s` %s", query, column, direction)
 }
 
+// classifyMySQLError maps a go-sql-driver/mysql error to a plugin.ErrorCode
+// using the server error number, falling back to plugin.ClassifyError for
+// client-side failures (dial errors, timeouts, ...).
+func classifyMySQLError(err error) plugin.ErrorCode {
+	if errors.Is(err, mysql.ErrInvalidConn) {
+		return plugin.ErrorCodeConnectionFailed
+	}
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return plugin.ClassifyError(err)
+	}
+	switch myErr.Number {
+	case 1044, 1045, 1698: // ER_DBACCESS_DENIED_ERROR, ER_ACCESS_DENIED_ERROR, ER_ACCESS_DENIED_NO_PASSWORD_ERROR
+		return plugin.ErrorCodeAuthFailed
+	case 1064, 1149: // ER_PARSE_ERROR, ER_SYNTAX_ERROR
+		return plugin.ErrorCodeSyntaxError
+	case 1049, 1051, 1054, 1146: // unknown database/table/column, no such table
+		return plugin.ErrorCodeNotFound
+	case 1205, 3024: // lock wait timeout, max_execution_time exceeded
+		return plugin.ErrorCodeTimeout
+	case 1235: // ER_NOT_SUPPORTED_YET
+		return plugin.ErrorCodeUnsupported
+	case 1040, 1053, 1152, 1159, 1161: // too many connections, shutdown, aborted/net errors
+		return plugin.ErrorCodeConnectionFailed
+	}
+	return plugin.ErrorCodeUnknown
+}
+
+// applyExplainMySQL prefixes query with EXPLAIN when the host requested an
+// explain run (`options["explain-query"] == "yes"`), mirroring the Postgres
+// plugin.  `options["explain-format"] == "json"` selects EXPLAIN FORMAT=JSON,
+// for which MySQL returns the whole plan as a single JSON row.  The query is
+// returned unchanged when no explain was requested.
+func applyExplainMySQL(query string, options map[string]string) string {
+	if options["explain-query"] != "yes" {
+		return query
+	}
+	if strings.EqualFold(options["explain-format"], "json") {
+		return "EXPLAIN FORMAT=JSON " + query
+	}
+	return "EXPLAIN " + query
+}
+
+// openMySQLDB wraps sql.Open so unit tests can replace it with a mock.
+var openMySQLDB = func(dsn string) (*sql.DB, error) {
+	return sql.Open("mysql", dsn)
+}
+
 func (m *mysqlPlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plugin.ExecResponse, error) {
 	if req.Options != nil {
-		if v, ok := req.Options["explain-query"]; ok && v == "yes" {
-			req.Query = "EXPLAIN " + req.Query
-		}
+		req.Query = applyExplainMySQL(req.Query, req.Options)
 		if col, ok := req.Options["sort-column"]; ok && col != "" {
 			dir := "ASC"
 			if strings.ToUpper(req.Options["sort-direction"]) == "DESC" {
@@ -280,6 +404,7 @@ func (m *mysqlPlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plugi
 			req.Query = applySortMySQL(req.Query, col, dir)
 		}
 	}
+	req.Query = plugin.ApplyLimitOffset(req.Query, req.GetLimit(), req.GetOffset())
 	dsn, err := buildDSN(req.Connection)
 	if err != nil {
 		return &plugin.ExecResponse{Error: fmt.Sprintf("invalid connection: %v", err)}, nil
@@ -288,56 +413,85 @@ func (m *mysqlPlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plugi
 		return &plugin.ExecResponse{Error: "missing dsn in connection"}, nil
 	}
 
-	db, err := sql.Open("mysql", dsn)
+	db, err := openMySQLDB(dsn)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("open error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: fmt.Sprintf("open error: %v", err), ErrorCode: classifyMySQLError(err)}, nil
 	}
 	defer db.Close()
+	// SHOW WARNINGS reports on the session's previous statement, so keep
+	// both on one connection.
+	db.SetMaxOpenConns(1)
+
+	// statement time is reported in the result metadata
+	start := time.Now()
+
+	// DML runs with Exec so the affected-row count and last insert id can be
+	// reported; everything else is scanned as a result set.
+	if plugin.IsDMLStatement(req.Query) {
+		res, err := db.ExecContext(ctx, req.Query)
+		if err != nil {
+			return &plugin.ExecResponse{Error: fmt.Sprintf("exec error: %v", err), ErrorCode: classifyMySQLError(err)}, nil
+		}
+		result := plugin.AffectedRowsResult(res)
+		result.Warnings = mysqlWarnings(ctx, db)
+		plugin.SetDuration(result, start)
+		return &plugin.ExecResponse{Result: result}, nil
+	}
 
 	rows, err := db.Query(req.Query)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("query error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: fmt.Sprintf("query error: %v", err), ErrorCode: classifyMySQLError(err)}, nil
 	}
 	defer rows.Close()
 
-	cols, err := rows.Columns()
+	// database type names let JSON columns be pretty-printed
+	result, err := plugin.SQLRowsToResultFunc(rows, 0, formatMySQLValue)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("cols error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: err.Error(), ErrorCode: classifyMySQLError(err)}, nil
 	}
+	// release the connection before asking it for warnings
+	rows.Close()
+	result.Warnings = mysqlWarnings(ctx, db)
+	plugin.SetDuration(result, start)
+	return &plugin.ExecResponse{Result: result}, nil
+}
 
-	// prepare column metadata (type info currently unavailable, leave empty)
-	colMeta := make([]*plugin.Column, len(cols))
-	for i, c := range cols {
-		colMeta[i] = &plugin.Column{Name: c}
+// formatMySQLValue renders a scanned value for display using the column's
+// database type name as reported by the driver.  JSON columns arrive as
+// minified []byte and are validated and pretty-printed; anything else, and
+// JSON that fails to parse, goes through plugin.FormatSQLValue unchanged.
+func formatMySQLValue(typeName string, v interface{}) string {
+	raw := plugin.FormatSQLValue(v)
+	if v == nil || typeName != "JSON" {
+		return raw
 	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(raw), "", "  "); err == nil {
+		return buf.String()
+	}
+	return raw
+}
 
-	var rowResults []*plugin.Row
+// mysqlWarnings returns the warnings and notes the server recorded for the
+// session's previous statement (truncated values, implicit conversions,
+// ...) as "<Level> <Code>: <Message>".  db must be pinned to the connection
+// that ran the statement.  Failures are ignored: warnings are best effort.
+func mysqlWarnings(ctx context.Context, db *sql.DB) []string {
+	rows, err := db.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	var out []string
 	for rows.Next() {
-		vals := make([]interface{}, len(cols))
-		ptrs := make([]interface{}, len(cols))
-		for i := range vals {
-			ptrs[i] = &vals[i]
-		}
-		if err := rows.Scan(ptrs...); err != nil {
-			return &plugin.ExecResponse{Error: fmt.Sprintf("scan error: %v", err)}, nil
+		var level, message string
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return out
 		}
-		strs := make([]string, len(cols))
-		for i, v := range vals {
-			strs[i] = plugin.FormatSQLValue(v)
-		}
-		rowResults = append(rowResults, &plugin.Row{Values: strs})
+		out = append(out, fmt.Sprintf("%s %d: %s", level, code, message))
 	}
-
-	return &plugin.ExecResponse{
-		Result: &plugin.ExecResult{
-			Payload: &pluginpb.PluginV1_ExecResult_Sql{
-				Sql: &plugin.SqlResult{
-					Columns: colMeta,
-					Rows:    rowResults,
-				},
-			},
-		},
-	}, nil
+	return out
 }
 
 // ConnectionTree returns a server root node, a list of databases, and their
@@ -376,33 +530,30 @@ func (m *mysqlPlugin) ConnectionTree(ctx context.Context, req *plugin.Connection
 		// For each database expose a child list of tables.  Clicking a table
 		// pre-fills a SELECT query; the DDL actions allow create/drop.
 		tables := []*plugin.ConnectionTreeNode{}
-		tblRows, err := db.Query(fmt.Sprintf("SHOW TABLES FROM `%s`", dbname))
-		if err == nil {
-			for tblRows.Next() {
-				var tbl string
-				if tblRows.Scan(&tbl) == nil {
-					tables = append(tables, &plugin.ConnectionTreeNode{
-						Key:      dbname + "." + tbl,
-						Label:    tbl,
-						NodeType: plugin.ConnectionTreeNodeTypeTable,
-						Actions: []*plugin.ConnectionTreeAction{
-						{Type: plugin.ConnectionTreeActionSelect, Title: "Select rows", Query: fmt.Sprintf("SELECT * FROM `%s` LIMIT 100;", tbl), Hidden: true, NewTab: true},
-						{Type: plugin.ConnectionTreeActionDropTable, Title: "Drop table", Query: fmt.Sprintf("DROP TABLE `%s`;", tbl)},
-						},
-					})
+		// a depth limit of 1 asks for the databases only
+		if req.GetMaxDepth() != 1 {
+			tblRows, err := db.Query(fmt.Sprintf("SHOW TABLES FROM `%s`", dbname))
+			if err == nil {
+				for tblRows.Next() {
+					var tbl string
+					if tblRows.Scan(&tbl) == nil {
+						tables = append(tables, &plugin.ConnectionTreeNode{
+							Key:      dbname + "." + tbl,
+							Label:    tbl,
+							NodeType: plugin.ConnectionTreeNodeTypeTable,
+							Actions:  tableActions(dbname, tbl),
+						})
+					}
 				}
+				tblRows.Close()
 			}
-			tblRows.Close()
 		}
 		dbNodes = append(dbNodes, &plugin.ConnectionTreeNode{
 			Key:      dbname,
 			Label:    dbname,
 			NodeType: plugin.ConnectionTreeNodeTypeDatabase,
 			Children: tables,
-			Actions: []*plugin.ConnectionTreeAction{
-				{Type: plugin.ConnectionTreeActionCreateTable, Title: "Create table", Query: "CREATE TABLE `new_table` (\n  `id` INT NOT NULL AUTO_INCREMENT,\n  PRIMARY KEY (`id`)\n);"},
-				{Type: plugin.ConnectionTreeActionDropDatabase, Title: "Drop database", Query: fmt.Sprintf("DROP DATABASE `%s`;", dbname)},
-			},
+			Actions:  databaseActions(dbname),
 		})
 	}
 
@@ -420,6 +571,24 @@ func (m *mysqlPlugin) ConnectionTree(ctx context.Context, req *plugin.Connection
 	return &plugin.ConnectionTreeResponse{Nodes: append([]*plugin.ConnectionTreeNode{createNode}, dbNodes...)}, nil
 }
 
+// tableActions returns the context-menu actions of a table node: the hidden
+// row preview fired on click, an index listing and the destructive drop.
+func tableActions(dbname, tbl string) []*plugin.ConnectionTreeAction {
+	return []*plugin.ConnectionTreeAction{
+		{Type: plugin.ConnectionTreeActionSelect, Title: "Select rows", Query: fmt.Sprintf("SELECT * FROM `%s` LIMIT 100;", tbl), Hidden: true, NewTab: true},
+		{Type: plugin.ConnectionTreeActionDescribe, Title: "Show indexes", Query: showIndexQuery(dbname, tbl), NewTab: true},
+		{Type: plugin.ConnectionTreeActionDropTable, Title: "Drop table", Query: fmt.Sprintf("DROP TABLE `%s`;", tbl), Destructive: true},
+	}
+}
+
+// databaseActions returns the DDL actions of a database node.
+func databaseActions(dbname string) []*plugin.ConnectionTreeAction {
+	return []*plugin.ConnectionTreeAction{
+		{Type: plugin.ConnectionTreeActionCreateTable, Title: "Create table", Query: "CREATE TABLE `new_table` (\n  `id` INT NOT NULL AUTO_INCREMENT,\n  PRIMARY KEY (`id`)\n);"},
+		{Type: plugin.ConnectionTreeActionDropDatabase, Title: "Drop database", Query: fmt.Sprintf("DROP DATABASE `%s`;", dbname), Destructive: true},
+	}
+}
+
 // TestConnection opens a MySQL connection and pings the server to verify the
 // supplied credentials are valid. Nothing is persisted.
 // GetCompletionFields returns column names and types for the given table,
@@ -485,7 +654,7 @@ func (m *mysqlPlugin) GetCompletionFields(ctx context.Context, req *plugin.GetCo
 }
 
 func (m *mysqlPlugin) TestConnection(ctx context.Context, req *plugin.TestConnectionRequest) (*plugin.TestConnectionResponse, error) {
-	dsn, err := buildDSN(req.Connection)
+	dsn, err := buildDSN(plugin.WithCredentialForm(req.Connection, req.GetForm()))
 	if err != nil || dsn == "" {
 		msg := "invalid connection parameters"
 		if err != nil {
@@ -493,15 +662,46 @@ func (m *mysqlPlugin) TestConnection(ctx context.Context, req *plugin.TestConnec
 		}
 		return &plugin.TestConnectionResponse{Ok: false, Message: msg}, nil
 	}
-	db, err := sql.Open("mysql", dsn)
+	db, err := openMySQLDB(dsn)
 	if err != nil {
 		return &plugin.TestConnectionResponse{Ok: false, Message: fmt.Sprintf("open error: %v", err)}, nil
 	}
 	defer db.Close()
-	if err := db.Ping(); err != nil {
+	latency, err := plugin.PingLatency(ctx, db)
+	if err != nil {
 		return &plugin.TestConnectionResponse{Ok: false, Message: fmt.Sprintf("ping error: %v", err)}, nil
 	}
-	return &plugin.TestConnectionResponse{Ok: true, Message: "Connection successful"}, nil
+	return &plugin.TestConnectionResponse{
+		Ok:         true,
+		Message:    "Connection successful",
+		LatencyMs:  &latency,
+		ServerInfo: plugin.SQLServerInfo(ctx, db, "SELECT VERSION(), @@version_comment", "version", "edition"),
+	}, nil
+}
+
+// GenerateDDL returns the `SHOW CREATE TABLE` statement for the table behind
+// a connection tree node key ("db.table").
+func (m *mysqlPlugin) GenerateDDL(ctx context.Context, req *plugin.GenerateDDLRequest) (*plugin.GenerateDDLResponse, error) {
+	dbName, table, ok := strings.Cut(req.NodeKey, ".")
+	if !ok || dbName == "" || table == "" {
+		return &plugin.GenerateDDLResponse{Error: fmt.Sprintf("cannot generate DDL for node %q: expected db.table", req.NodeKey)}, nil
+	}
+	dsn, err := buildDSN(req.Connection)
+	if err != nil || dsn == "" {
+		return &plugin.GenerateDDLResponse{Error: "invalid connection"}, nil
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: fmt.Sprintf("open error: %v", err)}, nil
+	}
+	defer db.Close()
+
+	var name, ddl string
+	q := fmt.Sprintf("SHOW CREATE TABLE `%s`.`%s`", escapeBacktick(dbName), escapeBacktick(table))
+	if err := db.QueryRowContext(ctx, q).Scan(&name, &ddl); err != nil {
+		return &plugin.GenerateDDLResponse{Error: err.Error()}, nil
+	}
+	return &plugin.GenerateDDLResponse{Ddl: ddl + ";"}, nil
 }
 
 // escapeBacktick doubles any backtick characters in s so it can be safely
@@ -510,6 +710,12 @@ func escapeBacktick(s string) string {
 	return strings.ReplaceAll(s, "`", "``")
 }
 
+// showIndexQuery returns the statement listing the indexes of db.table; the
+// server answers with one row per indexed column, which renders as a table.
+func showIndexQuery(db, table string) string {
+	return fmt.Sprintf("SHOW INDEX FROM `%s`.`%s`;", escapeBacktick(db), escapeBacktick(table))
+}
+
 // quoteSource wraps a table reference in backticks, handling the optional
 // "database.table" form produced by DescribeSchema (e.g. "employees.users"
 // becomes `employees`.`users`).
@@ -2,9 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/felixdotgo/querybox/pkg/plugin"
 	"github.com/go-sql-driver/mysql"
 )
@@ -47,6 +60,136 @@ func TestBuildDSNTLSParam(t *testing.T) {
     }
 }
 
+func TestBuildDSNUnixSocket(t *testing.T) {
+    conn := map[string]string{"credential_blob": plugin.MakeTestBlob(map[string]string{
+        "host": "127.0.0.1", "port": "3306", "user": "me", "password": "pw", "database": "db1",
+        "socket": "/var/run/mysqld/mysqld.sock", "tls": "skip-verify",
+    })}
+
+    dsn, err := buildDSN(conn)
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if !strings.HasPrefix(dsn, "me:pw@unix(/var/run/mysqld/mysqld.sock)/db1") {
+        t.Errorf("expected unix socket dsn, got %q", dsn)
+    }
+    if strings.Contains(dsn, "tcp(") || strings.Contains(dsn, "tls=") || strings.Contains(dsn, "socket=") {
+        t.Errorf("socket dsn should omit tcp host, tls and the socket param, got %q", dsn)
+    }
+    cfg, err := mysql.ParseDSN(dsn)
+    if err != nil {
+        t.Fatalf("driver rejected dsn %q: %v", dsn, err)
+    }
+    if cfg.Net != "unix" || cfg.Addr != "/var/run/mysqld/mysqld.sock" || cfg.DBName != "db1" {
+        t.Errorf("parsed config = net %q addr %q db %q", cfg.Net, cfg.Addr, cfg.DBName)
+    }
+}
+
+// writeTestCA writes a self-signed CA certificate to a temp PEM file and
+// returns the path and the parsed certificate.
+func writeTestCA(t *testing.T) (string, *x509.Certificate) {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    tmpl := &x509.Certificate{
+        SerialNumber:          big.NewInt(1),
+        Subject:               pkix.Name{CommonName: "querybox test CA"},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        IsCA:                  true,
+        KeyUsage:              x509.KeyUsageCertSign,
+        BasicConstraintsValid: true,
+    }
+    der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+    if err != nil {
+        t.Fatal(err)
+    }
+    cert, err := x509.ParseCertificate(der)
+    if err != nil {
+        t.Fatal(err)
+    }
+    path := filepath.Join(t.TempDir(), "ca.pem")
+    if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    return path, cert
+}
+
+func TestBuildDSNVerifyTLSRegistersConfig(t *testing.T) {
+    caPath, caCert := writeTestCA(t)
+    for _, mode := range []string{"verify-ca", "verify-full"} {
+        t.Run(mode, func(t *testing.T) {
+            conn := map[string]string{"credential_blob": plugin.MakeTestBlob(map[string]string{
+                "host": "db.example.com", "database": "db1", "tls": mode, "ca_cert": caPath,
+            })}
+            dsn, err := buildDSN(conn)
+            if err != nil {
+                t.Fatalf("unexpected error: %v", err)
+            }
+            if strings.Contains(dsn, "ca_cert") {
+                t.Errorf("ca_cert leaked into dsn: %q", dsn)
+            }
+            cfg, err := mysql.ParseDSN(dsn)
+            if err != nil {
+                t.Fatalf("dsn references an unregistered TLS config: %v", err)
+            }
+            if !strings.HasPrefix(cfg.TLSConfig, "querybox-"+mode+"-") {
+                t.Errorf("expected tls=querybox-%s-*, got %q", mode, cfg.TLSConfig)
+            }
+            if cfg.TLS == nil || cfg.TLS.RootCAs == nil {
+                t.Fatal("registered TLS config has no root pool")
+            }
+            if _, err := caCert.Verify(x509.VerifyOptions{Roots: cfg.TLS.RootCAs}); err != nil {
+                t.Errorf("user CA not trusted by registered config: %v", err)
+            }
+            if skip := cfg.TLS.InsecureSkipVerify; skip != (mode == "verify-ca") {
+                t.Errorf("InsecureSkipVerify = %v for %s", skip, mode)
+            }
+        })
+    }
+}
+
+// buildDSN reads the connection through plugin.ParseConnection, so a flat
+// map yields the same DSN as the equivalent credential blob and a flat key
+// overrides the blob value of the same name.
+func TestBuildDSNFlatMatchesBlob(t *testing.T) {
+    fields := map[string]string{"host": "db", "user": "me", "password": "pw", "database": "app", "tls": "skip-verify"}
+    flat, err := buildDSN(fields)
+    if err != nil {
+        t.Fatalf("flat: unexpected error: %v", err)
+    }
+    blob, err := buildDSN(map[string]string{"credential_blob": plugin.MakeTestBlob(fields)})
+    if err != nil {
+        t.Fatalf("blob: unexpected error: %v", err)
+    }
+    if flat != blob {
+        t.Errorf("flat map gave %q, blob gave %q", flat, blob)
+    }
+
+    over, err := buildDSN(map[string]string{"credential_blob": plugin.MakeTestBlob(fields), "database": "other"})
+    if err != nil {
+        t.Fatalf("override: unexpected error: %v", err)
+    }
+    if cfg, err := mysql.ParseDSN(over); err != nil || cfg.DBName != "other" {
+        t.Errorf("expected the flat database value to win, got %q", over)
+    }
+
+    if _, err := buildDSN(map[string]string{"credential_blob": "{not json"}); err == nil {
+        t.Error("expected an error for a malformed credential blob")
+    }
+}
+
+func TestBuildDSNVerifyTLSMissingCA(t *testing.T) {
+    conn := map[string]string{"credential_blob": plugin.MakeTestBlob(map[string]string{
+        "host": "db.example.com", "tls": "verify-full", "ca_cert": filepath.Join(t.TempDir(), "missing.pem"),
+    })}
+    if _, err := buildDSN(conn); err == nil {
+        t.Fatal("expected error for unreadable CA certificate")
+    }
+}
+
 func TestDescribeSchemaInvalid(t *testing.T) {
     m := &mysqlPlugin{}
     resp, err := m.DescribeSchema(context.Background(), &plugin.DescribeSchemaRequest{Connection: map[string]string{}})
@@ -188,3 +331,206 @@ func TestMutateRowBuildDSNDerivesDBFromSource(t *testing.T) {
         t.Errorf("rebuilt DSN %q does not contain the derived database", rebuilt)
     }
 }
+
+func TestApplyExplainMySQL(t *testing.T) {
+    tests := []struct {
+        name    string
+        options map[string]string
+        want    string
+    }{
+        {"no options", nil, "SELECT 1"},
+        {"explain not requested", map[string]string{"sort-column": "id"}, "SELECT 1"},
+        {"explain off", map[string]string{"explain-query": "no"}, "SELECT 1"},
+        {"explain", map[string]string{"explain-query": "yes"}, "EXPLAIN SELECT 1"},
+        {"explain json", map[string]string{"explain-query": "yes", "explain-format": "json"}, "EXPLAIN FORMAT=JSON SELECT 1"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := applyExplainMySQL("SELECT 1", tt.options); got != tt.want {
+                t.Errorf("applyExplainMySQL() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestClassifyMySQLError(t *testing.T) {
+    tests := []struct {
+        name string
+        err  error
+        want plugin.ErrorCode
+    }{
+        {"access denied", &mysql.MySQLError{Number: 1045}, plugin.ErrorCodeAuthFailed},
+        {"parse error", &mysql.MySQLError{Number: 1064}, plugin.ErrorCodeSyntaxError},
+        {"no such table", &mysql.MySQLError{Number: 1146}, plugin.ErrorCodeNotFound},
+        {"unknown database", &mysql.MySQLError{Number: 1049}, plugin.ErrorCodeNotFound},
+        {"max execution time", &mysql.MySQLError{Number: 3024}, plugin.ErrorCodeTimeout},
+        {"duplicate entry", &mysql.MySQLError{Number: 1062}, plugin.ErrorCodeUnknown},
+        {"bad connection", mysql.ErrInvalidConn, plugin.ErrorCodeConnectionFailed},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := classifyMySQLError(tt.err); got != tt.want {
+                t.Errorf("classifyMySQLError(%v) = %v, want %v", tt.err, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestShowIndexQuery(t *testing.T) {
+    tests := []struct {
+        db, table, want string
+    }{
+        {"shop", "orders", "SHOW INDEX FROM `shop`.`orders`;"},
+        {"my.db", "order items", "SHOW INDEX FROM `my.db`.`order items`;"},
+        {"odd`db", "t`", "SHOW INDEX FROM `odd``db`.`t```;"},
+    }
+    for _, tt := range tests {
+        if got := showIndexQuery(tt.db, tt.table); got != tt.want {
+            t.Errorf("showIndexQuery(%q, %q) = %q, want %q", tt.db, tt.table, got, tt.want)
+        }
+    }
+}
+
+func TestGenerateDDLRejectsNonTableKey(t *testing.T) {
+    m := &mysqlPlugin{}
+    for _, key := range []string{"", "mydb", "mydb.", "__create_database__"} {
+        resp, err := m.GenerateDDL(context.Background(), &plugin.GenerateDDLRequest{NodeKey: key})
+        if err != nil {
+            t.Fatalf("GenerateDDL(%q) unexpected error: %v", key, err)
+        }
+        if resp.Error == "" || resp.Ddl != "" {
+            t.Errorf("GenerateDDL(%q): expected an error, got %+v", key, resp)
+        }
+    }
+}
+
+func TestTreeActionsMarkDropDestructive(t *testing.T) {
+    actions := append(tableActions("shop", "orders"), databaseActions("shop")...)
+    for _, a := range actions {
+        isDrop := a.Type == plugin.ConnectionTreeActionDropTable || a.Type == plugin.ConnectionTreeActionDropDatabase
+        if a.Destructive != isDrop {
+            t.Errorf("%s action: Destructive = %v, want %v", a.Type, a.Destructive, isDrop)
+        }
+    }
+}
+
+func TestExecReportsRowsAffected(t *testing.T) {
+    orig := openMySQLDB
+    defer func() { openMySQLDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openMySQLDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectExec("INSERT INTO users (name) VALUES ('a'), ('b')").WillReturnResult(sqlmock.NewResult(41, 2))
+
+    m := &mysqlPlugin{}
+    resp, err := m.Exec(context.Background(), &plugin.ExecRequest{
+        Connection: map[string]string{"dsn": "root@tcp(127.0.0.1:3306)/app"},
+        Query:      "INSERT INTO users (name) VALUES ('a'), ('b')",
+    })
+    if err != nil || resp.Error != "" {
+        t.Fatalf("Exec: %v %s", err, resp.GetError())
+    }
+    res := resp.GetResult().GetSql()
+    if res.RowsAffected == nil || res.GetRowsAffected() != 2 || res.GetLastInsertId() != 41 {
+        t.Errorf("rows affected = %v, last insert id = %v", res.RowsAffected, res.LastInsertId)
+    }
+    if len(res.GetColumns()) != 0 {
+        t.Errorf("DML result should have no columns, got %v", res.GetColumns())
+    }
+    if _, ok := resp.GetResult().GetMetadata()[plugin.MetadataDurationMS]; !ok {
+        t.Errorf("metadata = %v, want %s", resp.GetResult().GetMetadata(), plugin.MetadataDurationMS)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+func TestExecCollectsWarnings(t *testing.T) {
+    orig := openMySQLDB
+    defer func() { openMySQLDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openMySQLDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectQuery("SELECT CAST('12abc' AS SIGNED) AS n").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(12))
+    mock.ExpectQuery("SHOW WARNINGS").WillReturnRows(sqlmock.NewRows([]string{"Level", "Code", "Message"}).
+        AddRow("Warning", 1292, "Truncated incorrect INTEGER value: '12abc'"))
+
+    m := &mysqlPlugin{}
+    resp, err := m.Exec(context.Background(), &plugin.ExecRequest{
+        Connection: map[string]string{"dsn": "root@tcp(127.0.0.1:3306)/app"},
+        Query:      "SELECT CAST('12abc' AS SIGNED) AS n",
+    })
+    if err != nil || resp.Error != "" {
+        t.Fatalf("Exec: %v %s", err, resp.GetError())
+    }
+    want := "Warning 1292: Truncated incorrect INTEGER value: '12abc'"
+    if w := resp.GetResult().GetWarnings(); len(w) != 1 || w[0] != want {
+        t.Errorf("warnings = %q, want [%q]", w, want)
+    }
+    if len(resp.GetResult().GetSql().GetRows()) != 1 {
+        t.Errorf("rows = %v", resp.GetResult().GetSql().GetRows())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+func TestFormatMySQLValue(t *testing.T) {
+    tests := []struct {
+        name     string
+        typeName string
+        input    interface{}
+        want     string
+    }{
+        {"json pretty printed", "JSON", []byte(`{"a":1,"tags":["x","y"]}`), "{\n  \"a\": 1,\n  \"tags\": [\n    \"x\",\n    \"y\"\n  ]\n}"},
+        {"json scalar", "JSON", []byte(`"plain"`), `"plain"`},
+        {"invalid json kept raw", "JSON", []byte(`{not json`), "{not json"},
+        {"text untouched", "VARCHAR", []byte(`{"a":1}`), `{"a":1}`},
+        {"nil", "JSON", nil, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := formatMySQLValue(tt.typeName, tt.input); got != tt.want {
+                t.Errorf("formatMySQLValue(%q, %q) = %q, want %q", tt.typeName, tt.input, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestTestConnectionReportsLatencyAndServerInfo(t *testing.T) {
+    orig := openMySQLDB
+    defer func() { openMySQLDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true), sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openMySQLDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectPing()
+    mock.ExpectQuery("SELECT VERSION(), @@version_comment").WillReturnRows(
+        sqlmock.NewRows([]string{"VERSION()", "@@version_comment"}).AddRow("8.0.36", "MySQL Community Server - GPL"))
+
+    m := &mysqlPlugin{}
+    resp, err := m.TestConnection(context.Background(), &plugin.TestConnectionRequest{
+        Connection: map[string]string{"dsn": "root@tcp(127.0.0.1:3306)/app"},
+    })
+    if err != nil || !resp.GetOk() {
+        t.Fatalf("TestConnection: %v %s", err, resp.GetMessage())
+    }
+    if resp.LatencyMs == nil {
+        t.Error("latency not populated")
+    }
+    want := map[string]string{"version": "8.0.36", "edition": "MySQL Community Server - GPL"}
+    if !reflect.DeepEqual(resp.GetServerInfo(), want) {
+        t.Errorf("server info = %v, want %v", resp.GetServerInfo(), want)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
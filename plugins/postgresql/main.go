@@ -1,19 +1,26 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/felixdotgo/querybox/pkg/certs"
 	"github.com/felixdotgo/querybox/pkg/plugin"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
 
-	_ "github.com/lib/pq" // postgres driver
+	"github.com/lib/pq" // postgres driver
 )
 
 // postgresqlPlugin implements the protobuf PluginServiceServer interface for a simple PostgreSQL executor.
@@ -29,10 +36,15 @@ func (m *postgresqlPlugin) Info(ctx context.Context, _ *pluginpb.PluginV1_InfoRe
 		Description: "PostgreSQL database driver",
 		Url:         "https://www.postgresql.org/",
 		Author:      "PostgreSQL Global Development Group",
-		Capabilities: []string{"query", "explain-query", "mutate-row", "describe-schema"},
+		Capabilities: []string{"query", "explain-query", "mutate-row", "describe-schema", "ddl"},
 		Tags:        []string{"sql", "relational"},
 		License:     "PostgreSQL",
 		IconUrl:     "https://www.postgresql.org/media/img/about/press/elephant.png",
+		ExampleQueries: []string{
+			"SELECT table_schema, table_name FROM information_schema.tables WHERE table_schema NOT IN ('pg_catalog', 'information_schema') ORDER BY 1, 2;",
+			`SELECT * FROM "table_name" LIMIT 100;`,
+			"SELECT version();",
+		},
 	}, nil
 }
 
@@ -42,7 +54,7 @@ func (m *postgresqlPlugin) AuthForms(ctx context.Context, _ *plugin.AuthFormsReq
 		Key: "basic",
 		Name: "Basic",
 		Fields: []*plugin.AuthField{
-			{Type: plugin.AuthFieldText, Name: "host", Label: "Host", Required: true, Placeholder: "127.0.0.1", Value: "localhost"},
+			{Type: plugin.AuthFieldText, Name: "host", Label: "Host or socket directory", Required: true, Placeholder: "127.0.0.1 or /var/run/postgresql", Value: "localhost"},
 			{Type: plugin.AuthFieldNumber, Name: "port", Label: "Port", Placeholder: "5432", Value: "5432"},
 			{Type: plugin.AuthFieldText, Name: "user", Label: "User", Value: "postgres"},
 			{Type: plugin.AuthFieldPassword, Name: "password", Label: "Password"},
@@ -50,6 +62,8 @@ func (m *postgresqlPlugin) AuthForms(ctx context.Context, _ *plugin.AuthFormsReq
 			// allow tls and extra params similar to mysql
 			{Type: plugin.AuthFieldSelect, Name: "tls", Label: "TLS mode (e.g. disable/require)", Options: []string{"disable", "require", "verify-ca", "verify-full"}, Value: "disable"},
 			{Type: plugin.AuthFieldText, Name: "params", Label: "Extra params", Placeholder: "connect_timeout=5&application_name=myapp"},
+			{Type: plugin.AuthFieldNumber, Name: "statement_timeout", Label: "Statement timeout (ms, 0 = none)", Placeholder: "30000"},
+			{Type: plugin.AuthFieldText, Name: "schema", Label: "Default schema (search_path)", Placeholder: "public"},
 		},
 	}
 
@@ -136,10 +150,22 @@ func setSSLMode(dsn, mode string) string {
     return dsn + " sslmode=" + mode
 }
 
-// buildConnString constructs a postgres keyword=value connection string from
-// the provided connection map.  Extra DSN parameters are appended as
-// space-separated key=value pairs as required by lib/pq; URL-encoded (&)
-// format is NOT used because it is invalid for the postgres DSN format.
+// isSocketDir reports whether a host value names the directory holding the
+// server's Unix domain socket (e.g. /var/run/postgresql) rather than a TCP
+// host.  lib/pq connects to <dir>/.s.PGSQL.<port> for such hosts, so the
+// port still selects the socket file.
+func isSocketDir(host string) bool {
+	return strings.HasPrefix(host, "/")
+}
+
+// buildConnString constructs a postgres connection string from the values
+// plugin.ParseConnection reads from the connection map, so a flat map and a
+// credential_blob follow the same precedence rules.  A "dsn" value is used
+// as given (a "tls" value overrides its sslmode); otherwise a keyword=value
+// string is built from host, port, user, password and database.  Remaining
+// values and the "params" field are appended as extra DSN parameters:
+// space-separated key=value pairs for a keyword DSN as required by lib/pq,
+// query parameters for a URL DSN.
 //
 // Historically we ignored the "database" field when a raw DSN was present.
 // that meant that ConnectionTree created new connections for each database but
@@ -147,118 +173,122 @@ func setSSLMode(dsn, mode string) string {
 // databases in the tree showed the same schemas/tables.  This helper now
 // overrides the DSN if a database override is supplied.
 func buildConnString(connection map[string]string) (string, error) {
-	// honour explicit DSN value and still ensure sslmode defaults correctly
-	if dsn, ok := connection["dsn"]; ok && dsn != "" {
-		// if the caller also supplied a "database" field, it should override
-		// whatever database is encoded in the DSN.
-		if db, ok2 := connection["database"]; ok2 && db != "" {
-			var err error
+	_, values, err := plugin.ParseConnection(connection)
+	if err != nil {
+		return "", err
+	}
+	dsn := values["dsn"]
+	if dsn != "" {
+		if tls := values["tls"]; tls != "" {
+			dsn = setSSLMode(dsn, tls)
+		}
+		// Apply an explicit database override, e.g. injected by
+		// ConnectionTree when scanning non-current databases.
+		if db := values["database"]; db != "" {
 			dsn, err = overrideDatabaseInDSN(dsn, db)
 			if err != nil {
 				return "", err
 			}
 		}
-		if tls, ok2 := connection["tls"]; ok2 && tls != "" {
-			dsn = setSSLMode(dsn, tls)
+	} else if host := values["host"]; host != "" {
+		port := values["port"]
+		if port == "" {
+			port = "5432"
+		}
+		// The "tls" form field carries a postgres sslmode value
+		// (disable / require / verify-ca / verify-full).
+		sslmode := values["tls"]
+		if sslmode == "" || isSocketDir(host) {
+			// TLS is meaningless over a local Unix socket
+			sslmode = "disable"
+		}
+		// build keyword-style DSN; omit dbname when blank.  Including
+		// an empty "dbname=" followed by a space could cause lib/pq to
+		// treat the next token (e.g. "sslmode=disable") as the
+		// database name, which is what was reported by users.
+		parts := []string{
+			"host=" + host,
+			"port=" + port,
+			"user=" + values["user"],
+			"password=" + values["password"],
 		}
-		return ensureSSLMode(dsn), nil
+		if dbname := values["database"]; dbname != "" {
+			parts = append(parts, "dbname="+dbname)
+		}
+		parts = append(parts, "sslmode="+sslmode)
+		dsn = strings.Join(parts, " ")
+	}
+	if dsn == "" {
+		return "", nil
 	}
-	dsn, ok := connection["dsn"]
-	if !ok || dsn == "" {
-		if cred, err := plugin.ParseCredentialBlob(connection); err == nil {
-				if v, ok := cred.Values["dsn"]; ok && v != "" {
-					dsn = ensureSSLMode(v)
-				} else {
-					host := cred.Values["host"]
-					user := cred.Values["user"]
-					pass := cred.Values["password"]
-					port := cred.Values["port"]
-					dbname := cred.Values["database"]
-					// The "tls" form field carries a postgres sslmode value
-					// (disable / require / verify-ca / verify-full).
-					sslmode := cred.Values["tls"]
-					if port == "" {
-						port = "5432"
-					}
-					if sslmode == "" {
-						sslmode = "disable"
-					}
-
-					if host != "" {
-						// build keyword-style DSN; omit dbname when blank.  Including
-						// an empty "dbname=" followed by a space could cause lib/pq to
-						// treat the next token (e.g. "sslmode=disable") as the
-						// database name, which is what was reported by users.
-						parts := []string{
-							"host=" + host,
-							"port=" + port,
-							"user=" + user,
-							"password=" + pass,
-						}
-						if dbname != "" {
-							parts = append(parts, "dbname="+dbname)
-						}
-						parts = append(parts, "sslmode="+sslmode)
-						dsn = strings.Join(parts, " ")
-					}
-				}
 
-				// Append extra postgres DSN params as space-separated key=value
-				// pairs.  The "tls", "params", and core credential fields are
-				// excluded here because they are handled above or parsed below.
-				if dsn != "" {
-					skip := map[string]bool{
-						"host": true, "user": true, "password": true,
-						"port": true, "database": true, "dsn": true,
-						"tls": true, "params": true,
-					}
-					var extra []string
-					for k, v := range cred.Values {
-						if skip[k] || v == "" {
-							continue
-						}
-						extra = append(extra, fmt.Sprintf("%s=%s", k, v))
-					}
-					// The "params" field lets users supply additional DSN
-					// key=value pairs separated by spaces or "&".
-					if raw := cred.Values["params"]; raw != "" {
-						for _, part := range strings.FieldsFunc(raw, func(r rune) bool {
-							return r == '&' || r == ' '
-						}) {
-							if kv := strings.SplitN(part, "=", 2); len(kv) == 2 && kv[1] != "" {
-								extra = append(extra, fmt.Sprintf("%s=%s", kv[0], kv[1]))
-							}
-						}
-					}
-					// Ensure a sensible default connect timeout when the caller
-					// has not specified one explicitly.
-					hasTimeout := strings.Contains(dsn, "connect_timeout")
-					for _, e := range extra {
-						if strings.HasPrefix(e, "connect_timeout=") {
-							hasTimeout = true
-						}
-					}
-					if !hasTimeout {
-						extra = append(extra, "connect_timeout=5")
-					}
-					if len(extra) > 0 {
-						dsn = dsn + " " + strings.Join(extra, " ")
-					}
-				}
+	// Extra DSN params.  The "tls", "params", and core credential fields
+	// are excluded here because they are handled above or parsed below.
+	skip := map[string]bool{
+		"host": true, "user": true, "password": true,
+		"port": true, "database": true, "dsn": true,
+		"tls": true, "params": true,
+		"statement_timeout": true, "schema": true,
+	}
+	extra := url.Values{}
+	for k, v := range values {
+		if skip[k] || v == "" {
+			continue
 		}
+		extra.Add(k, v)
 	}
-	// Apply explicit database override that may have been injected by ConnectionTree
-	// when scanning non-current databases.  The credential_blob paths above build the
-	// DSN from blob fields without knowledge of this override, so we apply it here
-	// once, covering both the embedded-DSN and separate-fields blob forms.
-	if db, ok := connection["database"]; ok && db != "" && dsn != "" {
-		if newDSN, oErr := overrideDatabaseInDSN(dsn, db); oErr == nil {
-			dsn = newDSN
+	// The "params" field lets users supply additional DSN key=value pairs
+	// separated by spaces or "&"; a key or value may percent-escape those
+	// separators (connection URL imports do).
+	if raw := values["params"]; raw != "" {
+		for _, part := range strings.FieldsFunc(raw, func(r rune) bool {
+			return r == '&' || r == ' '
+		}) {
+			if kv := strings.SplitN(part, "=", 2); len(kv) == 2 && kv[1] != "" {
+				extra.Add(unescapeParam(kv[0]), unescapeParam(kv[1]))
+			}
 		}
 	}
+	// Ensure a sensible default connect timeout when the caller has not
+	// specified one explicitly.
+	if !strings.Contains(dsn, "connect_timeout") && extra.Get("connect_timeout") == "" {
+		extra.Set("connect_timeout", "5")
+	}
+	dsn = appendDSNParams(dsn, extra)
+
 	// final normalisation
-	dsn = ensureSSLMode(dsn)
-	return dsn, nil
+	return ensureSSLMode(dsn), nil
+}
+
+// appendDSNParams adds params to dsn: as query parameters for a URL DSN, and
+// as space-separated key=value pairs (in key order, values quoted where
+// needed) for a keyword DSN.
+func appendDSNParams(dsn string, params url.Values) string {
+	if len(params) == 0 {
+		return dsn
+	}
+	if u, err := url.Parse(dsn); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		q := u.Query()
+		for k, vs := range params {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := []string{dsn}
+	for _, k := range keys {
+		for _, v := range params[k] {
+			parts = append(parts, k+"="+quoteDSNValue(v))
+		}
+	}
+	return strings.Join(parts, " ")
 }
 
 // overrideDatabaseInDSN returns a copy of the supplied DSN with its database
@@ -299,9 +329,151 @@ func overrideDatabaseInDSN(dsn, database string) (string, error) {
 	}
 	return strings.Join(out, " "), nil
 }
+
+// unescapeParam decodes percent-escapes in a "params" key or value.  Text
+// that is not a valid escape sequence is kept as typed.
+func unescapeParam(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// quoteDSNValue quotes v for a keyword/value DSN when it is empty or contains
+// spaces, quotes or backslashes.
+func quoteDSNValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, " '\\") {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// statementTimeoutMS returns the statement_timeout (milliseconds) requested
+// via the connection map or the credential blob's "statement_timeout" field.
+// Missing, non-numeric and non-positive values yield 0 (no timeout).
+func statementTimeoutMS(connection map[string]string) int {
+	_, values, _ := plugin.ParseConnection(connection)
+	ms, err := strconv.Atoi(strings.TrimSpace(values["statement_timeout"]))
+	if err != nil || ms <= 0 {
+		return 0
+	}
+	return ms
+}
+
+// searchPathSchema returns the default schema requested via the connection
+// map or the credential blob's "schema" field, or "" to keep the server's
+// search_path.
+func searchPathSchema(connection map[string]string) string {
+	_, values, _ := plugin.ParseConnection(connection)
+	return strings.TrimSpace(values["schema"])
+}
+
+// setSearchPathStatement returns the SET issued for a default schema.  The
+// name is always sent as a quoted identifier so it can neither inject SQL
+// nor be case-folded by the server.
+func setSearchPathStatement(schema string) string {
+	return fmt.Sprintf(`SET search_path TO "%s"`, escapeDoubleQuote(schema))
+}
+
 // openPostgresDB wraps sql.Open so unit tests can replace it with a mock.
+// Server notices raised on its connections are recorded in pgNotices.
 var openPostgresDB = func(dsn string) (*sql.DB, error) {
-	return sql.Open("postgres", dsn)
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(pq.ConnectorWithNoticeHandler(connector, pgNotices.add)), nil
+}
+
+// pgNotices collects the NOTICE/WARNING messages (RAISE NOTICE, implicit
+// casts, "table does not exist, skipping", ...) the server sends while a
+// statement runs; Exec returns them as ExecResult.Warnings.  The plugin runs
+// one request per process, so a package-level log is enough.
+var pgNotices noticeLog
+
+type noticeLog struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *noticeLog) add(e *pq.Error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, fmt.Sprintf("%s: %s", e.Severity, e.Message))
+}
+
+// drain returns the recorded notices and clears the log.
+func (l *noticeLog) drain() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	msgs := l.msgs
+	l.msgs = nil
+	return msgs
+}
+
+// backendPIDQuery reports the server process serving the current session.
+// Exec records it so a cancelled request can stop its statement server-side.
+const backendPIDQuery = "SELECT pg_backend_pid()"
+
+// cancelBackendQuery asks the server to cancel whatever statement the given
+// backend is running.  It must be sent from a different session.
+const cancelBackendQuery = "SELECT pg_cancel_backend($1)"
+
+// cancelBackendTimeout bounds the side connection opened by cancelBackend.
+const cancelBackendTimeout = 5 * time.Second
+
+// watchCancel captures the backend PID of db's session and, if ctx is
+// cancelled before the returned stop func is called, cancels that backend's
+// running statement from a second connection to dsn.  db must be pinned to a
+// single connection so the PID belongs to the session that runs the user's
+// statement.  A context that can never be cancelled, or a server that will
+// not report its PID, leaves nothing to watch.
+func watchCancel(ctx context.Context, db *sql.DB, dsn string) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	var pid int
+	if err := db.QueryRowContext(ctx, backendPIDQuery).Scan(&pid); err != nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+			if err := cancelBackend(dsn, pid); err != nil {
+				fmt.Fprintf(os.Stderr, "postgresql: Exec: cancel backend %d: %v\n", pid, err)
+			}
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// cancelBackend opens a fresh connection to dsn and calls pg_cancel_backend
+// for pid.  The request's own session is busy running the statement, so the
+// cancel has to travel over a second one.
+func cancelBackend(dsn string, pid int) error {
+	db, err := openPostgresDB(dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), cancelBackendTimeout)
+	defer cancel()
+	var signalled bool
+	if err := db.QueryRowContext(ctx, cancelBackendQuery, pid).Scan(&signalled); err != nil {
+		return err
+	}
+	if !signalled {
+		return fmt.Errorf("backend %d was not signalled", pid)
+	}
+	return nil
 }
 
 // getDatabaseFromConn extracts a requested database name from the
@@ -309,25 +481,14 @@ var openPostgresDB = func(dsn string) (*sql.DB, error) {
 // credential_blob payload, and finally any dbname element in a supplied
 // DSN string.  An empty return value indicates no preference.
 func getDatabaseFromConn(conn map[string]string) string {
-	if db, ok := conn["database"]; ok && db != "" {
-		return db
-	}
-	if cred, err := plugin.ParseCredentialBlob(conn); err == nil {
-		if v, ok := cred.Values["database"]; ok && v != "" {
-			return v
-		}
-		if v, ok := cred.Values["dsn"]; ok && v != "" {
-			if name := extractDBName(v); name != "" {
-				return name
-			}
-		}
+	_, values, err := plugin.ParseConnection(conn)
+	if err != nil {
+		return ""
 	}
-	if dsn, ok := conn["dsn"]; ok && dsn != "" {
-		if name := extractDBName(dsn); name != "" {
-			return name
-		}
+	if db := values["database"]; db != "" {
+		return db
 	}
-	return ""
+	return extractDBName(values["dsn"])
 }
 
 // extractDBName returns the database name found in the provided DSN string.
@@ -451,6 +612,12 @@ func applySortPQ(query, column, direction string) string {
 }
 
 func (m *postgresqlPlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plugin.ExecResponse, error) {
+	if isCopyToStdout(req.Query) {
+		return &plugin.ExecResponse{
+			Error:     "COPY ... TO STDOUT is not supported: the PostgreSQL driver cannot read COPY output; run the query itself and export the result as CSV instead",
+			ErrorCode: plugin.ErrorCodeUnsupported,
+		}, nil
+	}
 	if req.Options != nil {
 		if v, ok := req.Options["explain-query"]; ok && v == "yes" {
 			req.Query = "EXPLAIN " + req.Query
@@ -463,6 +630,7 @@ func (m *postgresqlPlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*
 			req.Query = applySortPQ(req.Query, col, dir)
 		}
 	}
+	req.Query = plugin.ApplyLimitOffset(req.Query, req.GetLimit(), req.GetOffset())
 	dsn, err := buildConnString(req.Connection)
 	if err != nil {
 		return &plugin.ExecResponse{Error: fmt.Sprintf("invalid connection: %v", err)}, nil
@@ -471,56 +639,177 @@ func (m *postgresqlPlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*
 		return &plugin.ExecResponse{Error: "missing dsn in connection"}, nil
 	}
 
-	// open postgres driver (custom hook for testing)
+	// open postgres driver (custom hook for testing); notices raised from
+	// here on belong to this request
+	pgNotices.drain()
 	db, err := openPostgresDB(dsn)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("open error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: fmt.Sprintf("open error: %v", err), ErrorCode: classifyPQError(err)}, nil
 	}
 	defer db.Close()
 
+	// A server-side statement_timeout cancels runaway queries cleanly instead
+	// of relying on the host killing the process, and a default schema makes
+	// unqualified names resolve against it.  Pinning the pool to a single
+	// connection guarantees each SET applies to the session that runs the
+	// user's query, and that the backend PID captured for cancellation is the
+	// one running it.
+	ms := statementTimeoutMS(req.Connection)
+	schema := searchPathSchema(req.Connection)
+	if ms > 0 || schema != "" || ctx.Done() != nil {
+		db.SetMaxOpenConns(1)
+	}
+	if ms > 0 {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("SET statement_timeout = %d", ms)); err != nil {
+			return &plugin.ExecResponse{Error: fmt.Sprintf("set statement_timeout: %v", err), ErrorCode: classifyPQError(err)}, nil
+		}
+	}
+	if schema != "" {
+		if _, err := db.ExecContext(ctx, setSearchPathStatement(schema)); err != nil {
+			return &plugin.ExecResponse{Error: fmt.Sprintf("set search_path: %v", err), ErrorCode: classifyPQError(err)}, nil
+		}
+	}
+
+	// a Stop from the host cancels the statement with pg_cancel_backend
+	// rather than leaving it running after the process goes away
+	defer watchCancel(ctx, db, dsn)()
+
+	start := time.Now()
+
+	// DML without RETURNING runs with Exec so the affected-row count can be
+	// reported (lib/pq has no last insert id; use RETURNING for that).
+	if plugin.IsDMLStatement(req.Query) {
+		res, err := db.ExecContext(ctx, req.Query)
+		if err != nil {
+			return &plugin.ExecResponse{Error: fmt.Sprintf("exec error: %v", err), ErrorCode: classifyPQError(err)}, nil
+		}
+		result := plugin.AffectedRowsResult(res)
+		result.Warnings = pgNotices.drain()
+		plugin.SetDuration(result, start)
+		return &plugin.ExecResponse{Result: result}, nil
+	}
+
 	rows, err := db.Query(req.Query)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("query error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: fmt.Sprintf("query error: %v", err), ErrorCode: classifyPQError(err)}, nil
 	}
 	defer rows.Close()
 
-	cols, err := rows.Columns()
+	// database type names drive array/JSON formatting
+	result, err := plugin.SQLRowsToResultFunc(rows, 0, formatPGValue)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("cols error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: err.Error(), ErrorCode: classifyPQError(err)}, nil
 	}
+	result.Warnings = pgNotices.drain()
+	plugin.SetDuration(result, start)
+	return &plugin.ExecResponse{Result: result}, nil
+}
 
-	colMeta := make([]*plugin.Column, len(cols))
-	for i, c := range cols {
-		colMeta[i] = &plugin.Column{Name: c}
+// classifyPQError maps a lib/pq error to a plugin.ErrorCode using its
+// SQLSTATE code, falling back to plugin.ClassifyError for errors that did not
+// come from the server (dial failures, timeouts, ...).
+func classifyPQError(err error) plugin.ErrorCode {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return plugin.ClassifyError(err)
+	}
+	switch pqErr.Code {
+	case "42601": // syntax_error
+		return plugin.ErrorCodeSyntaxError
+	case "42P01", "42703", "42883", "3D000", "3F000": // undefined table/column/function, invalid catalog/schema name
+		return plugin.ErrorCodeNotFound
+	case "57014": // query_canceled (statement_timeout)
+		return plugin.ErrorCodeTimeout
+	}
+	switch pqErr.Code.Class() {
+	case "28": // invalid_authorization_specification
+		return plugin.ErrorCodeAuthFailed
+	case "08": // connection_exception
+		return plugin.ErrorCodeConnectionFailed
+	case "0A": // feature_not_supported
+		return plugin.ErrorCodeUnsupported
 	}
+	return plugin.ErrorCodeUnknown
+}
 
-	var rowResults []*plugin.Row
-	for rows.Next() {
-		vals := make([]interface{}, len(cols))
-		ptrs := make([]interface{}, len(cols))
-		for i := range vals {
-			ptrs[i] = &vals[i]
-		}
-		if err := rows.Scan(ptrs...); err != nil {
-			return &plugin.ExecResponse{Error: fmt.Sprintf("scan error: %v", err)}, nil
+// formatPGValue renders a scanned value for display using the column's
+// database type name as reported by lib/pq.  Array columns (type names with a
+// leading underscore, e.g. "_TEXT") arrive as raw `{a,b,c}` literals and are
+// shown as `[a, b, c]`; JSON/JSONB documents are validated and pretty-printed.
+// Anything else, including values that fail to parse, goes through
+// plugin.FormatSQLValue unchanged.
+func formatPGValue(typeName string, v interface{}) string {
+	raw := plugin.FormatSQLValue(v)
+	if v == nil || typeName == "" {
+		return raw
+	}
+	switch {
+	case strings.HasPrefix(typeName, "_"):
+		if formatted, ok := formatPGArray(raw); ok {
+			return formatted
 		}
-		strs := make([]string, len(cols))
-		for i, v := range vals {
-			strs[i] = plugin.FormatSQLValue(v)
+	case typeName == "JSON" || typeName == "JSONB":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(raw), "", "  "); err == nil {
+			return buf.String()
 		}
-		rowResults = append(rowResults, &plugin.Row{Values: strs})
 	}
+	return raw
+}
 
-	return &plugin.ExecResponse{
-		Result: &plugin.ExecResult{
-			Payload: &pluginpb.PluginV1_ExecResult_Sql{
-				Sql: &plugin.SqlResult{
-					Columns: colMeta,
-					Rows: rowResults,
-				},
-			},
-		},
-	}, nil
+// formatPGArray converts a Postgres array literal such as `{1,2,3}`,
+// `{"a b",NULL}` or `{{1,2},{3,4}}` into a bracketed list.  Quoted elements
+// are unquoted and unescaped.  It reports false if the literal is malformed.
+func formatPGArray(lit string) (string, bool) {
+	// arrays with explicit bounds (e.g. `[0:1]={1,2}`) keep their raw form
+	if !strings.HasPrefix(lit, "{") {
+		return "", false
+	}
+	var out strings.Builder
+	depth := 0
+	i := 0
+	for i < len(lit) {
+		c := lit[i]
+		switch c {
+		case '{':
+			depth++
+			out.WriteByte('[')
+			i++
+		case '}':
+			depth--
+			if depth < 0 {
+				return "", false
+			}
+			out.WriteByte(']')
+			i++
+		case ',':
+			out.WriteString(", ")
+			i++
+		case '"':
+			i++
+			for i < len(lit) && lit[i] != '"' {
+				if lit[i] == '\\' && i+1 < len(lit) {
+					i++
+				}
+				out.WriteByte(lit[i])
+				i++
+			}
+			if i >= len(lit) {
+				return "", false
+			}
+			i++ // closing quote
+		default:
+			start := i
+			for i < len(lit) && lit[i] != ',' && lit[i] != '}' {
+				i++
+			}
+			out.WriteString(lit[start:i])
+		}
+	}
+	if depth != 0 {
+		return "", false
+	}
+	return out.String(), true
 }
 
 // ConnectionTree returns a server → database → schema → table hierarchy.
@@ -549,6 +838,8 @@ func (m *postgresqlPlugin) ConnectionTree(ctx context.Context, req *plugin.Conne
 
 	// optional filter coming from the connection info
 	filterDB := getDatabaseFromConn(req.Connection)
+	// the configured default schema is shown expanded, inside its database
+	defaultSchema := searchPathSchema(req.Connection)
 
 	// retrieve list of databases on the server
 	dbNames := []string{}
@@ -633,9 +924,16 @@ ORDER BY c.relname`, schemaName); err == nil {
 									NewTab: true,
 								},
 								{
-									Type:  plugin.ConnectionTreeActionDropTable,
-									Title: "Drop table",
-									Query: fmt.Sprintf(`DROP TABLE "%s"."%s";`, schemaName, tbl),
+									Type:   plugin.ConnectionTreeActionDescribe,
+									Title:  "Show table size",
+									Query:  tableSizeQuery(schemaName, tbl),
+									NewTab: true,
+								},
+								{
+									Type:        plugin.ConnectionTreeActionDropTable,
+									Title:       "Drop table",
+									Query:       fmt.Sprintf(`DROP TABLE "%s"."%s";`, schemaName, tbl),
+									Destructive: true,
 								},
 							},
 						})
@@ -857,6 +1155,7 @@ ORDER BY c.relname`, schemaName); err == nil {
 				Label:    schemaName,
 				NodeType: plugin.ConnectionTreeNodeTypeSchema,
 				Children: categories,
+				Expanded: schemaName == defaultSchema,
 			}
 			schemaNodes = append(schemaNodes, schemaNode)
 		}
@@ -866,9 +1165,12 @@ ORDER BY c.relname`, schemaName); err == nil {
 	var dbNodes []*plugin.ConnectionTreeNode
 	for _, dbname := range dbNames {
 		var schemas []*plugin.ConnectionTreeNode
-		if dbname == currentDB {
+		switch {
+		case req.GetMaxDepth() == 1:
+			// databases only; skip connecting to each one for its schemas
+		case dbname == currentDB:
 			schemas = loadSchemas(db)
-		} else {
+		default:
 			connMap := make(map[string]string)
 			for k, v := range req.Connection {
 				connMap[k] = v
@@ -886,11 +1188,13 @@ ORDER BY c.relname`, schemaName); err == nil {
 			Label:    dbname,
 			NodeType: plugin.ConnectionTreeNodeTypeDatabase,
 			Children: schemas,
+			Expanded: defaultSchema != "" && dbname == currentDB,
 			Actions: []*plugin.ConnectionTreeAction{
 				{
-					Type:  plugin.ConnectionTreeActionDropDatabase,
-					Title: "Drop database",
-					Query: fmt.Sprintf(`DROP DATABASE "%s";`, dbname),
+					Type:        plugin.ConnectionTreeActionDropDatabase,
+					Title:       "Drop database",
+					Query:       fmt.Sprintf(`DROP DATABASE "%s";`, dbname),
+					Destructive: true,
 				},
 			},
 		}
@@ -951,8 +1255,12 @@ func (m *postgresqlPlugin) GetCompletionFields(ctx context.Context, req *plugin.
 		tableName = parts[1]
 	}
 
-	// When no schema is known, ask the live connection for its current search-path
-	// schema so we don't hard-code "public" (schemas like dbo, myapp, etc. exist).
+	// When no schema is known, use the connection's default schema or ask the
+	// live connection for its current search-path schema so we don't hard-code
+	// "public" (schemas like dbo, myapp, etc. exist).
+	if schemaName == "" {
+		schemaName = searchPathSchema(req.Connection)
+	}
 	if schemaName == "" {
 		_ = db.QueryRowContext(ctx, "SELECT current_schema()").Scan(&schemaName)
 	}
@@ -982,7 +1290,7 @@ func (m *postgresqlPlugin) GetCompletionFields(ctx context.Context, req *plugin.
 }
 
 func (m *postgresqlPlugin) TestConnection(ctx context.Context, req *plugin.TestConnectionRequest) (*plugin.TestConnectionResponse, error) {
-	dsn, err := buildConnString(req.Connection)
+	dsn, err := buildConnString(plugin.WithCredentialForm(req.Connection, req.GetForm()))
 	if err != nil || dsn == "" {
 		msg := "invalid connection parameters"
 		if err != nil {
@@ -995,10 +1303,171 @@ func (m *postgresqlPlugin) TestConnection(ctx context.Context, req *plugin.TestC
 		return &plugin.TestConnectionResponse{Ok: false, Message: fmt.Sprintf("open error: %v", err)}, nil
 	}
 	defer db.Close()
-	if err := db.Ping(); err != nil {
+	latency, err := plugin.PingLatency(ctx, db)
+	if err != nil {
 		return &plugin.TestConnectionResponse{Ok: false, Message: formatPingError(err)}, nil
 	}
-	return &plugin.TestConnectionResponse{Ok: true, Message: "Connection successful"}, nil
+	return &plugin.TestConnectionResponse{
+		Ok:         true,
+		Message:    "Connection successful",
+		LatencyMs:  &latency,
+		ServerInfo: plugin.SQLServerInfo(ctx, db, "SELECT current_setting('server_version'), version()", "version", "description"),
+	}, nil
+}
+
+// Validate asks the server to parse and plan the query without running it.
+// lib/pq prepares statements with the extended-protocol Parse message, which
+// surfaces syntax and unknown-relation errors for any statement kind
+// (including DDL) while leaving the database untouched.
+func (m *postgresqlPlugin) Validate(ctx context.Context, req *plugin.ValidateRequest) (*plugin.ValidateResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return &plugin.ValidateResponse{Valid: false, Message: "query is empty"}, nil
+	}
+	dsn, err := buildConnString(req.Connection)
+	if err != nil || dsn == "" {
+		msg := "invalid connection parameters"
+		if err != nil {
+			msg = err.Error()
+		}
+		return &plugin.ValidateResponse{Valid: false, Message: msg}, nil
+	}
+	db, err := openPostgresDB(dsn)
+	if err != nil {
+		return &plugin.ValidateResponse{Valid: false, Message: fmt.Sprintf("open error: %v", err)}, nil
+	}
+	defer db.Close()
+
+	// resolve unqualified names the way Exec would; the single connection
+	// keeps the SET and the prepare on one session
+	if schema := searchPathSchema(req.Connection); schema != "" {
+		db.SetMaxOpenConns(1)
+		if _, err := db.ExecContext(ctx, setSearchPathStatement(schema)); err != nil {
+			return &plugin.ValidateResponse{Valid: false, Message: fmt.Sprintf("set search_path: %v", err)}, nil
+		}
+	}
+
+	stmt, err := db.PrepareContext(ctx, req.Query)
+	if err != nil {
+		return &plugin.ValidateResponse{Valid: false, Message: err.Error()}, nil
+	}
+	_ = stmt.Close()
+	return &plugin.ValidateResponse{Valid: true}, nil
+}
+
+// pgColumnDef is one column of a table as read from pg_attribute.
+type pgColumnDef struct {
+	name     string
+	typ      string // format_type() output, e.g. "character varying(64)"
+	notNull  bool
+	defValue string // pg_get_expr() of the default, empty when none
+}
+
+// GenerateDDL reconstructs `CREATE TABLE` for the table behind a connection
+// tree node key ("schema.table") from the system catalogs, followed by the
+// table's indexes that are not backing a constraint.  PostgreSQL has no
+// SHOW CREATE, so the result approximates pg_dump's table section.
+func (m *postgresqlPlugin) GenerateDDL(ctx context.Context, req *plugin.GenerateDDLRequest) (*plugin.GenerateDDLResponse, error) {
+	schema, table, ok := strings.Cut(req.NodeKey, ".")
+	if !ok || schema == "" || table == "" {
+		return &plugin.GenerateDDLResponse{Error: fmt.Sprintf("cannot generate DDL for node %q: expected schema.table", req.NodeKey)}, nil
+	}
+	dsn, err := buildConnString(req.Connection)
+	if err != nil || dsn == "" {
+		return &plugin.GenerateDDLResponse{Error: "invalid connection"}, nil
+	}
+	db, err := openPostgresDB(dsn)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: fmt.Sprintf("open error: %v", err)}, nil
+	}
+	defer db.Close()
+
+	qualified := quoteSourcePG(req.NodeKey)
+
+	rows, err := db.QueryContext(ctx, `
+SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+       COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+FROM pg_catalog.pg_attribute a
+LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`, qualified)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: err.Error()}, nil
+	}
+	var cols []pgColumnDef
+	for rows.Next() {
+		var c pgColumnDef
+		if err := rows.Scan(&c.name, &c.typ, &c.notNull, &c.defValue); err != nil {
+			rows.Close()
+			return &plugin.GenerateDDLResponse{Error: err.Error()}, nil
+		}
+		cols = append(cols, c)
+	}
+	rows.Close()
+
+	constraints, err := queryStrings(ctx, db, `
+SELECT 'CONSTRAINT ' || quote_ident(conname) || ' ' || pg_get_constraintdef(oid)
+FROM pg_catalog.pg_constraint
+WHERE conrelid = $1::regclass
+ORDER BY contype = 'p' DESC, conname`, qualified)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: err.Error()}, nil
+	}
+	indexes, err := queryStrings(ctx, db, `
+SELECT pg_get_indexdef(i.indexrelid)
+FROM pg_catalog.pg_index i
+WHERE i.indrelid = $1::regclass
+  AND NOT EXISTS (SELECT 1 FROM pg_catalog.pg_constraint c WHERE c.conindid = i.indexrelid)
+ORDER BY i.indexrelid`, qualified)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: err.Error()}, nil
+	}
+
+	return &plugin.GenerateDDLResponse{Ddl: buildCreateTablePG(qualified, cols, constraints, indexes)}, nil
+}
+
+// queryStrings runs a single-column query and collects the results.
+func queryStrings(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// buildCreateTablePG renders a CREATE TABLE statement for qualified (already
+// quoted) from its columns and table constraints, followed by one statement
+// per standalone index.
+func buildCreateTablePG(qualified string, cols []pgColumnDef, constraints, indexes []string) string {
+	var defs []string
+	for _, c := range cols {
+		def := fmt.Sprintf(`    "%s" %s`, escapeDoubleQuote(c.name), c.typ)
+		if c.defValue != "" {
+			def += " DEFAULT " + c.defValue
+		}
+		if c.notNull {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+	}
+	for _, con := range constraints {
+		defs = append(defs, "    "+con)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n%s\n);", qualified, strings.Join(defs, ",\n"))
+	for _, idx := range indexes {
+		b.WriteString("\n" + idx + ";")
+	}
+	return b.String()
 }
 
 // escapeDoubleQuote doubles any double-quote characters in s so it can be
@@ -1007,6 +1476,42 @@ func escapeDoubleQuote(s string) string {
 	return strings.ReplaceAll(s, `"`, `""`)
 }
 
+// escapeSingleQuote doubles any single-quote characters in s so it can be
+// safely embedded in a standard SQL string literal.
+func escapeSingleQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// copyToStdoutRe matches `COPY (query) TO STDOUT ...` and
+// `COPY table [(columns)] TO STDOUT ...`, whatever WITH options follow.
+var copyToStdoutRe = regexp.MustCompile(`(?is)^\s*copy\s+(?:\(.+\)|[^\s(]+\s*(?:\([^)]*\))?)\s+to\s+stdout\b`)
+
+// isCopyToStdout reports whether query is a `COPY ... TO STDOUT` export.
+// lib/pq rejects COPY TO ("COPY TO is not supported"), so Exec refuses such
+// statements up front with a clear error instead.  COPY ... FROM and COPY
+// ... TO a server-side file are not matched.
+func isCopyToStdout(query string) bool {
+	return copyToStdoutRe.MatchString(query)
+}
+
+// tableSizeQuery returns a statement reporting the on-disk size and row
+// estimates of schema.table as key/value rows, cheap enough to run before a
+// full scan.  The names travel as string literals quoted by format('%I.%I')
+// so mixed-case and otherwise unusual identifiers resolve correctly.  The
+// planner estimate (reltuples) is -1 for a table that was never analysed; the
+// live-row count comes from the statistics collector.
+func tableSizeQuery(schema, table string) string {
+	return fmt.Sprintf(`WITH t AS (SELECT format('%%I.%%I', '%s', '%s')::regclass AS oid)
+SELECT 'Table size' AS key, pg_size_pretty(pg_relation_size(oid)) AS value FROM t
+UNION ALL
+SELECT 'Total size (with indexes and TOAST)', pg_size_pretty(pg_total_relation_size(oid)) FROM t
+UNION ALL
+SELECT 'Estimated rows', reltuples::bigint::text FROM pg_catalog.pg_class WHERE oid = (SELECT oid FROM t)
+UNION ALL
+SELECT 'Live rows (statistics)', n_live_tup::text FROM pg_catalog.pg_stat_user_tables WHERE relid = (SELECT oid FROM t);`,
+		escapeSingleQuote(schema), escapeSingleQuote(table))
+}
+
 // quoteSourcePG wraps a table reference in double-quotes, handling the
 // optional "schema.table" form produced by DescribeSchema (e.g.
 // "public.users" becomes "public"."users").
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"strings"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/felixdotgo/querybox/pkg/certs"
 	"github.com/felixdotgo/querybox/pkg/plugin"
+	"github.com/lib/pq"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
 )
 
@@ -47,6 +49,30 @@ func TestBuildConnStringDefaultDisable(t *testing.T) {
     }
 }
 
+// A host that is a directory path selects a Unix socket: the DSN keeps the
+// directory as host (lib/pq's socket convention), keeps the port that names
+// the socket file and forces TLS off.
+func TestBuildConnStringUnixSocket(t *testing.T) {
+    conn := map[string]string{"credential_blob": makeBlob(map[string]string{
+        "host": "/var/run/postgresql", "port": "5433", "user": "me", "database": "db1", "tls": "require",
+    })}
+    dsn, err := buildConnString(conn)
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    for _, want := range []string{"host=/var/run/postgresql", "port=5433", "dbname=db1", "sslmode=disable"} {
+        if !strings.Contains(dsn, want) {
+            t.Errorf("expected %q in conn string, got %q", want, dsn)
+        }
+    }
+    if strings.Contains(dsn, "sslmode=require") {
+        t.Errorf("TLS should be disabled for socket connections, got %q", dsn)
+    }
+    if _, err := pq.NewConnector(dsn); err != nil {
+        t.Errorf("lib/pq rejected socket DSN %q: %v", dsn, err)
+    }
+}
+
 // verify that leaving the database name blank doesn't cause the sslmode
 // token to be parsed as the database name (user-reported bug).
 func TestBuildConnStringEmptyDatabase(t *testing.T) {
@@ -76,6 +102,74 @@ func TestBuildConnStringBlobDSN(t *testing.T) {
     }
 }
 
+// buildConnString reads the connection through plugin.ParseConnection, so a
+// flat map yields the same DSN as the equivalent credential blob and a flat
+// key overrides the blob value of the same name.
+func TestBuildConnStringFlatMatchesBlob(t *testing.T) {
+    fields := map[string]string{"host": "db", "user": "me", "database": "app", "tls": "require"}
+    flat, err := buildConnString(fields)
+    if err != nil {
+        t.Fatalf("flat: unexpected error: %v", err)
+    }
+    blob, err := buildConnString(map[string]string{"credential_blob": makeBlob(fields)})
+    if err != nil {
+        t.Fatalf("blob: unexpected error: %v", err)
+    }
+    if flat != blob {
+        t.Errorf("flat map gave %q, blob gave %q", flat, blob)
+    }
+
+    over, err := buildConnString(map[string]string{"credential_blob": makeBlob(fields), "tls": "disable"})
+    if err != nil {
+        t.Fatalf("override: unexpected error: %v", err)
+    }
+    if !strings.Contains(over, "sslmode=disable") {
+        t.Errorf("expected the flat tls value to win, got %q", over)
+    }
+
+    if _, err := buildConnString(map[string]string{"credential_blob": "{not json"}); err == nil {
+        t.Error("expected an error for a malformed credential blob")
+    }
+}
+
+// Extra parameters for a URL DSN go into its query string; appending them
+// as keyword pairs would produce a DSN lib/pq cannot parse.
+func TestBuildConnStringURLDSNParams(t *testing.T) {
+    conn := map[string]string{"credential_blob": makeBlob(map[string]string{
+        "dsn": "postgres://user@localhost/db", "application_name": "qb",
+    })}
+    dsn, err := buildConnString(conn)
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if strings.Contains(dsn, " ") {
+        t.Errorf("expected params in the URL query, got %q", dsn)
+    }
+    for _, want := range []string{"application_name=qb", "connect_timeout=5", "sslmode=disable"} {
+        if !strings.Contains(dsn, want) {
+            t.Errorf("expected %q in conn string, got %q", want, dsn)
+        }
+    }
+    if _, err := pq.NewConnector(dsn); err != nil {
+        t.Errorf("lib/pq rejected URL DSN %q: %v", dsn, err)
+    }
+}
+
+func TestBuildConnStringUnescapesParams(t *testing.T) {
+    // as written by a connection URL import: & and = escaped inside a value
+    params := "application_name=q%2Bb&options=-c%20search_path%3Da%26b"
+    conn := map[string]string{"credential_blob": makeBlob(map[string]string{"host": "localhost", "params": params})}
+    dsn, err := buildConnString(conn)
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    for _, want := range []string{"application_name=q+b", "options='-c search_path=a&b'"} {
+        if !strings.Contains(dsn, want) {
+            t.Errorf("expected %q in conn string, got %q", want, dsn)
+        }
+    }
+}
+
 // Helpers for constructing blobs used across multiple tests.
 var makeBlob = plugin.MakeTestBlob
 
@@ -605,6 +699,61 @@ func TestConnectionTreeSchemaGroups(t *testing.T) {
         t.Errorf("Tables group should have 2 tables, got %d", len(tablesGroup.Children))
     }
 
+    // drop actions must be flagged so the host asks for confirmation
+    for _, n := range append([]*plugin.ConnectionTreeNode{dbNode}, tablesGroup.Children...) {
+        for _, a := range n.Actions {
+            isDrop := a.Type == plugin.ConnectionTreeActionDropTable || a.Type == plugin.ConnectionTreeActionDropDatabase
+            if a.Destructive != isDrop {
+                t.Errorf("%s action on %q: Destructive = %v, want %v", a.Type, n.Key, a.Destructive, isDrop)
+            }
+        }
+    }
+
+    // without a default schema nothing is pre-expanded
+    if dbNode.Expanded || schemaNode.Expanded {
+        t.Errorf("no default schema configured, but Expanded = %v/%v", dbNode.Expanded, schemaNode.Expanded)
+    }
+
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+func TestConnectionTreeExpandsDefaultSchema(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+
+    mock.ExpectQuery("SELECT current_database\\(\\)").WillReturnRows(sqlmock.NewRows([]string{"current_database"}).AddRow("mydb"))
+    mock.ExpectQuery("SELECT datname FROM pg_database").WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("mydb"))
+    mock.ExpectQuery("SELECT schema_name").WillReturnRows(sqlmock.NewRows([]string{"schema_name"}).AddRow("app").AddRow("sales"))
+    mock.ExpectQuery("(?s)relkind IN.*pg_inherits").WithArgs("app").WillReturnRows(sqlmock.NewRows([]string{"relname"}))
+    mock.ExpectQuery("(?s)relkind IN.*pg_inherits").WithArgs("sales").WillReturnRows(sqlmock.NewRows([]string{"relname"}))
+
+    p := &postgresqlPlugin{}
+    resp, err := p.ConnectionTree(context.Background(), &pluginpb.PluginV1_ConnectionTreeRequest{
+        Connection: map[string]string{"dsn": "postgres://foo", "schema": "sales"},
+    })
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if len(resp.Nodes) != 2 || len(resp.Nodes[1].Children) != 2 {
+        t.Fatalf("unexpected tree: %v", resp.Nodes)
+    }
+    dbNode := resp.Nodes[1]
+    if !dbNode.Expanded {
+        t.Error("database holding the default schema should be expanded")
+    }
+    for _, schema := range dbNode.Children {
+        if want := schema.Label == "sales"; schema.Expanded != want {
+            t.Errorf("schema %q: Expanded = %v, want %v", schema.Label, schema.Expanded, want)
+        }
+    }
     if err := mock.ExpectationsWereMet(); err != nil {
         t.Errorf("unmet expectations: %v", err)
     }
@@ -748,3 +897,552 @@ func TestQuoteSourcePG(t *testing.T) {
         }
     }
 }
+
+func TestTableSizeQuery(t *testing.T) {
+    q := tableSizeQuery("app", "orders")
+    if !strings.Contains(q, `format('%I.%I', 'app', 'orders')::regclass`) {
+        t.Errorf("query should resolve the table through format('%%I.%%I'):\n%s", q)
+    }
+    for _, want := range []string{"pg_relation_size", "pg_total_relation_size", "reltuples", "pg_stat_user_tables"} {
+        if !strings.Contains(q, want) {
+            t.Errorf("query missing %s:\n%s", want, q)
+        }
+    }
+
+    // quotes in names must not terminate the literals
+    q = tableSizeQuery("it's", "o'); DROP TABLE x; --")
+    if !strings.Contains(q, `'it''s', 'o''); DROP TABLE x; --'`) {
+        t.Errorf("single quotes were not escaped:\n%s", q)
+    }
+}
+
+func TestIsCopyToStdout(t *testing.T) {
+    tests := []struct {
+        query string
+        want  bool
+    }{
+        {"COPY (SELECT id, name FROM users WHERE active) TO STDOUT WITH CSV HEADER", true},
+        {"copy (select (a).x from t) to stdout with (format csv, header true);", true},
+        {"COPY public.users TO STDOUT WITH CSV HEADER", true},
+        {"COPY \"Orders\" (id, total) TO STDOUT CSV DELIMITER '|'", true},
+        {"COPY users TO STDOUT", true},
+        {"COPY users FROM STDIN WITH CSV", false},
+        {"COPY users TO '/tmp/users.csv' WITH CSV", false},
+        {"SELECT 'COPY (x) TO STDOUT'", false},
+    }
+    for _, tt := range tests {
+        if got := isCopyToStdout(tt.query); got != tt.want {
+            t.Errorf("isCopyToStdout(%q) = %v, want %v", tt.query, got, tt.want)
+        }
+    }
+}
+
+func TestExecRejectsCopyToStdout(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+    openPostgresDB = func(dsn string) (*sql.DB, error) {
+        t.Fatal("COPY TO STDOUT must be rejected before connecting")
+        return nil, nil
+    }
+
+    m := &postgresqlPlugin{}
+    resp, err := m.Exec(context.Background(), &plugin.ExecRequest{
+        Connection: map[string]string{"dsn": "host=localhost"},
+        Query:      "COPY (SELECT id FROM users) TO STDOUT WITH CSV HEADER",
+        Limit:      1,
+    })
+    if err != nil {
+        t.Fatalf("Exec: %v", err)
+    }
+    if !strings.Contains(resp.GetError(), "COPY ... TO STDOUT is not supported") {
+        t.Errorf("error = %q, want COPY TO STDOUT rejected", resp.GetError())
+    }
+    if resp.GetErrorCode() != plugin.ErrorCodeUnsupported {
+        t.Errorf("error code = %v, want %v", resp.GetErrorCode(), plugin.ErrorCodeUnsupported)
+    }
+}
+
+func TestExecReportsRowsAffected(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    // Exec closes its handle, so every call gets a fresh mock
+    var mock sqlmock.Sqlmock
+    var setup func(sqlmock.Sqlmock)
+    openPostgresDB = func(dsn string) (*sql.DB, error) {
+        db, mk, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+        mock = mk
+        setup(mk)
+        return db, err
+    }
+    conn := map[string]string{"dsn": "host=localhost"}
+    m := &postgresqlPlugin{}
+
+    setup = func(mk sqlmock.Sqlmock) {
+        mk.ExpectExec("UPDATE users SET active = false").WillReturnResult(driver.RowsAffected(3))
+    }
+    resp, err := m.Exec(context.Background(), &plugin.ExecRequest{Connection: conn, Query: "UPDATE users SET active = false"})
+    if err != nil || resp.Error != "" {
+        t.Fatalf("Exec: %v %s", err, resp.GetError())
+    }
+    res := resp.GetResult().GetSql()
+    if res.RowsAffected == nil || res.GetRowsAffected() != 3 {
+        t.Errorf("rows affected = %v, want 3", res.RowsAffected)
+    }
+    if res.LastInsertId != nil {
+        t.Errorf("last insert id should be unset, got %d", res.GetLastInsertId())
+    }
+    if _, ok := resp.GetResult().GetMetadata()[plugin.MetadataDurationMS]; !ok {
+        t.Errorf("metadata = %v, want %s", resp.GetResult().GetMetadata(), plugin.MetadataDurationMS)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+
+    // RETURNING makes DML a query; its rows are returned instead of a count
+    setup = func(mk sqlmock.Sqlmock) {
+        mk.ExpectQuery("DELETE FROM users WHERE id = 1 RETURNING id").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+    }
+    resp, err = m.Exec(context.Background(), &plugin.ExecRequest{Connection: conn, Query: "DELETE FROM users WHERE id = 1 RETURNING id"})
+    if err != nil || resp.Error != "" {
+        t.Fatalf("Exec: %v %s", err, resp.GetError())
+    }
+    if res := resp.GetResult().GetSql(); res.RowsAffected != nil || len(res.GetRows()) != 1 {
+        t.Errorf("RETURNING result = %v", res)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+func TestExecReturnsNotices(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    // a stale notice from an earlier request must not leak into this one
+    pgNotices.add(&pq.Error{Severity: "NOTICE", Message: "stale"})
+
+    db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) {
+        // stands in for the notice handler the real opener installs
+        pgNotices.add(&pq.Error{Severity: "NOTICE", Message: `table "t" does not exist, skipping`})
+        return db, nil
+    }
+    mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+    m := &postgresqlPlugin{}
+    resp, err := m.Exec(context.Background(), &plugin.ExecRequest{Connection: map[string]string{"dsn": "host=localhost"}, Query: "SELECT 1"})
+    if err != nil || resp.Error != "" {
+        t.Fatalf("Exec: %v %s", err, resp.GetError())
+    }
+    want := []string{`NOTICE: table "t" does not exist, skipping`}
+    if got := resp.GetResult().GetWarnings(); len(got) != 1 || got[0] != want[0] {
+        t.Errorf("warnings = %q, want %q", got, want)
+    }
+    if left := pgNotices.drain(); len(left) != 0 {
+        t.Errorf("notices not drained: %q", left)
+    }
+}
+
+// A cancellable request records its backend PID on the pinned session
+// before the user's statement runs.
+func TestExecCapturesBackendPID(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectQuery("SELECT pg_backend_pid()").WillReturnRows(sqlmock.NewRows([]string{"pg_backend_pid"}).AddRow(4242))
+    mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    m := &postgresqlPlugin{}
+    resp, err := m.Exec(ctx, &plugin.ExecRequest{Connection: map[string]string{"dsn": "host=localhost"}, Query: "SELECT 1"})
+    if err != nil || resp.Error != "" {
+        t.Fatalf("Exec: %v %s", err, resp.GetError())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+func TestCancelBackend(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectQuery("SELECT pg_cancel_backend($1)").WithArgs(4242).WillReturnRows(sqlmock.NewRows([]string{"pg_cancel_backend"}).AddRow(true))
+    if err := cancelBackend("host=localhost", 4242); err != nil {
+        t.Fatalf("cancelBackend: %v", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+
+    db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    mock.ExpectQuery("SELECT pg_cancel_backend($1)").WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"pg_cancel_backend"}).AddRow(false))
+    if err := cancelBackend("host=localhost", 7); err == nil {
+        t.Error("expected an error when the backend was not signalled")
+    }
+}
+
+// Cancelling the request context sends pg_cancel_backend for the captured
+// PID over a second connection.
+func TestWatchCancelCancelsBackend(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    mock.ExpectQuery("SELECT pg_backend_pid()").WillReturnRows(sqlmock.NewRows([]string{"pg_backend_pid"}).AddRow(4242))
+
+    side, sideMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    sideMock.ExpectQuery("SELECT pg_cancel_backend($1)").WithArgs(4242).WillReturnRows(sqlmock.NewRows([]string{"pg_cancel_backend"}).AddRow(true))
+    opened := make(chan string, 1)
+    openPostgresDB = func(dsn string) (*sql.DB, error) {
+        opened <- dsn
+        return side, nil
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    stop := watchCancel(ctx, db, "host=localhost")
+    cancel()
+    if dsn := <-opened; dsn != "host=localhost" {
+        t.Errorf("cancel connection dsn = %q", dsn)
+    }
+    stop()
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+    if err := sideMock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet cancel expectations: %v", err)
+    }
+
+    // an uncancellable context issues no queries at all
+    watchCancel(context.Background(), db, "host=localhost")()
+}
+
+func TestValidatePreparesWithoutExecuting(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    cases := []struct {
+        query     string
+        prepErr   error
+        wantValid bool
+    }{
+        {"SELECT * FROM users", nil, true},
+        {"SELEC 1", fmt.Errorf(`syntax error at or near "SELEC"`), false},
+    }
+    for _, c := range cases {
+        db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+        if err != nil {
+            t.Fatalf("sqlmock: %v", err)
+        }
+        openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+        if c.prepErr != nil {
+            mock.ExpectPrepare(c.query).WillReturnError(c.prepErr)
+        } else {
+            mock.ExpectPrepare(c.query).WillBeClosed()
+        }
+
+        p := &postgresqlPlugin{}
+        resp, err := p.Validate(context.Background(), &plugin.ValidateRequest{
+            Connection: map[string]string{"dsn": "host=localhost sslmode=disable"},
+            Query:      c.query,
+        })
+        if err != nil {
+            t.Fatalf("Validate(%q) error: %v", c.query, err)
+        }
+        if resp.Valid != c.wantValid {
+            t.Errorf("Validate(%q) valid = %v, want %v (message: %s)", c.query, resp.Valid, c.wantValid, resp.Message)
+        }
+        if !c.wantValid && !strings.Contains(resp.Message, "syntax error") {
+            t.Errorf("Validate(%q) message = %q, want syntax error", c.query, resp.Message)
+        }
+        if err := mock.ExpectationsWereMet(); err != nil {
+            t.Errorf("unmet expectations for %q: %v", c.query, err)
+        }
+    }
+}
+
+func TestFormatPGValue(t *testing.T) {
+    tests := []struct {
+        name     string
+        typeName string
+        input    interface{}
+        want     string
+    }{
+        {"text array", "_TEXT", []byte(`{a,b,c}`), "[a, b, c]"},
+        {"quoted elements", "_TEXT", []byte(`{"hello world","say \"hi\"",NULL}`), `[hello world, say "hi", NULL]`},
+        {"nested int array", "_INT4", []byte(`{{1,2},{3,4}}`), "[[1, 2], [3, 4]]"},
+        {"empty array", "_TEXT", []byte(`{}`), "[]"},
+        {"malformed array kept raw", "_TEXT", []byte(`{"unterminated`), `{"unterminated`},
+        {"jsonb pretty printed", "JSONB", []byte(`{"a":1,"b":[true,null]}`), "{\n  \"a\": 1,\n  \"b\": [\n    true,\n    null\n  ]\n}"},
+        {"invalid json kept raw", "JSON", []byte(`{not json`), "{not json"},
+        {"plain text untouched", "TEXT", []byte(`{a,b}`), "{a,b}"},
+        {"nil", "_TEXT", nil, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := formatPGValue(tt.typeName, tt.input); got != tt.want {
+                t.Errorf("formatPGValue(%q, %q) = %q, want %q", tt.typeName, tt.input, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestClassifyPQError(t *testing.T) {
+    tests := []struct {
+        name string
+        err  error
+        want plugin.ErrorCode
+    }{
+        {"syntax", &pq.Error{Code: "42601"}, plugin.ErrorCodeSyntaxError},
+        {"undefined table", &pq.Error{Code: "42P01"}, plugin.ErrorCodeNotFound},
+        {"unknown database", &pq.Error{Code: "3D000"}, plugin.ErrorCodeNotFound},
+        {"bad password", &pq.Error{Code: "28P01"}, plugin.ErrorCodeAuthFailed},
+        {"connection exception", &pq.Error{Code: "08006"}, plugin.ErrorCodeConnectionFailed},
+        {"statement timeout", &pq.Error{Code: "57014"}, plugin.ErrorCodeTimeout},
+        {"feature not supported", &pq.Error{Code: "0A000"}, plugin.ErrorCodeUnsupported},
+        {"unique violation", &pq.Error{Code: "23505"}, plugin.ErrorCodeUnknown},
+        {"wrapped", fmt.Errorf("query: %w", &pq.Error{Code: "42601"}), plugin.ErrorCodeSyntaxError},
+        {"client side", fmt.Errorf("dial tcp: connection refused"), plugin.ErrorCodeConnectionFailed},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := classifyPQError(tt.err); got != tt.want {
+                t.Errorf("classifyPQError(%v) = %v, want %v", tt.err, got, tt.want)
+            }
+        })
+    }
+}
+
+// Session settings (statement_timeout, default schema) are issued as SETs on
+// the same pooled connection before the user's query, never via the DSN.  The
+// schema is always a quoted identifier so odd names cannot break out of the
+// SET statement.
+func TestExecAppliesSessionSettings(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    blob := func(v map[string]string) map[string]string {
+        v["host"] = "localhost"
+        return map[string]string{"credential_blob": plugin.MakeTestBlob(v)}
+    }
+    tests := []struct {
+        name     string
+        conn     map[string]string
+        wantSets []string
+    }{
+        {"timeout blob field", blob(map[string]string{"statement_timeout": "5000"}), []string{"SET statement_timeout = 5000"}},
+        {"timeout connection key", map[string]string{"dsn": "host=localhost", "statement_timeout": "250"}, []string{"SET statement_timeout = 250"}},
+        {"invalid timeout", map[string]string{"dsn": "host=localhost", "statement_timeout": "soon"}, nil},
+        {"schema blob field", blob(map[string]string{"schema": "sales"}), []string{`SET search_path TO "sales"`}},
+        {"schema sanitized", map[string]string{"dsn": "host=localhost", "schema": `x"; DROP TABLE t; --`}, []string{`SET search_path TO "x""; DROP TABLE t; --"`}},
+        {"blank schema", map[string]string{"dsn": "host=localhost", "schema": "  "}, nil},
+        {"both", blob(map[string]string{"statement_timeout": "100", "schema": "sales"}), []string{"SET statement_timeout = 100", `SET search_path TO "sales"`}},
+        {"absent", map[string]string{"dsn": "host=localhost"}, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+            if err != nil {
+                t.Fatalf("failed to create mock: %v", err)
+            }
+            openPostgresDB = func(dsn string) (*sql.DB, error) {
+                if strings.Contains(dsn, "statement_timeout") || strings.Contains(dsn, "schema") {
+                    t.Errorf("session setting leaked into dsn: %q", dsn)
+                }
+                return db, nil
+            }
+            for _, set := range tt.wantSets {
+                mock.ExpectExec(set).WillReturnResult(sqlmock.NewResult(0, 0))
+            }
+            mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+            m := &postgresqlPlugin{}
+            resp, err := m.Exec(context.Background(), &plugin.ExecRequest{Connection: tt.conn, Query: "SELECT 1"})
+            if err != nil {
+                t.Fatalf("Exec error: %v", err)
+            }
+            if resp.Error != "" {
+                t.Fatalf("Exec returned error: %s", resp.Error)
+            }
+            if err := mock.ExpectationsWereMet(); err != nil {
+                t.Errorf("unmet expectations: %v", err)
+            }
+        })
+    }
+}
+
+// Validate must resolve unqualified names against the default schema, like
+// Exec does.
+func TestValidateAppliesSearchPath(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+    if err != nil {
+        t.Fatalf("sqlmock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectExec(`SET search_path TO "sales"`).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectPrepare("SELECT * FROM orders").WillBeClosed()
+
+    p := &postgresqlPlugin{}
+    resp, err := p.Validate(context.Background(), &plugin.ValidateRequest{
+        Connection: map[string]string{"dsn": "host=localhost", "schema": "sales"},
+        Query:      "SELECT * FROM orders",
+    })
+    if err != nil {
+        t.Fatalf("Validate error: %v", err)
+    }
+    if !resp.GetValid() {
+        t.Errorf("expected valid, got %q", resp.GetMessage())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+// Completion for an unqualified table looks in the default schema instead
+// of asking the server for current_schema().
+func TestCompletionFieldsUsesDefaultSchema(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatalf("sqlmock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectQuery("FROM information_schema.columns").WithArgs("sales", "orders").
+        WillReturnRows(sqlmock.NewRows([]string{"column_name", "data_type"}).AddRow("id", "integer"))
+
+    p := &postgresqlPlugin{}
+    resp, err := p.GetCompletionFields(context.Background(), &plugin.GetCompletionFieldsRequest{
+        Connection: map[string]string{"dsn": "host=localhost", "schema": "sales"},
+        Collection: "orders",
+    })
+    if err != nil {
+        t.Fatalf("GetCompletionFields error: %v", err)
+    }
+    if len(resp.GetFields()) != 1 || resp.GetFields()[0].GetName() != "id" {
+        t.Errorf("unexpected fields: %v", resp.GetFields())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+func TestGenerateDDLBuildsCreateTable(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatalf("sqlmock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+
+    mock.ExpectQuery("FROM pg_catalog.pg_attribute").WithArgs(`"public"."users"`).WillReturnRows(
+        sqlmock.NewRows([]string{"attname", "type", "notnull", "default"}).
+            AddRow("id", "integer", true, "nextval('users_id_seq'::regclass)").
+            AddRow("email", "character varying(255)", true, "").
+            AddRow("bio", "text", false, ""))
+    mock.ExpectQuery("FROM pg_catalog.pg_constraint").WithArgs(`"public"."users"`).WillReturnRows(
+        sqlmock.NewRows([]string{"def"}).
+            AddRow("CONSTRAINT users_pkey PRIMARY KEY (id)").
+            AddRow("CONSTRAINT users_email_key UNIQUE (email)"))
+    mock.ExpectQuery("FROM pg_catalog.pg_index").WithArgs(`"public"."users"`).WillReturnRows(
+        sqlmock.NewRows([]string{"def"}).
+            AddRow("CREATE INDEX users_bio_idx ON public.users USING btree (bio)"))
+
+    p := &postgresqlPlugin{}
+    resp, err := p.GenerateDDL(context.Background(), &plugin.GenerateDDLRequest{
+        Connection: map[string]string{"dsn": "host=localhost sslmode=disable"},
+        NodeKey:    "public.users",
+    })
+    if err != nil {
+        t.Fatalf("GenerateDDL error: %v", err)
+    }
+    if resp.Error != "" {
+        t.Fatalf("GenerateDDL reported error: %s", resp.Error)
+    }
+    want := `CREATE TABLE "public"."users" (
+    "id" integer DEFAULT nextval('users_id_seq'::regclass) NOT NULL,
+    "email" character varying(255) NOT NULL,
+    "bio" text,
+    CONSTRAINT users_pkey PRIMARY KEY (id),
+    CONSTRAINT users_email_key UNIQUE (email)
+);
+CREATE INDEX users_bio_idx ON public.users USING btree (bio);`
+    if resp.Ddl != want {
+        t.Errorf("DDL mismatch\ngot:\n%s\nwant:\n%s", resp.Ddl, want)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
+
+func TestGenerateDDLRejectsNonTableKey(t *testing.T) {
+    p := &postgresqlPlugin{}
+    resp, err := p.GenerateDDL(context.Background(), &plugin.GenerateDDLRequest{NodeKey: "mydb"})
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if resp.Error == "" || resp.Ddl != "" {
+        t.Errorf("expected an error for a non-table key, got %+v", resp)
+    }
+}
+
+func TestTestConnectionReportsLatencyAndServerInfo(t *testing.T) {
+    orig := openPostgresDB
+    defer func() { openPostgresDB = orig }()
+
+    db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+    if err != nil {
+        t.Fatalf("failed to create mock: %v", err)
+    }
+    openPostgresDB = func(dsn string) (*sql.DB, error) { return db, nil }
+    mock.ExpectPing()
+    mock.ExpectQuery(`current_setting\('server_version'\), version\(\)`).WillReturnRows(
+        sqlmock.NewRows([]string{"current_setting", "version"}).AddRow("16.2", "PostgreSQL 16.2 on x86_64-pc-linux-gnu"))
+
+    m := &postgresqlPlugin{}
+    resp, err := m.TestConnection(context.Background(), &plugin.TestConnectionRequest{
+        Connection: map[string]string{"credential_blob": makeBlob(map[string]string{"host": "localhost"})},
+    })
+    if err != nil || !resp.GetOk() {
+        t.Fatalf("TestConnection: %v %s", err, resp.GetMessage())
+    }
+    if resp.LatencyMs == nil {
+        t.Error("latency not populated")
+    }
+    if v := resp.GetServerInfo()["version"]; v != "16.2" {
+        t.Errorf("server version = %q, want 16.2", v)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Errorf("unmet expectations: %v", err)
+    }
+}
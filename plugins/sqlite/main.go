@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/felixdotgo/querybox/pkg/plugin"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
@@ -29,10 +32,15 @@ func (m *sqlitePlugin) Info(ctx context.Context, _ *pluginpb.PluginV1_InfoReques
 		Description: "SQLite database driver",
 		Url:         "https://www.sqlite.org/",
 		Author:      "SQLite Consortium",
-		Capabilities: []string{"query", "explain-query", "mutate-row", "describe-schema"},
+		Capabilities: []string{"query", "explain-query", "mutate-row", "describe-schema", "ddl"},
 		Tags:        []string{"sql", "relational"},
 		License:     "Public Domain",
 		IconUrl:     "https://www.sqlite.org/images/logo-square.jpg",
+		ExampleQueries: []string{
+			"SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name;",
+			`SELECT * FROM "table_name" LIMIT 100;`,
+			"PRAGMA table_info(\"table_name\");",
+		},
 	}, nil
 }
 
@@ -55,19 +63,34 @@ func (m *sqlitePlugin) AuthForms(ctx context.Context, _ *plugin.AuthFormsRequest
 			{Type: plugin.AuthFieldPassword, Name: "token", Label: "Auth Token", Required: true, Placeholder: "your-turso-auth-token"},
 		},
 	}
-	// if OS is windows, not return turso-cloud form, because libsql driver does not support windows yet.
+
+	// turso-replica: a local file kept in sync with a remote database, so
+	// reads are served locally
+	replica := plugin.AuthForm{
+		Key:  "turso-replica",
+		Name: "Turso Embedded Replica",
+		Fields: []*plugin.AuthField{
+			{Type: plugin.AuthFieldFilePath, Name: "file", Label: "Local replica file", Required: true, Placeholder: "/path/to/replica.db"},
+			{Type: plugin.AuthFieldText, Name: "database_url", Label: "Database URL", Required: true, Placeholder: "libsql://example.aws-region.turso.io"},
+			{Type: plugin.AuthFieldPassword, Name: "token", Label: "Auth Token", Required: true, Placeholder: "your-turso-auth-token"},
+			{Type: plugin.AuthFieldNumber, Name: "sync_interval", Label: "Sync interval (seconds)", Placeholder: "0 = sync on connect only"},
+		},
+	}
+	// if OS is windows, not return the turso forms, because libsql driver does not support windows yet.
 	if strings.Contains(strings.ToLower(runtime.GOOS), "windows") {
 		return &plugin.AuthFormsResponse{Forms: map[string]*plugin.AuthForm{"basic": &basic}}, nil
 	}
-	return &plugin.AuthFormsResponse{Forms: map[string]*plugin.AuthForm{"basic": &basic, "turso-cloud": &turso}}, nil
+	return &plugin.AuthFormsResponse{Forms: map[string]*plugin.AuthForm{"basic": &basic, "turso-cloud": &turso, "turso-replica": &replica}}, nil
 }
 
+// parseCredential reads the connection map via plugin.ParseConnection, so a
+// flat {"file": ...} map works as well as a credential blob.
 func parseCredential(connection map[string]string) plugin.CredentialBlob {
-	cred, err := plugin.ParseCredentialBlob(connection)
+	form, values, err := plugin.ParseConnection(connection)
 	if err != nil {
 		return plugin.CredentialBlob{}
 	}
-	return cred
+	return plugin.CredentialBlob{Form: form, Values: values}
 }
 
 func tursoURL(c plugin.CredentialBlob) string {
@@ -81,8 +104,116 @@ func tursoURL(c plugin.CredentialBlob) string {
 	return url
 }
 
+// replicaDriver is the driver name driverDSN reports for the turso-replica
+// form.  It is not a registered database/sql driver: openDB recognises it and
+// builds a libsql embedded replica connector from the DSN instead.
+const replicaDriver = "libsql-replica"
+
+// replicaDSN encodes the turso-replica form as
+// file:<path>?primary_url=<url>&auth_token=<token>&sync_interval=<duration>.
+// sync_interval is given in seconds (a Go duration such as "90s" is also
+// accepted); zero or empty means the replica only syncs when it is opened.
+func replicaDSN(c plugin.CredentialBlob) (string, error) {
+	file := c.Values["file"]
+	if file == "" {
+		return "", fmt.Errorf("missing replica file path in connection")
+	}
+	primary := c.Values["database_url"]
+	if primary == "" {
+		return "", fmt.Errorf("missing database_url in connection")
+	}
+	interval, err := parseSyncInterval(c.Values["sync_interval"])
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Set("primary_url", primary)
+	if token := c.Values["token"]; token != "" {
+		q.Set("auth_token", token)
+	}
+	if interval > 0 {
+		q.Set("sync_interval", interval.String())
+	}
+	return "file:" + file + "?" + q.Encode(), nil
+}
+
+// parseSyncInterval accepts whole seconds or a Go duration string.
+func parseSyncInterval(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid sync_interval %q", v)
+		}
+		return time.Duration(n) * time.Second, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid sync_interval %q", v)
+	}
+	return d, nil
+}
+
+// replicaConfig is the decoded form of a replicaDSN string.
+type replicaConfig struct {
+	path         string
+	primaryURL   string
+	authToken    string
+	syncInterval time.Duration
+}
+
+func parseReplicaDSN(dsn string) (replicaConfig, error) {
+	rest, ok := strings.CutPrefix(dsn, "file:")
+	if !ok {
+		return replicaConfig{}, fmt.Errorf("invalid replica dsn")
+	}
+	// the encoded query never contains a literal '?', so split on the last
+	// one to keep file paths containing '?' intact
+	i := strings.LastIndex(rest, "?")
+	if i < 0 {
+		return replicaConfig{}, fmt.Errorf("invalid replica dsn")
+	}
+	path := rest[:i]
+	q, err := url.ParseQuery(rest[i+1:])
+	if err != nil {
+		return replicaConfig{}, fmt.Errorf("invalid replica dsn: %w", err)
+	}
+	cfg := replicaConfig{path: path, primaryURL: q.Get("primary_url"), authToken: q.Get("auth_token")}
+	if v := q.Get("sync_interval"); v != "" {
+		if cfg.syncInterval, err = time.ParseDuration(v); err != nil {
+			return replicaConfig{}, fmt.Errorf("invalid replica dsn: %w", err)
+		}
+	}
+	if cfg.path == "" || cfg.primaryURL == "" {
+		return replicaConfig{}, fmt.Errorf("invalid replica dsn")
+	}
+	return cfg, nil
+}
+
+// openDB opens the database resolved by driverDSN.  Embedded replicas need a
+// libsql connector rather than a DSN, see openReplica.
+func openDB(driver, dsn string) (*sql.DB, error) {
+	if driver == replicaDriver {
+		cfg, err := parseReplicaDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return openReplica(cfg)
+	}
+	return sql.Open(driver, dsn)
+}
+
 // driverDSN resolves the SQL driver name and DSN from the credential form.
 func driverDSN(c plugin.CredentialBlob) (driver, dsn string, err error) {
+	if c.Form == "turso-replica" {
+		dsn, err = replicaDSN(c)
+		if err != nil {
+			return "", "", err
+		}
+		return replicaDriver, dsn, nil
+	}
 	if c.Form == "turso-cloud" {
 		dsn = tursoURL(c)
 		if dsn == "" {
@@ -118,6 +249,7 @@ func (m *sqlitePlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plug
 			req.Query = applySortSQLite(req.Query, col, dir)
 		}
 	}
+	req.Query = plugin.ApplyLimitOffset(req.Query, req.GetLimit(), req.GetOffset())
 
 	c := parseCredential(req.Connection)
 
@@ -126,19 +258,24 @@ func (m *sqlitePlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plug
 		return &plugin.ExecResponse{Error: err.Error()}, nil
 	}
 
-	db, err := sql.Open(driver, dsn)
+	db, err := openDB(driver, dsn)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("open error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: fmt.Sprintf("open error: %v", err), ErrorCode: plugin.ClassifyError(err)}, nil
 	}
 	defer db.Close()
 
-	// Use Exec for non-SELECT statements (DDL, DML) so they succeed even when
-	// they return no rows.  db.Query on a DROP/CREATE would drain silently on
-	// some drivers and return a confusing empty-result instead of an error.
-	trimmed := strings.TrimSpace(strings.ToUpper(req.Query))
-	if !strings.HasPrefix(trimmed, "SELECT") && !strings.HasPrefix(trimmed, "WITH") && !strings.HasPrefix(trimmed, "PRAGMA") {
-		if _, execErr := db.Exec(req.Query); execErr != nil {
-			return &plugin.ExecResponse{Error: fmt.Sprintf("exec error: %v", execErr)}, nil
+	// Use Exec for statements that produce no result set (DDL, DML without
+	// RETURNING, as classified by plugin.StatementReturnsRows).  db.Query on
+	// a DROP/CREATE would drain silently on some drivers and return a
+	// confusing empty-result instead of an error.  DML additionally reports
+	// the affected-row count and last insert id.
+	if !plugin.StatementReturnsRows(req.Query) {
+		res, execErr := db.Exec(req.Query)
+		if execErr != nil {
+			return &plugin.ExecResponse{Error: fmt.Sprintf("exec error: %v", execErr), ErrorCode: plugin.ClassifyError(execErr)}, nil
+		}
+		if plugin.IsDMLStatement(req.Query) {
+			return &plugin.ExecResponse{Result: plugin.AffectedRowsResult(res)}, nil
 		}
 		return &plugin.ExecResponse{
 			Result: &plugin.ExecResult{
@@ -151,47 +288,15 @@ func (m *sqlitePlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plug
 
 	rows, err := db.Query(req.Query)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("query error: %v", err)}, nil
+		return &plugin.ExecResponse{Error: fmt.Sprintf("query error: %v", err), ErrorCode: plugin.ClassifyError(err)}, nil
 	}
 	defer rows.Close()
 
-	cols, err := rows.Columns()
+	result, err := plugin.SQLRowsToResult(rows, 0)
 	if err != nil {
-		return &plugin.ExecResponse{Error: fmt.Sprintf("cols error: %v", err)}, nil
-	}
-
-	colMeta := make([]*plugin.Column, len(cols))
-	for i, c := range cols {
-		colMeta[i] = &plugin.Column{Name: c}
-	}
-
-	var rowResults []*plugin.Row
-	for rows.Next() {
-		vals := make([]interface{}, len(cols))
-		ptrs := make([]interface{}, len(cols))
-		for i := range vals {
-			ptrs[i] = &vals[i]
-		}
-		if err := rows.Scan(ptrs...); err != nil {
-			return &plugin.ExecResponse{Error: fmt.Sprintf("scan error: %v", err)}, nil
-		}
-		strs := make([]string, len(cols))
-		for i, v := range vals {
-			strs[i] = plugin.FormatSQLValue(v)
-		}
-		rowResults = append(rowResults, &plugin.Row{Values: strs})
+		return &plugin.ExecResponse{Error: err.Error(), ErrorCode: plugin.ClassifyError(err)}, nil
 	}
-
-	return &plugin.ExecResponse{
-		Result: &plugin.ExecResult{
-			Payload: &pluginpb.PluginV1_ExecResult_Sql{
-				Sql: &plugin.SqlResult{
-					Columns: colMeta,
-					Rows:    rowResults,
-				},
-			},
-		},
-	}, nil
+	return &plugin.ExecResponse{Result: result}, nil
 }
 
 // ConnectionTree returns a list of tables in the SQLite database.
@@ -203,7 +308,7 @@ func (m *sqlitePlugin) ConnectionTree(ctx context.Context, req *plugin.Connectio
 		return &plugin.ConnectionTreeResponse{}, nil
 	}
 
-	db, err := sql.Open(driver, dsn)
+	db, err := openDB(driver, dsn)
 	if err != nil {
 		return &plugin.ConnectionTreeResponse{}, nil
 	}
@@ -227,7 +332,7 @@ func (m *sqlitePlugin) ConnectionTree(ctx context.Context, req *plugin.Connectio
 			NodeType: plugin.ConnectionTreeNodeTypeTable,
 			Actions: []*plugin.ConnectionTreeAction{
 				{Type: plugin.ConnectionTreeActionSelect, Title: "Select rows", Query: fmt.Sprintf(`SELECT * FROM "%s"`, tbl), Hidden: true, NewTab: true},
-				{Type: plugin.ConnectionTreeActionDropTable, Title: "Drop table", Query: fmt.Sprintf(`DROP TABLE "%s";`, tbl)},
+				{Type: plugin.ConnectionTreeActionDropTable, Title: "Drop table", Query: fmt.Sprintf(`DROP TABLE "%s";`, tbl), Destructive: true},
 			},
 		})
 	}
@@ -248,9 +353,34 @@ func (m *sqlitePlugin) ConnectionTree(ctx context.Context, req *plugin.Connectio
 		},
 	}
 
-	return &plugin.ConnectionTreeResponse{Nodes: append([]*plugin.ConnectionTreeNode{createNode}, tableNodes...)}, nil
+	// Foreign keys across every table, as an edge list the UI can render or
+	// feed into an ER diagram.
+	fkNode := &plugin.ConnectionTreeNode{
+		Key:      "__foreign_keys__",
+		Label:    "Foreign keys",
+		NodeType: plugin.ConnectionTreeNodeTypeAction,
+		Actions: []*plugin.ConnectionTreeAction{
+			{Type: plugin.ConnectionTreeActionDescribe, Title: "Foreign keys", Query: foreignKeyGraphQuery, NewTab: true},
+		},
+	}
+
+	return &plugin.ConnectionTreeResponse{Nodes: append([]*plugin.ConnectionTreeNode{createNode, fkNode}, tableNodes...)}, nil
 }
 
+// foreignKeyGraphQuery lists every foreign-key relationship in the database,
+// one row per referencing column, by joining each table with its
+// pragma_foreign_key_list.  A reference written without a column list
+// ("REFERENCES users") targets the parent's primary key; the pragma reports
+// NULL for "to" then, so the matching primary-key column is looked up.
+const foreignKeyGraphQuery = `SELECT m.name AS from_table,
+       f."from" AS from_column,
+       f."table" AS to_table,
+       COALESCE(f."to", (SELECT p.name FROM pragma_table_info(f."table") p WHERE p.pk = f.seq + 1)) AS to_column
+FROM sqlite_master m
+JOIN pragma_foreign_key_list(m.name) f
+WHERE m.type = 'table'
+ORDER BY m.name, f.id, f.seq;`
+
 // DescribeSchema returns column/index metadata for one or more tables.
 func (m *sqlitePlugin) DescribeSchema(ctx context.Context, req *plugin.DescribeSchemaRequest) (*plugin.DescribeSchemaResponse, error) {
     c := parseCredential(req.Connection)
@@ -258,7 +388,7 @@ func (m *sqlitePlugin) DescribeSchema(ctx context.Context, req *plugin.DescribeS
     if err != nil {
         return &plugin.DescribeSchemaResponse{}, nil
     }
-    db, err := sql.Open(driver, dsn)
+    db, err := openDB(driver, dsn)
     if err != nil {
         return &plugin.DescribeSchemaResponse{}, nil
     }
@@ -354,7 +484,7 @@ func (m *sqlitePlugin) GetCompletionFields(ctx context.Context, req *plugin.GetC
 	if err != nil {
 		return &plugin.GetCompletionFieldsResponse{}, nil
 	}
-	db, err := sql.Open(driver, dsn)
+	db, err := openDB(driver, dsn)
 	if err != nil {
 		return &plugin.GetCompletionFieldsResponse{}, nil
 	}
@@ -415,7 +545,7 @@ func (m *sqlitePlugin) MutateRow(ctx context.Context, req *plugin.MutateRowReque
 		return &plugin.MutateRowResponse{Success: false, Error: "invalid connection"}, nil
 	}
 
-	db, err := sql.Open(driver, dsn)
+	db, err := openDB(driver, dsn)
 	if err != nil {
 		return &plugin.MutateRowResponse{Success: false, Error: fmt.Sprintf("open error: %v", err)}, nil
 	}
@@ -455,24 +585,87 @@ func (m *sqlitePlugin) MutateRow(ctx context.Context, req *plugin.MutateRowReque
 }
 
 func (m *sqlitePlugin) TestConnection(ctx context.Context, req *plugin.TestConnectionRequest) (*plugin.TestConnectionResponse, error) {
-	c := parseCredential(req.Connection)
+	c := parseCredential(plugin.WithCredentialForm(req.Connection, req.GetForm()))
 
 	driver, dsn, err := driverDSN(c)
 	if err != nil {
 		return &plugin.TestConnectionResponse{Ok: false, Message: err.Error()}, nil
 	}
 
-	db, err := sql.Open(driver, dsn)
+	db, err := openDB(driver, dsn)
 	if err != nil {
 		return &plugin.TestConnectionResponse{Ok: false, Message: fmt.Sprintf("open error: %v", err)}, nil
 	}
 	defer db.Close()
 
-	if err := db.Ping(); err != nil {
+	latency, err := plugin.PingLatency(ctx, db)
+	if err != nil {
 		return &plugin.TestConnectionResponse{Ok: false, Message: fmt.Sprintf("ping error: %v", err)}, nil
 	}
 
-	return &plugin.TestConnectionResponse{Ok: true, Message: "Connection successful"}, nil
+	return &plugin.TestConnectionResponse{
+		Ok:         true,
+		Message:    "Connection successful",
+		LatencyMs:  &latency,
+		ServerInfo: plugin.SQLServerInfo(ctx, db, "SELECT sqlite_version()", "version"),
+	}, nil
+}
+
+// GenerateDDL returns the stored CREATE TABLE statement for the table behind
+// a connection tree node key (the table name) followed by its explicit
+// CREATE INDEX statements.
+func (m *sqlitePlugin) GenerateDDL(ctx context.Context, req *plugin.GenerateDDLRequest) (*plugin.GenerateDDLResponse, error) {
+	if req.NodeKey == "" {
+		return &plugin.GenerateDDLResponse{Error: "node key (table name) is required"}, nil
+	}
+	c := parseCredential(req.Connection)
+	driver, dsn, err := driverDSN(c)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: err.Error()}, nil
+	}
+	db, err := openDB(driver, dsn)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: fmt.Sprintf("open error: %v", err)}, nil
+	}
+	defer db.Close()
+
+	ddl, err := tableDDL(ctx, db, req.NodeKey)
+	if err != nil {
+		return &plugin.GenerateDDLResponse{Error: err.Error()}, nil
+	}
+	return &plugin.GenerateDDLResponse{Ddl: ddl}, nil
+}
+
+// tableDDL reads the CREATE statements for table and its indexes from
+// sqlite_master.  Automatic indexes (PRIMARY KEY/UNIQUE) have no stored SQL
+// and are already part of the table definition.
+func tableDDL(ctx context.Context, db *sql.DB, table string) (string, error) {
+	var create string
+	err := db.QueryRowContext(ctx, `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&create)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("table %q not found", table)
+	}
+	if err != nil {
+		return "", err
+	}
+	stmts := []string{create + ";"}
+
+	rows, err := db.QueryContext(ctx, `SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL ORDER BY name`, table)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var idx string
+		if err := rows.Scan(&idx); err != nil {
+			return "", err
+		}
+		stmts = append(stmts, idx+";")
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(stmts, "\n"), nil
 }
 
 func main() {
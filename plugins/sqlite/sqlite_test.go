@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/felixdotgo/querybox/pkg/plugin"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
 	_ "modernc.org/sqlite"
 )
@@ -211,3 +214,309 @@ func TestMutateRowMissingFilter(t *testing.T) {
         t.Error("expected failure when filter is empty")
     }
 }
+
+func TestTestConnectionHonoursForm(t *testing.T) {
+    fname, cleanup := prepareDB(t)
+    defer cleanup()
+
+    // the blob records turso-cloud but only carries a local file path
+    blob, _ := json.Marshal(map[string]interface{}{
+        "form":   "turso-cloud",
+        "values": map[string]string{"file": fname},
+    })
+    conn := map[string]string{"credential_blob": string(blob)}
+
+    p := &sqlitePlugin{}
+    resp, err := p.TestConnection(context.Background(), &pluginpb.PluginV1_TestConnectionRequest{Connection: conn})
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if resp.Ok {
+        t.Fatal("expected the recorded turso-cloud form to fail without database_url")
+    }
+
+    resp, err = p.TestConnection(context.Background(), &pluginpb.PluginV1_TestConnectionRequest{Connection: conn, Form: "basic"})
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if !resp.Ok {
+        t.Fatalf("expected basic form to succeed, got %q", resp.Message)
+    }
+}
+
+func TestDriverDSNTursoReplica(t *testing.T) {
+    c := plugin.CredentialBlob{Form: "turso-replica", Values: map[string]string{
+        "file":          "/data/replica?.db",
+        "database_url":  "libsql://example.turso.io",
+        "token":         "s3cr&t",
+        "sync_interval": "30",
+    }}
+    driver, dsn, err := driverDSN(c)
+    if err != nil {
+        t.Fatalf("driverDSN: %v", err)
+    }
+    if driver != replicaDriver {
+        t.Errorf("driver = %q, want %q", driver, replicaDriver)
+    }
+    cfg, err := parseReplicaDSN(dsn)
+    if err != nil {
+        t.Fatalf("parseReplicaDSN(%q): %v", dsn, err)
+    }
+    want := replicaConfig{
+        path:         "/data/replica?.db",
+        primaryURL:   "libsql://example.turso.io",
+        authToken:    "s3cr&t",
+        syncInterval: 30 * time.Second,
+    }
+    if cfg != want {
+        t.Errorf("config = %+v, want %+v", cfg, want)
+    }
+}
+
+func TestDriverDSNTursoReplicaValidation(t *testing.T) {
+    tests := []struct {
+        name   string
+        values map[string]string
+    }{
+        {"missing file", map[string]string{"database_url": "libsql://x"}},
+        {"missing url", map[string]string{"file": "/tmp/r.db"}},
+        {"bad interval", map[string]string{"file": "/tmp/r.db", "database_url": "libsql://x", "sync_interval": "soon"}},
+        {"negative interval", map[string]string{"file": "/tmp/r.db", "database_url": "libsql://x", "sync_interval": "-5"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, _, err := driverDSN(plugin.CredentialBlob{Form: "turso-replica", Values: tt.values}); err == nil {
+                t.Error("expected error")
+            }
+        })
+    }
+}
+
+func TestParseSyncInterval(t *testing.T) {
+    tests := map[string]time.Duration{"": 0, "0": 0, "45": 45 * time.Second, "2m": 2 * time.Minute}
+    for in, want := range tests {
+        got, err := parseSyncInterval(in)
+        if err != nil || got != want {
+            t.Errorf("parseSyncInterval(%q) = %v, %v; want %v", in, got, err, want)
+        }
+    }
+}
+
+func TestGenerateDDL(t *testing.T) {
+    fname, cleanup := prepareDB(t)
+    defer cleanup()
+
+    db, err := sql.Open("sqlite", fname)
+    if err != nil {
+        t.Fatalf("open db: %v", err)
+    }
+    if _, err := db.Exec(`CREATE INDEX users_name_idx ON users (name)`); err != nil {
+        t.Fatalf("create index: %v", err)
+    }
+    db.Close()
+
+    conn := map[string]string{"credential_blob": plugin.MakeTestBlob(map[string]string{"file": fname})}
+    p := &sqlitePlugin{}
+    resp, err := p.GenerateDDL(context.Background(), &pluginpb.PluginV1_GenerateDDLRequest{Connection: conn, NodeKey: "users"})
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if resp.Error != "" {
+        t.Fatalf("GenerateDDL reported error: %s", resp.Error)
+    }
+    if !strings.HasPrefix(resp.Ddl, "CREATE TABLE users (") {
+        t.Errorf("expected CREATE TABLE first, got %q", resp.Ddl)
+    }
+    if !strings.HasSuffix(resp.Ddl, "\nCREATE INDEX users_name_idx ON users (name);") {
+        t.Errorf("expected trailing CREATE INDEX, got %q", resp.Ddl)
+    }
+
+    resp, err = p.GenerateDDL(context.Background(), &pluginpb.PluginV1_GenerateDDLRequest{Connection: conn, NodeKey: "missing"})
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if resp.Error == "" {
+        t.Error("expected an error for an unknown table")
+    }
+}
+
+func TestConnectionTreeMarksDropDestructive(t *testing.T) {
+    fname, cleanup := prepareDB(t)
+    defer cleanup()
+
+    conn := map[string]string{"credential_blob": plugin.MakeTestBlob(map[string]string{"file": fname})}
+    p := &sqlitePlugin{}
+    resp, err := p.ConnectionTree(context.Background(), &plugin.ConnectionTreeRequest{Connection: conn})
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    var drops int
+    for _, n := range resp.Nodes {
+        for _, a := range n.Actions {
+            switch a.Type {
+            case plugin.ConnectionTreeActionDropTable:
+                drops++
+                if !a.Destructive {
+                    t.Errorf("drop action on %q is not marked destructive", n.Key)
+                }
+            default:
+                if a.Destructive {
+                    t.Errorf("%s action on %q unexpectedly marked destructive", a.Type, n.Key)
+                }
+            }
+        }
+    }
+    if drops == 0 {
+        t.Fatal("no drop-table action found")
+    }
+}
+
+func TestForeignKeyGraph(t *testing.T) {
+    fname, cleanup := prepareDB(t)
+    defer cleanup()
+
+    db, err := sql.Open("sqlite", fname)
+    if err != nil {
+        t.Fatalf("open db: %v", err)
+    }
+    // orders references users by its implicit primary key; lines uses an
+    // explicit column list and a composite key
+    _, err = db.Exec(`
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users);
+CREATE TABLE products (sku TEXT, variant TEXT, PRIMARY KEY (sku, variant));
+CREATE TABLE lines (
+    order_id INTEGER REFERENCES orders(id),
+    sku TEXT,
+    variant TEXT,
+    FOREIGN KEY (sku, variant) REFERENCES products(sku, variant)
+);`)
+    db.Close()
+    if err != nil {
+        t.Fatalf("create tables: %v", err)
+    }
+
+    conn := map[string]string{"credential_blob": plugin.MakeTestBlob(map[string]string{"file": fname})}
+    p := &sqlitePlugin{}
+    tree, err := p.ConnectionTree(context.Background(), &plugin.ConnectionTreeRequest{Connection: conn})
+    if err != nil {
+        t.Fatalf("ConnectionTree: %v", err)
+    }
+    var query string
+    for _, n := range tree.Nodes {
+        if n.Key == "__foreign_keys__" && len(n.Actions) == 1 {
+            query = n.Actions[0].Query
+        }
+    }
+    if query == "" {
+        t.Fatal("no foreign keys action in the tree")
+    }
+
+    resp, err := p.Exec(context.Background(), &plugin.ExecRequest{Connection: conn, Query: query})
+    if err != nil || resp.Error != "" {
+        t.Fatalf("exec: %v %s", err, resp.GetError())
+    }
+    sqlRes := resp.GetResult().GetSql()
+    var cols []string
+    for _, c := range sqlRes.GetColumns() {
+        cols = append(cols, c.Name)
+    }
+    if strings.Join(cols, ",") != "from_table,from_column,to_table,to_column" {
+        t.Errorf("columns = %v", cols)
+    }
+    var got []string
+    for _, r := range sqlRes.GetRows() {
+        got = append(got, strings.Join(r.Values, " "))
+    }
+    want := []string{
+        "lines sku products sku",
+        "lines variant products variant",
+        "lines order_id orders id",
+        "orders user_id users id",
+    }
+    if strings.Join(got, "\n") != strings.Join(want, "\n") {
+        t.Errorf("edges:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+    }
+}
+
+func TestExecReportsRowsAffected(t *testing.T) {
+    fname, cleanup := prepareDB(t)
+    defer cleanup()
+
+    p := &sqlitePlugin{}
+    exec := func(query string) *pluginpb.PluginV1_SqlResult {
+        t.Helper()
+        resp, err := p.Exec(context.Background(), &plugin.ExecRequest{Connection: makeConn(t, fname), Query: query})
+        if err != nil || resp.GetError() != "" {
+            t.Fatalf("Exec(%q): %v %s", query, err, resp.GetError())
+        }
+        return resp.GetResult().GetSql()
+    }
+
+    res := exec(`INSERT INTO users(id, name, age) VALUES (7, 'Alice', 30)`)
+    if res.RowsAffected == nil || res.GetRowsAffected() != 1 || res.GetLastInsertId() != 7 {
+        t.Errorf("insert: rows affected = %v, last insert id = %v", res.RowsAffected, res.LastInsertId)
+    }
+    exec(`INSERT INTO users(id, name, age) VALUES (8, 'Bob', 30)`)
+
+    res = exec(`UPDATE users SET age = 31 WHERE age = 30`)
+    if res.GetRowsAffected() != 2 {
+        t.Errorf("update: rows affected = %v, want 2", res.RowsAffected)
+    }
+
+    // DDL and queries carry no count
+    if res := exec(`CREATE INDEX users_age ON users(age)`); res.RowsAffected != nil {
+        t.Errorf("ddl: rows affected = %d, want unset", res.GetRowsAffected())
+    }
+    if res := exec(`SELECT * FROM users`); res.RowsAffected != nil || len(res.GetRows()) != 2 {
+        t.Errorf("select: %v", res)
+    }
+}
+
+// Comments, CTEs, EXPLAIN and RETURNING must not fool the Query/Exec split.
+func TestExecClassifiesStatements(t *testing.T) {
+    fname, cleanup := prepareDB(t)
+    defer cleanup()
+
+    p := &sqlitePlugin{}
+    exec := func(query string) *pluginpb.PluginV1_SqlResult {
+        t.Helper()
+        resp, err := p.Exec(context.Background(), &plugin.ExecRequest{Connection: makeConn(t, fname), Query: query})
+        if err != nil || resp.GetError() != "" {
+            t.Fatalf("Exec(%q): %v %s", query, err, resp.GetError())
+        }
+        return resp.GetResult().GetSql()
+    }
+
+    res := exec("WITH src(id, name, age) AS (VALUES (1, 'a', 20), (2, 'b', 21)) INSERT INTO users(id, name, age) SELECT * FROM src")
+    if res.GetRowsAffected() != 2 {
+        t.Errorf("WITH ... INSERT: rows affected = %v, want 2", res.RowsAffected)
+    }
+    if res := exec("-- everyone\nSELECT name FROM users ORDER BY id"); len(res.GetRows()) != 2 {
+        t.Errorf("commented select rows = %v", res.GetRows())
+    }
+    if res := exec("UPDATE users SET age = age + 1 WHERE id = 1 RETURNING age"); len(res.GetRows()) != 1 || res.GetRows()[0].Values[0] != "21" {
+        t.Errorf("RETURNING rows = %v", res.GetRows())
+    }
+    if res := exec("EXPLAIN QUERY PLAN SELECT * FROM users WHERE id = 1"); len(res.GetRows()) == 0 {
+        t.Error("EXPLAIN returned no rows")
+    }
+}
+
+func TestTestConnectionReportsLatencyAndVersion(t *testing.T) {
+    fname, cleanup := prepareDB(t)
+    defer cleanup()
+
+    p := &sqlitePlugin{}
+    resp, err := p.TestConnection(context.Background(), &pluginpb.PluginV1_TestConnectionRequest{
+        Connection: map[string]string{"file": fname},
+    })
+    if err != nil || !resp.Ok {
+        t.Fatalf("TestConnection: %v %s", err, resp.GetMessage())
+    }
+    if resp.LatencyMs == nil {
+        t.Error("latency not populated")
+    }
+    if resp.GetServerInfo()["version"] == "" {
+        t.Errorf("server info = %v, want the sqlite version", resp.GetServerInfo())
+    }
+}
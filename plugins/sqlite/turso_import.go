@@ -6,4 +6,27 @@ package main
 // import libsql driver on non-windows platforms so the "libsql"
 // driver name is registered.  the package doesn't build on
 // windows, hence the build constraint.
-import _ "github.com/tursodatabase/go-libsql"
+import (
+	"database/sql"
+
+	libsql "github.com/tursodatabase/go-libsql"
+)
+
+// openReplica opens a libsql embedded replica: reads are served from the
+// local file, which is synced against the primary on open and then every
+// syncInterval (when non-zero).  Closing the returned DB closes the
+// connector and stops background syncing.
+func openReplica(cfg replicaConfig) (*sql.DB, error) {
+	var opts []libsql.Option
+	if cfg.authToken != "" {
+		opts = append(opts, libsql.WithAuthToken(cfg.authToken))
+	}
+	if cfg.syncInterval > 0 {
+		opts = append(opts, libsql.WithSyncInterval(cfg.syncInterval))
+	}
+	connector, err := libsql.NewEmbeddedReplicaConnector(cfg.path, cfg.primaryURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
@@ -3,7 +3,18 @@
 
 package main
 
+import (
+	"database/sql"
+	"fmt"
+)
+
 // stub file for windows; do not import go-libsql since it has no
 // windows-compatible sources.  absence of the import means the
 // "libsql" driver won't be registered and attempting to use it
 // will error earlier in driverDSN.
+
+// openReplica reports that embedded replicas need libsql, which is not
+// available on windows.
+func openReplica(cfg replicaConfig) (*sql.DB, error) {
+	return nil, fmt.Errorf("turso embedded replicas are not supported on windows")
+}
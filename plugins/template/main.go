@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/felixdotgo/querybox/pkg/plugin"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
@@ -26,6 +27,9 @@ func (t *templatePlugin) Info(ctx context.Context, _ *pluginpb.PluginV1_InfoRequ
 		License:     "MIT",
 		IconUrl:     "https://example.com/icon.png",
 		Contact:     "support@example.com",
+		// `ExampleQueries` are snippets the host may offer in the editor of
+		// a new connection; list the most useful first.
+		ExampleQueries: []string{"SELECT 1"},
 		// `Metadata` is an arbitrary key/value map exposed via the plugin
 		// manager.  It can be used by the frontend for driver-specific hints;
 		// for example, supplying
@@ -42,20 +46,24 @@ func (t *templatePlugin) Info(ctx context.Context, _ *pluginpb.PluginV1_InfoRequ
 }
 
 func (t *templatePlugin) Exec(ctx context.Context, req *plugin.ExecRequest) (*plugin.ExecResponse, error) {
-	// return a simple key/value map containing the query and connection for demo
-	data := map[string]string{"query": req.Query}
-	for k, v := range req.Connection {
-		data[k] = v
+	// return a simple key/value map containing the query and connection for
+	// demo; NewKeyValueResult keeps the entries in the order listed here
+	pairs := []string{"query", req.Query}
+	keys := make([]string, 0, len(req.Connection))
+	for k := range req.Connection {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pairs = append(pairs, k, req.Connection[k])
 	}
 	if req.Options != nil {
-		data["options"] = fmt.Sprintf("%v", req.Options)
+		pairs = append(pairs, "options", fmt.Sprintf("%v", req.Options))
 	}
 	return &plugin.ExecResponse{
 		Result: &plugin.ExecResult{
 			Payload: &pluginpb.PluginV1_ExecResult_Kv{
-				Kv: &plugin.KeyValueResult{
-					Data: data,
-				},
+				Kv: plugin.NewKeyValueResult(pairs...),
 			},
 		},
 	}, nil
@@ -91,13 +99,10 @@ func (t *templatePlugin) ConnectionTree(ctx context.Context, req *plugin.Connect
 // In a real plugin this would execute the query and return results or perform some other side effect.
 func (t *templatePlugin) ConnectionTreeAction(req *plugin.ConnectionTreeAction) (*plugin.ExecResponse, error) {
 	// simply echo back the action's query for demo purposes
-	data := map[string]string{"action_query": req.Query}
 	return &plugin.ExecResponse{
 		Result: &plugin.ExecResult{
 			Payload: &pluginpb.PluginV1_ExecResult_Kv{
-				Kv: &plugin.KeyValueResult{
-					Data: data,
-				},
+				Kv: plugin.NewKeyValueResult("action_query", req.Query),
 			},
 		},
 	}, nil
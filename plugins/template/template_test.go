@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/felixdotgo/querybox/pkg/plugin"
@@ -27,3 +28,18 @@ func TestTemplatePlugin_MutateRow(t *testing.T) {
         t.Errorf("expected success, got %+v", resp)
     }
 }
+
+func TestTemplatePlugin_ExecKeepsKeyOrder(t *testing.T) {
+    p := &templatePlugin{}
+    resp, err := p.Exec(context.Background(), &plugin.ExecRequest{
+        Connection: map[string]string{"user": "u", "host": "h"},
+        Query:      "SELECT 1",
+    })
+    if err != nil {
+        t.Fatalf("Exec returned error: %v", err)
+    }
+    kv := resp.GetResult().GetKv()
+    if want := []string{"query", "host", "user"}; !reflect.DeepEqual(kv.GetKeys(), want) {
+        t.Errorf("keys = %v, want %v", kv.GetKeys(), want)
+    }
+}
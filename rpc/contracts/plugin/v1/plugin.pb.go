@@ -69,6 +69,69 @@ func (PluginV1_Type) EnumDescriptor() ([]byte, []int) {
 	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 0}
 }
 
+// ErrorCode is a machine-readable classification of a plugin error.
+// ERROR_CODE_UNKNOWN means the plugin did not (or could not) classify it.
+type PluginV1_ErrorCode int32
+
+const (
+	PluginV1_ERROR_CODE_UNKNOWN           PluginV1_ErrorCode = 0
+	PluginV1_ERROR_CODE_AUTH_FAILED       PluginV1_ErrorCode = 1 // credentials rejected
+	PluginV1_ERROR_CODE_TIMEOUT           PluginV1_ErrorCode = 2 // statement or connection timed out / was cancelled
+	PluginV1_ERROR_CODE_SYNTAX_ERROR      PluginV1_ErrorCode = 3 // query could not be parsed
+	PluginV1_ERROR_CODE_CONNECTION_FAILED PluginV1_ErrorCode = 4 // server unreachable or connection dropped
+	PluginV1_ERROR_CODE_NOT_FOUND         PluginV1_ErrorCode = 5 // database, table or other object does not exist
+	PluginV1_ERROR_CODE_UNSUPPORTED       PluginV1_ErrorCode = 6 // operation not supported by the driver/server
+)
+
+// Enum value maps for PluginV1_ErrorCode.
+var (
+	PluginV1_ErrorCode_name = map[int32]string{
+		0: "ERROR_CODE_UNKNOWN",
+		1: "ERROR_CODE_AUTH_FAILED",
+		2: "ERROR_CODE_TIMEOUT",
+		3: "ERROR_CODE_SYNTAX_ERROR",
+		4: "ERROR_CODE_CONNECTION_FAILED",
+		5: "ERROR_CODE_NOT_FOUND",
+		6: "ERROR_CODE_UNSUPPORTED",
+	}
+	PluginV1_ErrorCode_value = map[string]int32{
+		"ERROR_CODE_UNKNOWN":           0,
+		"ERROR_CODE_AUTH_FAILED":       1,
+		"ERROR_CODE_TIMEOUT":           2,
+		"ERROR_CODE_SYNTAX_ERROR":      3,
+		"ERROR_CODE_CONNECTION_FAILED": 4,
+		"ERROR_CODE_NOT_FOUND":         5,
+		"ERROR_CODE_UNSUPPORTED":       6,
+	}
+)
+
+func (x PluginV1_ErrorCode) Enum() *PluginV1_ErrorCode {
+	p := new(PluginV1_ErrorCode)
+	*p = x
+	return p
+}
+
+func (x PluginV1_ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PluginV1_ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_contracts_plugin_v1_plugin_proto_enumTypes[1].Descriptor()
+}
+
+func (PluginV1_ErrorCode) Type() protoreflect.EnumType {
+	return &file_contracts_plugin_v1_plugin_proto_enumTypes[1]
+}
+
+func (x PluginV1_ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PluginV1_ErrorCode.Descriptor instead.
+func (PluginV1_ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 1}
+}
+
 // NodeType is an optional hint for the frontend icon renderer and lets Go
 // code reference well-known node kinds via generated constants instead of
 // raw strings.
@@ -126,11 +189,11 @@ func (x PluginV1_NodeType) String() string {
 }
 
 func (PluginV1_NodeType) Descriptor() protoreflect.EnumDescriptor {
-	return file_contracts_plugin_v1_plugin_proto_enumTypes[1].Descriptor()
+	return file_contracts_plugin_v1_plugin_proto_enumTypes[2].Descriptor()
 }
 
 func (PluginV1_NodeType) Type() protoreflect.EnumType {
-	return &file_contracts_plugin_v1_plugin_proto_enumTypes[1]
+	return &file_contracts_plugin_v1_plugin_proto_enumTypes[2]
 }
 
 func (x PluginV1_NodeType) Number() protoreflect.EnumNumber {
@@ -139,7 +202,7 @@ func (x PluginV1_NodeType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PluginV1_NodeType.Descriptor instead.
 func (PluginV1_NodeType) EnumDescriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 1}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 2}
 }
 
 // FieldType defines the type of input field to render for authentication details.
@@ -153,6 +216,11 @@ const (
 	PluginV1_AuthField_CHECKBOX      PluginV1_AuthField_FieldType = 4
 	PluginV1_AuthField_SELECT        PluginV1_AuthField_FieldType = 5
 	PluginV1_AuthField_FILE_PATH     PluginV1_AuthField_FieldType = 6
+	// MULTISELECT picks any number of values, from options when given or
+	// typed freely otherwise.  The value is carried in the credential blob
+	// as a JSON array of strings (a comma-separated list is also accepted);
+	// plugins read it with plugin.ParseMultiValue.
+	PluginV1_AuthField_MULTISELECT PluginV1_AuthField_FieldType = 7
 )
 
 // Enum value maps for PluginV1_AuthField_FieldType.
@@ -165,6 +233,7 @@ var (
 		4: "CHECKBOX",
 		5: "SELECT",
 		6: "FILE_PATH",
+		7: "MULTISELECT",
 	}
 	PluginV1_AuthField_FieldType_value = map[string]int32{
 		"FIELD_UNKNOWN": 0,
@@ -174,6 +243,7 @@ var (
 		"CHECKBOX":      4,
 		"SELECT":        5,
 		"FILE_PATH":     6,
+		"MULTISELECT":   7,
 	}
 )
 
@@ -188,11 +258,11 @@ func (x PluginV1_AuthField_FieldType) String() string {
 }
 
 func (PluginV1_AuthField_FieldType) Descriptor() protoreflect.EnumDescriptor {
-	return file_contracts_plugin_v1_plugin_proto_enumTypes[2].Descriptor()
+	return file_contracts_plugin_v1_plugin_proto_enumTypes[3].Descriptor()
 }
 
 func (PluginV1_AuthField_FieldType) Type() protoreflect.EnumType {
-	return &file_contracts_plugin_v1_plugin_proto_enumTypes[2]
+	return &file_contracts_plugin_v1_plugin_proto_enumTypes[3]
 }
 
 func (x PluginV1_AuthField_FieldType) Number() protoreflect.EnumNumber {
@@ -201,7 +271,7 @@ func (x PluginV1_AuthField_FieldType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PluginV1_AuthField_FieldType.Descriptor instead.
 func (PluginV1_AuthField_FieldType) EnumDescriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 15, 0}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 16, 0}
 }
 
 // OperationType defines the type of mutation operation to perform.
@@ -241,11 +311,11 @@ func (x PluginV1_MutateRowRequest_OperationType) String() string {
 }
 
 func (PluginV1_MutateRowRequest_OperationType) Descriptor() protoreflect.EnumDescriptor {
-	return file_contracts_plugin_v1_plugin_proto_enumTypes[3].Descriptor()
+	return file_contracts_plugin_v1_plugin_proto_enumTypes[4].Descriptor()
 }
 
 func (PluginV1_MutateRowRequest_OperationType) Type() protoreflect.EnumType {
-	return &file_contracts_plugin_v1_plugin_proto_enumTypes[3]
+	return &file_contracts_plugin_v1_plugin_proto_enumTypes[4]
 }
 
 func (x PluginV1_MutateRowRequest_OperationType) Number() protoreflect.EnumNumber {
@@ -254,7 +324,7 @@ func (x PluginV1_MutateRowRequest_OperationType) Number() protoreflect.EnumNumbe
 
 // Deprecated: Use PluginV1_MutateRowRequest_OperationType.Descriptor instead.
 func (PluginV1_MutateRowRequest_OperationType) EnumDescriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 28, 0}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 29, 0}
 }
 
 // PluginV1 defines the data structures for plugin information, execution, and authentication forms.
@@ -345,15 +415,22 @@ type PluginV1_InfoResponse struct {
 	// arbitrary plugin-specific info.  Hosts may look for known
 	// keys such as `simple_icon` (a simple-icons name) to render branded
 	// database icons in the UI; unknown keys must be ignored by clients.
-	Metadata      map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // arbitrary plugin-specific info
-	Settings      map[string]string `protobuf:"bytes,8,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // hints the core can use (defaults, etc.)
-	Capabilities  []string          `protobuf:"bytes,9,rep,name=capabilities,proto3" json:"capabilities,omitempty"`                                                                   // use in background to determine which plugins support which features (e.g. "transactions", "stored procedures", "json support")
-	Tags          []string          `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`                                                                                  // categories/statistics
-	License       string            `protobuf:"bytes,11,opt,name=license,proto3" json:"license,omitempty"`                                                                            // SPDX identifier or text
-	IconUrl       string            `protobuf:"bytes,12,opt,name=icon_url,json=iconUrl,proto3" json:"icon_url,omitempty"`                                                             // link to a small icon
-	Contact       string            `protobuf:"bytes,13,opt,name=contact,proto3" json:"contact,omitempty"`                                                                            // maintainer email/URL
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Metadata     map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // arbitrary plugin-specific info
+	Settings     map[string]string `protobuf:"bytes,8,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // hints the core can use (defaults, etc.)
+	Capabilities []string          `protobuf:"bytes,9,rep,name=capabilities,proto3" json:"capabilities,omitempty"`                                                                   // use in background to determine which plugins support which features (e.g. "transactions", "stored procedures", "json support")
+	Tags         []string          `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`                                                                                  // categories/statistics
+	License      string            `protobuf:"bytes,11,opt,name=license,proto3" json:"license,omitempty"`                                                                            // SPDX identifier or text
+	IconUrl      string            `protobuf:"bytes,12,opt,name=icon_url,json=iconUrl,proto3" json:"icon_url,omitempty"`                                                             // link to a small icon
+	Contact      string            `protobuf:"bytes,13,opt,name=contact,proto3" json:"contact,omitempty"`                                                                            // maintainer email/URL
+	// protocol identifies the output as a querybox plugin handshake.  The
+	// host only registers executables whose `info` output carries a
+	// recognised value; ServeCLI fills it in automatically.
+	Protocol string `protobuf:"bytes,14,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// example_queries are driver-appropriate snippets the host may offer to
+	// pre-populate the editor of a new connection, most useful first.
+	ExampleQueries []string `protobuf:"bytes,15,rep,name=example_queries,json=exampleQueries,proto3" json:"example_queries,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *PluginV1_InfoResponse) Reset() {
@@ -477,6 +554,20 @@ func (x *PluginV1_InfoResponse) GetContact() string {
 	return ""
 }
 
+func (x *PluginV1_InfoResponse) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *PluginV1_InfoResponse) GetExampleQueries() []string {
+	if x != nil {
+		return x.ExampleQueries
+	}
+	return nil
+}
+
 type PluginV1_ExecRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// connection is plugin-defined key/value (host, user, password, ...)
@@ -487,7 +578,18 @@ type PluginV1_ExecRequest struct {
 	// (`options["explain-query"] == "yes"`).  Plugins are free to define
 	// additional keys for their own features; forwards-compatible hosts will
 	// simply include them in the map and ignore unknown values.
-	Options       map[string]string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Options map[string]string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// optional pagination for grid views.  limit <= 0 means "no limit" and
+	// offset is only honoured together with a positive limit.  SQL plugins
+	// append `LIMIT/OFFSET` when the query has no LIMIT of its own (see
+	// plugin.ApplyLimitOffset); document stores map them to skip/limit.
+	Offset int64 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit  int64 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// keep_alive hints that the host will send further requests to the same
+	// plugin process (resident mode), so the plugin may keep its database
+	// handle open, keyed by plugin.ConnectionFingerprint, instead of
+	// reopening it per request.  One-shot CLI invocations leave it unset.
+	KeepAlive     bool `protobuf:"varint,6,opt,name=keep_alive,json=keepAlive,proto3" json:"keep_alive,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -543,15 +645,39 @@ func (x *PluginV1_ExecRequest) GetOptions() map[string]string {
 	return nil
 }
 
+func (x *PluginV1_ExecRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PluginV1_ExecRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PluginV1_ExecRequest) GetKeepAlive() bool {
+	if x != nil {
+		return x.KeepAlive
+	}
+	return false
+}
+
 // ExecResponse contains the output of an Exec call,
 // provide a typed, extensible envelope that can represent at least three
 // common data models (SQL, document/JSON, and simple key-value maps).  The
 // UI will examine the oneof field and render accordingly instead of relying
 // on plugin-specific semantics.
 type PluginV1_ExecResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        *PluginV1_ExecResult   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // optional error message
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result *PluginV1_ExecResult   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Error  string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // optional error message
+	// error_code optionally classifies `error` so the host can react to
+	// specific failures (e.g. prompt for credentials on AUTH_FAILED).
+	ErrorCode     PluginV1_ErrorCode `protobuf:"varint,3,opt,name=error_code,json=errorCode,proto3,enum=plugin.v1.PluginV1_ErrorCode" json:"error_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -600,6 +726,13 @@ func (x *PluginV1_ExecResponse) GetError() string {
 	return ""
 }
 
+func (x *PluginV1_ExecResponse) GetErrorCode() PluginV1_ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return PluginV1_ERROR_CODE_UNKNOWN
+}
+
 // ExecResult is a wrapper around the various result types supported by a
 // plugin.  Only one field will be populated.
 type PluginV1_ExecResult struct {
@@ -609,7 +742,16 @@ type PluginV1_ExecResult struct {
 	//	*PluginV1_ExecResult_Sql
 	//	*PluginV1_ExecResult_Document
 	//	*PluginV1_ExecResult_Kv
-	Payload       isPluginV1_ExecResult_Payload `protobuf_oneof:"payload"`
+	Payload isPluginV1_ExecResult_Payload `protobuf_oneof:"payload"`
+	// warnings carries non-fatal messages the server raised while running
+	// the statement (MySQL SHOW WARNINGS, PostgreSQL notices), one per
+	// entry, so the UI can flag results that may be incomplete or coerced.
+	Warnings []string `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// metadata carries driver-specific statistics about the statement
+	// (execution time, rows examined, index used, ...) as display strings,
+	// so plugins can report them without a dedicated payload type.  Keys
+	// are free-form; see pkg/plugin for the well-known ones.
+	Metadata      map[string]string `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -678,6 +820,20 @@ func (x *PluginV1_ExecResult) GetKv() *PluginV1_KeyValueResult {
 	return nil
 }
 
+func (x *PluginV1_ExecResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *PluginV1_ExecResult) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 type isPluginV1_ExecResult_Payload interface {
 	isPluginV1_ExecResult_Payload()
 }
@@ -760,9 +916,16 @@ func (x *PluginV1_Column) GetType() string {
 }
 
 type PluginV1_SqlResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Columns       []*PluginV1_Column     `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
-	Rows          []*PluginV1_Row        `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Columns []*PluginV1_Column     `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows    []*PluginV1_Row        `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	// rows_affected is set for statements that modify rows (INSERT, UPDATE,
+	// DELETE, ...) and carries the driver's affected-row count; such results
+	// have no columns.  Unset for queries that return rows.
+	RowsAffected *int64 `protobuf:"varint,3,opt,name=rows_affected,json=rowsAffected,proto3,oneof" json:"rows_affected,omitempty"`
+	// last_insert_id is the id generated by the statement when the driver
+	// reports one (MySQL, SQLite).  Unset otherwise.
+	LastInsertId  *int64 `protobuf:"varint,4,opt,name=last_insert_id,json=lastInsertId,proto3,oneof" json:"last_insert_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -811,6 +974,20 @@ func (x *PluginV1_SqlResult) GetRows() []*PluginV1_Row {
 	return nil
 }
 
+func (x *PluginV1_SqlResult) GetRowsAffected() int64 {
+	if x != nil && x.RowsAffected != nil {
+		return *x.RowsAffected
+	}
+	return 0
+}
+
+func (x *PluginV1_SqlResult) GetLastInsertId() int64 {
+	if x != nil && x.LastInsertId != nil {
+		return *x.LastInsertId
+	}
+	return 0
+}
+
 // DescribeSchemaRequest carries the connection information and optional
 // filters used when querying for object metadata.
 type PluginV1_DescribeSchemaRequest struct {
@@ -1225,18 +1402,94 @@ func (x *PluginV1_DocumentResult) GetDocuments() []*structpb.Struct {
 	return nil
 }
 
+// DocumentBatch is one frame of a streamed document exec, requested with
+// the exec option format=document-batches.  The plugin writes one batch
+// per line as it drains its cursor; the last frame has final set and, if
+// the scan failed part-way, carries the error.  Documents of earlier
+// frames remain valid after a failure.
+type PluginV1_DocumentBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Documents     []*structpb.Struct     `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	Final         bool                   `protobuf:"varint,2,opt,name=final,proto3" json:"final,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	ErrorCode     PluginV1_ErrorCode     `protobuf:"varint,4,opt,name=error_code,json=errorCode,proto3,enum=plugin.v1.PluginV1_ErrorCode" json:"error_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_DocumentBatch) Reset() {
+	*x = PluginV1_DocumentBatch{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_DocumentBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_DocumentBatch) ProtoMessage() {}
+
+func (x *PluginV1_DocumentBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_DocumentBatch.ProtoReflect.Descriptor instead.
+func (*PluginV1_DocumentBatch) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 14}
+}
+
+func (x *PluginV1_DocumentBatch) GetDocuments() []*structpb.Struct {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+func (x *PluginV1_DocumentBatch) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *PluginV1_DocumentBatch) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PluginV1_DocumentBatch) GetErrorCode() PluginV1_ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return PluginV1_ERROR_CODE_UNKNOWN
+}
+
 // KeyValueResult is a simple map of string→string appropriate for things like
 // Redis or other key/value stores where the “row” concept isn’t meaningful.
 type PluginV1_KeyValueResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          map[string]string      `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Data  map[string]string      `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// keys is the display order of data's entries (maps carry none).  Keys
+	// missing from it follow in sorted order; unknown keys are ignored.
+	Keys          []string `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginV1_KeyValueResult) Reset() {
 	*x = PluginV1_KeyValueResult{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[15]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1248,7 +1501,7 @@ func (x *PluginV1_KeyValueResult) String() string {
 func (*PluginV1_KeyValueResult) ProtoMessage() {}
 
 func (x *PluginV1_KeyValueResult) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[15]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1261,7 +1514,7 @@ func (x *PluginV1_KeyValueResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_KeyValueResult.ProtoReflect.Descriptor instead.
 func (*PluginV1_KeyValueResult) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 14}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 15}
 }
 
 func (x *PluginV1_KeyValueResult) GetData() map[string]string {
@@ -1271,6 +1524,13 @@ func (x *PluginV1_KeyValueResult) GetData() map[string]string {
 	return nil
 }
 
+func (x *PluginV1_KeyValueResult) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
 // AuthField represents a single input field for authentication (e.g. host, user, password).
 // The plugin defines the fields it needs for authentication and the core renders them accordingly.
 type PluginV1_AuthField struct {
@@ -1280,7 +1540,7 @@ type PluginV1_AuthField struct {
 	Label         string                       `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`                                            // human-friendly label
 	Value         string                       `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`                                            // default/value used when invoking plugin
 	Required      bool                         `protobuf:"varint,5,opt,name=required,proto3" json:"required,omitempty"`                                     // whether field is required
-	Options       []string                     `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty"`                                        // for select inputs
+	Options       []string                     `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty"`                                        // for select and multiselect inputs
 	Placeholder   string                       `protobuf:"bytes,7,opt,name=placeholder,proto3" json:"placeholder,omitempty"`                                // optional placeholder
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -1288,7 +1548,7 @@ type PluginV1_AuthField struct {
 
 func (x *PluginV1_AuthField) Reset() {
 	*x = PluginV1_AuthField{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[16]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1300,7 +1560,7 @@ func (x *PluginV1_AuthField) String() string {
 func (*PluginV1_AuthField) ProtoMessage() {}
 
 func (x *PluginV1_AuthField) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[16]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1313,7 +1573,7 @@ func (x *PluginV1_AuthField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_AuthField.ProtoReflect.Descriptor instead.
 func (*PluginV1_AuthField) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 15}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 16}
 }
 
 func (x *PluginV1_AuthField) GetType() PluginV1_AuthField_FieldType {
@@ -1379,7 +1639,7 @@ type PluginV1_AuthForm struct {
 
 func (x *PluginV1_AuthForm) Reset() {
 	*x = PluginV1_AuthForm{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[17]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1391,7 +1651,7 @@ func (x *PluginV1_AuthForm) String() string {
 func (*PluginV1_AuthForm) ProtoMessage() {}
 
 func (x *PluginV1_AuthForm) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[17]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1404,7 +1664,7 @@ func (x *PluginV1_AuthForm) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_AuthForm.ProtoReflect.Descriptor instead.
 func (*PluginV1_AuthForm) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 16}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 17}
 }
 
 func (x *PluginV1_AuthForm) GetKey() string {
@@ -1436,7 +1696,7 @@ type PluginV1_AuthFormsRequest struct {
 
 func (x *PluginV1_AuthFormsRequest) Reset() {
 	*x = PluginV1_AuthFormsRequest{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[18]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1448,7 +1708,7 @@ func (x *PluginV1_AuthFormsRequest) String() string {
 func (*PluginV1_AuthFormsRequest) ProtoMessage() {}
 
 func (x *PluginV1_AuthFormsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[18]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1461,7 +1721,7 @@ func (x *PluginV1_AuthFormsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_AuthFormsRequest.ProtoReflect.Descriptor instead.
 func (*PluginV1_AuthFormsRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 17}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 18}
 }
 
 type PluginV1_AuthFormsResponse struct {
@@ -1474,7 +1734,7 @@ type PluginV1_AuthFormsResponse struct {
 
 func (x *PluginV1_AuthFormsResponse) Reset() {
 	*x = PluginV1_AuthFormsResponse{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[19]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1486,7 +1746,7 @@ func (x *PluginV1_AuthFormsResponse) String() string {
 func (*PluginV1_AuthFormsResponse) ProtoMessage() {}
 
 func (x *PluginV1_AuthFormsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[19]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1499,7 +1759,7 @@ func (x *PluginV1_AuthFormsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_AuthFormsResponse.ProtoReflect.Descriptor instead.
 func (*PluginV1_AuthFormsResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 18}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 19}
 }
 
 func (x *PluginV1_AuthFormsResponse) GetForms() map[string]*PluginV1_AuthForm {
@@ -1512,15 +1772,19 @@ func (x *PluginV1_AuthFormsResponse) GetForms() map[string]*PluginV1_AuthForm {
 // ConnectionTreeRequest is sent when the frontend wants to browse a
 // connection.  The contents are purely driver-defined.
 type PluginV1_ConnectionTreeRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Connection    map[string]string      `protobuf:"bytes,1,rep,name=connection,proto3" json:"connection,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Connection map[string]string      `protobuf:"bytes,1,rep,name=connection,proto3" json:"connection,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// max_depth limits how many levels of nodes are returned: 1 returns only
+	// the top-level nodes, 2 those and their children, and so on.  Nodes at
+	// the last level are returned without children.  0 means no limit.
+	MaxDepth      int32 `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginV1_ConnectionTreeRequest) Reset() {
 	*x = PluginV1_ConnectionTreeRequest{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[20]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1532,7 +1796,7 @@ func (x *PluginV1_ConnectionTreeRequest) String() string {
 func (*PluginV1_ConnectionTreeRequest) ProtoMessage() {}
 
 func (x *PluginV1_ConnectionTreeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[20]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1545,7 +1809,7 @@ func (x *PluginV1_ConnectionTreeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_ConnectionTreeRequest.ProtoReflect.Descriptor instead.
 func (*PluginV1_ConnectionTreeRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 19}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 20}
 }
 
 func (x *PluginV1_ConnectionTreeRequest) GetConnection() map[string]string {
@@ -1555,6 +1819,13 @@ func (x *PluginV1_ConnectionTreeRequest) GetConnection() map[string]string {
 	return nil
 }
 
+func (x *PluginV1_ConnectionTreeRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
 type PluginV1_ConnectionTreeResponse struct {
 	state         protoimpl.MessageState         `protogen:"open.v1"`
 	Nodes         []*PluginV1_ConnectionTreeNode `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
@@ -1564,7 +1835,7 @@ type PluginV1_ConnectionTreeResponse struct {
 
 func (x *PluginV1_ConnectionTreeResponse) Reset() {
 	*x = PluginV1_ConnectionTreeResponse{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[21]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1576,7 +1847,7 @@ func (x *PluginV1_ConnectionTreeResponse) String() string {
 func (*PluginV1_ConnectionTreeResponse) ProtoMessage() {}
 
 func (x *PluginV1_ConnectionTreeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[21]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1589,7 +1860,7 @@ func (x *PluginV1_ConnectionTreeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_ConnectionTreeResponse.ProtoReflect.Descriptor instead.
 func (*PluginV1_ConnectionTreeResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 20}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 21}
 }
 
 func (x *PluginV1_ConnectionTreeResponse) GetNodes() []*PluginV1_ConnectionTreeNode {
@@ -1600,19 +1871,22 @@ func (x *PluginV1_ConnectionTreeResponse) GetNodes() []*PluginV1_ConnectionTreeN
 }
 
 type PluginV1_ConnectionTreeNode struct {
-	state         protoimpl.MessageState           `protogen:"open.v1"`
-	Key           string                           `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`     // unique within the returned tree
-	Label         string                           `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"` // user-visible text
-	Children      []*PluginV1_ConnectionTreeNode   `protobuf:"bytes,3,rep,name=children,proto3" json:"children,omitempty"`
-	Actions       []*PluginV1_ConnectionTreeAction `protobuf:"bytes,4,rep,name=actions,proto3" json:"actions,omitempty"`
-	NodeType      PluginV1_NodeType                `protobuf:"varint,5,opt,name=node_type,json=nodeType,proto3,enum=plugin.v1.PluginV1_NodeType" json:"node_type,omitempty"`
+	state    protoimpl.MessageState           `protogen:"open.v1"`
+	Key      string                           `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`     // unique within the returned tree
+	Label    string                           `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"` // user-visible text
+	Children []*PluginV1_ConnectionTreeNode   `protobuf:"bytes,3,rep,name=children,proto3" json:"children,omitempty"`
+	Actions  []*PluginV1_ConnectionTreeAction `protobuf:"bytes,4,rep,name=actions,proto3" json:"actions,omitempty"`
+	NodeType PluginV1_NodeType                `protobuf:"varint,5,opt,name=node_type,json=nodeType,proto3,enum=plugin.v1.PluginV1_NodeType" json:"node_type,omitempty"`
+	// expanded asks the host to show the node open when the tree is first
+	// loaded, e.g. the connection's default database or schema.
+	Expanded      bool `protobuf:"varint,6,opt,name=expanded,proto3" json:"expanded,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginV1_ConnectionTreeNode) Reset() {
 	*x = PluginV1_ConnectionTreeNode{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[22]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1624,7 +1898,7 @@ func (x *PluginV1_ConnectionTreeNode) String() string {
 func (*PluginV1_ConnectionTreeNode) ProtoMessage() {}
 
 func (x *PluginV1_ConnectionTreeNode) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[22]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1637,7 +1911,7 @@ func (x *PluginV1_ConnectionTreeNode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_ConnectionTreeNode.ProtoReflect.Descriptor instead.
 func (*PluginV1_ConnectionTreeNode) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 21}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 22}
 }
 
 func (x *PluginV1_ConnectionTreeNode) GetKey() string {
@@ -1675,6 +1949,13 @@ func (x *PluginV1_ConnectionTreeNode) GetNodeType() PluginV1_NodeType {
 	return PluginV1_NODE_TYPE_UNKNOWN
 }
 
+func (x *PluginV1_ConnectionTreeNode) GetExpanded() bool {
+	if x != nil {
+		return x.Expanded
+	}
+	return false
+}
+
 type PluginV1_ConnectionTreeAction struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	Type  string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`   // machine name (e.g. "select", "describe")
@@ -1683,15 +1964,18 @@ type PluginV1_ConnectionTreeAction struct {
 	// hidden suppresses the action from the context menu / action buttons.
 	// Use this for actions that should only fire on leaf-node click, not be
 	// explicitly listed as selectable options.
-	Hidden        bool `protobuf:"varint,4,opt,name=hidden,proto3" json:"hidden,omitempty"`
-	NewTab        bool `protobuf:"varint,5,opt,name=new_tab,json=newTab,proto3" json:"new_tab,omitempty"` // whether the core should open a new tab when this action is executed
+	Hidden bool `protobuf:"varint,4,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	NewTab bool `protobuf:"varint,5,opt,name=new_tab,json=newTab,proto3" json:"new_tab,omitempty"` // whether the core should open a new tab when this action is executed
+	// destructive marks actions that lose data (drop, flush, delete).  The
+	// host asks for confirmation before running them.
+	Destructive   bool `protobuf:"varint,6,opt,name=destructive,proto3" json:"destructive,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginV1_ConnectionTreeAction) Reset() {
 	*x = PluginV1_ConnectionTreeAction{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[23]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1703,7 +1987,7 @@ func (x *PluginV1_ConnectionTreeAction) String() string {
 func (*PluginV1_ConnectionTreeAction) ProtoMessage() {}
 
 func (x *PluginV1_ConnectionTreeAction) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[23]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1716,7 +2000,7 @@ func (x *PluginV1_ConnectionTreeAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_ConnectionTreeAction.ProtoReflect.Descriptor instead.
 func (*PluginV1_ConnectionTreeAction) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 22}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 23}
 }
 
 func (x *PluginV1_ConnectionTreeAction) GetType() string {
@@ -1754,18 +2038,30 @@ func (x *PluginV1_ConnectionTreeAction) GetNewTab() bool {
 	return false
 }
 
+func (x *PluginV1_ConnectionTreeAction) GetDestructive() bool {
+	if x != nil {
+		return x.Destructive
+	}
+	return false
+}
+
 // TestConnectionRequest carries the same credential map as ExecRequest so
 // plugins can reuse their existing connection-building logic.
 type PluginV1_TestConnectionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Connection    map[string]string      `protobuf:"bytes,1,rep,name=connection,proto3" json:"connection,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Connection map[string]string      `protobuf:"bytes,1,rep,name=connection,proto3" json:"connection,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// form optionally names the auth form (AuthForm.key) to test.  When set
+	// it overrides the form recorded in credential_blob so the host can probe
+	// several candidate forms for the same connection; empty keeps the blob's
+	// own form.
+	Form          string `protobuf:"bytes,2,opt,name=form,proto3" json:"form,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginV1_TestConnectionRequest) Reset() {
 	*x = PluginV1_TestConnectionRequest{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[24]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1777,7 +2073,7 @@ func (x *PluginV1_TestConnectionRequest) String() string {
 func (*PluginV1_TestConnectionRequest) ProtoMessage() {}
 
 func (x *PluginV1_TestConnectionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[24]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1790,7 +2086,7 @@ func (x *PluginV1_TestConnectionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_TestConnectionRequest.ProtoReflect.Descriptor instead.
 func (*PluginV1_TestConnectionRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 23}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 24}
 }
 
 func (x *PluginV1_TestConnectionRequest) GetConnection() map[string]string {
@@ -1800,20 +2096,33 @@ func (x *PluginV1_TestConnectionRequest) GetConnection() map[string]string {
 	return nil
 }
 
+func (x *PluginV1_TestConnectionRequest) GetForm() string {
+	if x != nil {
+		return x.Form
+	}
+	return ""
+}
+
 // TestConnectionResponse indicates whether the connection attempt succeeded.
 // ok=true means the plugin could open and ping the data store.
 // message carries a human-readable success or failure description.
 type PluginV1_TestConnectionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Ok      bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// latency_ms is the round-trip time of the ping, set on success by
+	// plugins that measure it.
+	LatencyMs *int64 `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs,proto3,oneof" json:"latency_ms,omitempty"`
+	// server_info carries best-effort details about the server, e.g.
+	// "version" and "edition".  Absent keys are simply unknown.
+	ServerInfo    map[string]string `protobuf:"bytes,4,rep,name=server_info,json=serverInfo,proto3" json:"server_info,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginV1_TestConnectionResponse) Reset() {
 	*x = PluginV1_TestConnectionResponse{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[25]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1825,7 +2134,7 @@ func (x *PluginV1_TestConnectionResponse) String() string {
 func (*PluginV1_TestConnectionResponse) ProtoMessage() {}
 
 func (x *PluginV1_TestConnectionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[25]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1838,7 +2147,7 @@ func (x *PluginV1_TestConnectionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_TestConnectionResponse.ProtoReflect.Descriptor instead.
 func (*PluginV1_TestConnectionResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 24}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 25}
 }
 
 func (x *PluginV1_TestConnectionResponse) GetOk() bool {
@@ -1855,6 +2164,20 @@ func (x *PluginV1_TestConnectionResponse) GetMessage() string {
 	return ""
 }
 
+func (x *PluginV1_TestConnectionResponse) GetLatencyMs() int64 {
+	if x != nil && x.LatencyMs != nil {
+		return *x.LatencyMs
+	}
+	return 0
+}
+
+func (x *PluginV1_TestConnectionResponse) GetServerInfo() map[string]string {
+	if x != nil {
+		return x.ServerInfo
+	}
+	return nil
+}
+
 // GetCompletionFieldsRequest asks the plugin for field names within a specific
 // database and collection, for use in the editor auto-completion feature.
 type PluginV1_GetCompletionFieldsRequest struct {
@@ -1868,7 +2191,7 @@ type PluginV1_GetCompletionFieldsRequest struct {
 
 func (x *PluginV1_GetCompletionFieldsRequest) Reset() {
 	*x = PluginV1_GetCompletionFieldsRequest{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[26]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1880,7 +2203,7 @@ func (x *PluginV1_GetCompletionFieldsRequest) String() string {
 func (*PluginV1_GetCompletionFieldsRequest) ProtoMessage() {}
 
 func (x *PluginV1_GetCompletionFieldsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[26]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1893,7 +2216,7 @@ func (x *PluginV1_GetCompletionFieldsRequest) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use PluginV1_GetCompletionFieldsRequest.ProtoReflect.Descriptor instead.
 func (*PluginV1_GetCompletionFieldsRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 25}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 26}
 }
 
 func (x *PluginV1_GetCompletionFieldsRequest) GetConnection() map[string]string {
@@ -1928,7 +2251,7 @@ type PluginV1_FieldInfo struct {
 
 func (x *PluginV1_FieldInfo) Reset() {
 	*x = PluginV1_FieldInfo{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[27]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1940,7 +2263,7 @@ func (x *PluginV1_FieldInfo) String() string {
 func (*PluginV1_FieldInfo) ProtoMessage() {}
 
 func (x *PluginV1_FieldInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[27]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1953,7 +2276,7 @@ func (x *PluginV1_FieldInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_FieldInfo.ProtoReflect.Descriptor instead.
 func (*PluginV1_FieldInfo) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 26}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 27}
 }
 
 func (x *PluginV1_FieldInfo) GetName() string {
@@ -1980,7 +2303,7 @@ type PluginV1_GetCompletionFieldsResponse struct {
 
 func (x *PluginV1_GetCompletionFieldsResponse) Reset() {
 	*x = PluginV1_GetCompletionFieldsResponse{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[28]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1992,7 +2315,7 @@ func (x *PluginV1_GetCompletionFieldsResponse) String() string {
 func (*PluginV1_GetCompletionFieldsResponse) ProtoMessage() {}
 
 func (x *PluginV1_GetCompletionFieldsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[28]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2005,7 +2328,7 @@ func (x *PluginV1_GetCompletionFieldsResponse) ProtoReflect() protoreflect.Messa
 
 // Deprecated: Use PluginV1_GetCompletionFieldsResponse.ProtoReflect.Descriptor instead.
 func (*PluginV1_GetCompletionFieldsResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 27}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 28}
 }
 
 func (x *PluginV1_GetCompletionFieldsResponse) GetFields() []*PluginV1_FieldInfo {
@@ -2029,7 +2352,7 @@ type PluginV1_MutateRowRequest struct {
 
 func (x *PluginV1_MutateRowRequest) Reset() {
 	*x = PluginV1_MutateRowRequest{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[29]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2041,7 +2364,7 @@ func (x *PluginV1_MutateRowRequest) String() string {
 func (*PluginV1_MutateRowRequest) ProtoMessage() {}
 
 func (x *PluginV1_MutateRowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[29]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2054,7 +2377,7 @@ func (x *PluginV1_MutateRowRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_MutateRowRequest.ProtoReflect.Descriptor instead.
 func (*PluginV1_MutateRowRequest) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 28}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 29}
 }
 
 func (x *PluginV1_MutateRowRequest) GetConnection() map[string]string {
@@ -2103,7 +2426,7 @@ type PluginV1_MutateRowResponse struct {
 
 func (x *PluginV1_MutateRowResponse) Reset() {
 	*x = PluginV1_MutateRowResponse{}
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[30]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2115,7 +2438,7 @@ func (x *PluginV1_MutateRowResponse) String() string {
 func (*PluginV1_MutateRowResponse) ProtoMessage() {}
 
 func (x *PluginV1_MutateRowResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[30]
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2128,7 +2451,7 @@ func (x *PluginV1_MutateRowResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginV1_MutateRowResponse.ProtoReflect.Descriptor instead.
 func (*PluginV1_MutateRowResponse) Descriptor() ([]byte, []int) {
-	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 29}
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 30}
 }
 
 func (x *PluginV1_MutateRowResponse) GetSuccess() bool {
@@ -2145,13 +2468,416 @@ func (x *PluginV1_MutateRowResponse) GetError() string {
 	return ""
 }
 
+// ValidateRequest carries the same connection map and query payload as
+// ExecRequest.  The plugin must not execute the query.
+type PluginV1_ValidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Connection    map[string]string      `protobuf:"bytes,1,rep,name=connection,proto3" json:"connection,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_ValidateRequest) Reset() {
+	*x = PluginV1_ValidateRequest{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_ValidateRequest) ProtoMessage() {}
+
+func (x *PluginV1_ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_ValidateRequest.ProtoReflect.Descriptor instead.
+func (*PluginV1_ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 31}
+}
+
+func (x *PluginV1_ValidateRequest) GetConnection() map[string]string {
+	if x != nil {
+		return x.Connection
+	}
+	return nil
+}
+
+func (x *PluginV1_ValidateRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+// ValidateResponse reports whether the query parsed/planned successfully.
+// message carries the parser/planner error when valid=false.  unsupported
+// is set when the plugin cannot validate queries; hosts should then treat
+// the query as neither valid nor invalid.
+type PluginV1_ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Unsupported   bool                   `protobuf:"varint,3,opt,name=unsupported,proto3" json:"unsupported,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_ValidateResponse) Reset() {
+	*x = PluginV1_ValidateResponse{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_ValidateResponse) ProtoMessage() {}
+
+func (x *PluginV1_ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_ValidateResponse.ProtoReflect.Descriptor instead.
+func (*PluginV1_ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 32}
+}
+
+func (x *PluginV1_ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *PluginV1_ValidateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PluginV1_ValidateResponse) GetUnsupported() bool {
+	if x != nil {
+		return x.Unsupported
+	}
+	return false
+}
+
+// GenerateDDLRequest identifies the object by the key of its connection
+// tree node (ConnectionTreeNode.key), e.g. "db.table" for MySQL or
+// "schema.table" for PostgreSQL.
+type PluginV1_GenerateDDLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Connection    map[string]string      `protobuf:"bytes,1,rep,name=connection,proto3" json:"connection,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	NodeKey       string                 `protobuf:"bytes,2,opt,name=node_key,json=nodeKey,proto3" json:"node_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_GenerateDDLRequest) Reset() {
+	*x = PluginV1_GenerateDDLRequest{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_GenerateDDLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_GenerateDDLRequest) ProtoMessage() {}
+
+func (x *PluginV1_GenerateDDLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_GenerateDDLRequest.ProtoReflect.Descriptor instead.
+func (*PluginV1_GenerateDDLRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 33}
+}
+
+func (x *PluginV1_GenerateDDLRequest) GetConnection() map[string]string {
+	if x != nil {
+		return x.Connection
+	}
+	return nil
+}
+
+func (x *PluginV1_GenerateDDLRequest) GetNodeKey() string {
+	if x != nil {
+		return x.NodeKey
+	}
+	return ""
+}
+
+// GenerateDDLResponse carries the DDL text.  error is set when the object
+// could not be described; unsupported is set when the plugin cannot
+// generate DDL at all.
+type PluginV1_GenerateDDLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ddl           string                 `protobuf:"bytes,1,opt,name=ddl,proto3" json:"ddl,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Unsupported   bool                   `protobuf:"varint,3,opt,name=unsupported,proto3" json:"unsupported,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_GenerateDDLResponse) Reset() {
+	*x = PluginV1_GenerateDDLResponse{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_GenerateDDLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_GenerateDDLResponse) ProtoMessage() {}
+
+func (x *PluginV1_GenerateDDLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_GenerateDDLResponse.ProtoReflect.Descriptor instead.
+func (*PluginV1_GenerateDDLResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 34}
+}
+
+func (x *PluginV1_GenerateDDLResponse) GetDdl() string {
+	if x != nil {
+		return x.Ddl
+	}
+	return ""
+}
+
+func (x *PluginV1_GenerateDDLResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PluginV1_GenerateDDLResponse) GetUnsupported() bool {
+	if x != nil {
+		return x.Unsupported
+	}
+	return false
+}
+
+type PluginV1_SettingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_SettingsRequest) Reset() {
+	*x = PluginV1_SettingsRequest{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_SettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_SettingsRequest) ProtoMessage() {}
+
+func (x *PluginV1_SettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_SettingsRequest.ProtoReflect.Descriptor instead.
+func (*PluginV1_SettingsRequest) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 35}
+}
+
+// Setting declares one configurable option.  The host sends the user's
+// value as ExecRequest.options["setting.<key>"]; when absent the plugin
+// applies default_value.
+type PluginV1_Setting struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Key           string                       `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`                                                // machine name (lower-case, no spaces)
+	Label         string                       `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`                                            // human-friendly label
+	Type          PluginV1_AuthField_FieldType `protobuf:"varint,3,opt,name=type,proto3,enum=plugin.v1.PluginV1_AuthField_FieldType" json:"type,omitempty"` // input to render (TEXT, NUMBER, CHECKBOX, SELECT)
+	DefaultValue  string                       `protobuf:"bytes,4,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+	Description   string                       `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"` // optional help text
+	Options       []string                     `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty"`         // for SELECT inputs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_Setting) Reset() {
+	*x = PluginV1_Setting{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_Setting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_Setting) ProtoMessage() {}
+
+func (x *PluginV1_Setting) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_Setting.ProtoReflect.Descriptor instead.
+func (*PluginV1_Setting) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 36}
+}
+
+func (x *PluginV1_Setting) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PluginV1_Setting) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *PluginV1_Setting) GetType() PluginV1_AuthField_FieldType {
+	if x != nil {
+		return x.Type
+	}
+	return PluginV1_AuthField_FIELD_UNKNOWN
+}
+
+func (x *PluginV1_Setting) GetDefaultValue() string {
+	if x != nil {
+		return x.DefaultValue
+	}
+	return ""
+}
+
+func (x *PluginV1_Setting) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PluginV1_Setting) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type PluginV1_SettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Settings      []*PluginV1_Setting    `protobuf:"bytes,1,rep,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginV1_SettingsResponse) Reset() {
+	*x = PluginV1_SettingsResponse{}
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginV1_SettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginV1_SettingsResponse) ProtoMessage() {}
+
+func (x *PluginV1_SettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_contracts_plugin_v1_plugin_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginV1_SettingsResponse.ProtoReflect.Descriptor instead.
+func (*PluginV1_SettingsResponse) Descriptor() ([]byte, []int) {
+	return file_contracts_plugin_v1_plugin_proto_rawDescGZIP(), []int{0, 37}
+}
+
+func (x *PluginV1_SettingsResponse) GetSettings() []*PluginV1_Setting {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
 var File_contracts_plugin_v1_plugin_proto protoreflect.FileDescriptor
 
 const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\n" +
-	" contracts/plugin/v1/plugin.proto\x12\tplugin.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xaa(\n" +
+	" contracts/plugin/v1/plugin.proto\x12\tplugin.v1\x1a\x1cgoogle/protobuf/struct.proto\"\xff8\n" +
 	"\bPluginV1\x1a\r\n" +
-	"\vInfoRequest\x1a\xcf\x04\n" +
+	"\vInfoRequest\x1a\x94\x05\n" +
 	"\fInfoResponse\x12,\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x18.plugin.v1.PluginV1.TypeR\x04type\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
@@ -2166,40 +2892,57 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	" \x03(\tR\x04tags\x12\x18\n" +
 	"\alicense\x18\v \x01(\tR\alicense\x12\x19\n" +
 	"\bicon_url\x18\f \x01(\tR\aiconUrl\x12\x18\n" +
-	"\acontact\x18\r \x01(\tR\acontact\x1a;\n" +
+	"\acontact\x18\r \x01(\tR\acontact\x12\x1a\n" +
+	"\bprotocol\x18\x0e \x01(\tR\bprotocol\x12'\n" +
+	"\x0fexample_queries\x18\x0f \x03(\tR\x0eexampleQueries\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
 	"\rSettingsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\xb7\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\x84\x03\n" +
 	"\vExecRequest\x12O\n" +
 	"\n" +
 	"connection\x18\x01 \x03(\v2/.plugin.v1.PluginV1.ExecRequest.ConnectionEntryR\n" +
 	"connection\x12\x14\n" +
 	"\x05query\x18\x02 \x01(\tR\x05query\x12F\n" +
-	"\aoptions\x18\x03 \x03(\v2,.plugin.v1.PluginV1.ExecRequest.OptionsEntryR\aoptions\x1a=\n" +
+	"\aoptions\x18\x03 \x03(\v2,.plugin.v1.PluginV1.ExecRequest.OptionsEntryR\aoptions\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x03R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x03R\x05limit\x12\x1d\n" +
+	"\n" +
+	"keep_alive\x18\x06 \x01(\bR\tkeepAlive\x1a=\n" +
 	"\x0fConnectionEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
 	"\fOptionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\\\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\x9a\x01\n" +
 	"\fExecResponse\x126\n" +
 	"\x06result\x18\x01 \x01(\v2\x1e.plugin.v1.PluginV1.ExecResultR\x06result\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\x1a\xc2\x01\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12<\n" +
+	"\n" +
+	"error_code\x18\x03 \x01(\x0e2\x1d.plugin.v1.PluginV1.ErrorCodeR\terrorCode\x1a\xe5\x02\n" +
 	"\n" +
 	"ExecResult\x121\n" +
 	"\x03sql\x18\x01 \x01(\v2\x1d.plugin.v1.PluginV1.SqlResultH\x00R\x03sql\x12@\n" +
 	"\bdocument\x18\x02 \x01(\v2\".plugin.v1.PluginV1.DocumentResultH\x00R\bdocument\x124\n" +
-	"\x02kv\x18\x03 \x01(\v2\".plugin.v1.PluginV1.KeyValueResultH\x00R\x02kvB\t\n" +
+	"\x02kv\x18\x03 \x01(\v2\".plugin.v1.PluginV1.KeyValueResultH\x00R\x02kv\x12\x1a\n" +
+	"\bwarnings\x18\x04 \x03(\tR\bwarnings\x12H\n" +
+	"\bmetadata\x18\x05 \x03(\v2,.plugin.v1.PluginV1.ExecResult.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
 	"\apayload\x1a0\n" +
 	"\x06Column\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
-	"\x04type\x18\x02 \x01(\tR\x04type\x1an\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x1a\xe8\x01\n" +
 	"\tSqlResult\x124\n" +
 	"\acolumns\x18\x01 \x03(\v2\x1a.plugin.v1.PluginV1.ColumnR\acolumns\x12+\n" +
-	"\x04rows\x18\x02 \x03(\v2\x17.plugin.v1.PluginV1.RowR\x04rows\x1a\xe3\x01\n" +
+	"\x04rows\x18\x02 \x03(\v2\x17.plugin.v1.PluginV1.RowR\x04rows\x12(\n" +
+	"\rrows_affected\x18\x03 \x01(\x03H\x00R\frowsAffected\x88\x01\x01\x12)\n" +
+	"\x0elast_insert_id\x18\x04 \x01(\x03H\x01R\flastInsertId\x88\x01\x01B\x10\n" +
+	"\x0e_rows_affectedB\x11\n" +
+	"\x0f_last_insert_id\x1a\xe3\x01\n" +
 	"\x15DescribeSchemaRequest\x12Y\n" +
 	"\n" +
 	"connection\x18\x01 \x03(\v29.plugin.v1.PluginV1.DescribeSchemaRequest.ConnectionEntryR\n" +
@@ -2231,12 +2974,19 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\x03Row\x12\x16\n" +
 	"\x06values\x18\x01 \x03(\tR\x06values\x1aG\n" +
 	"\x0eDocumentResult\x125\n" +
-	"\tdocuments\x18\x01 \x03(\v2\x17.google.protobuf.StructR\tdocuments\x1a\x8b\x01\n" +
+	"\tdocuments\x18\x01 \x03(\v2\x17.google.protobuf.StructR\tdocuments\x1a\xb0\x01\n" +
+	"\rDocumentBatch\x125\n" +
+	"\tdocuments\x18\x01 \x03(\v2\x17.google.protobuf.StructR\tdocuments\x12\x14\n" +
+	"\x05final\x18\x02 \x01(\bR\x05final\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12<\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\x0e2\x1d.plugin.v1.PluginV1.ErrorCodeR\terrorCode\x1a\x9f\x01\n" +
 	"\x0eKeyValueResult\x12@\n" +
-	"\x04data\x18\x01 \x03(\v2,.plugin.v1.PluginV1.KeyValueResult.DataEntryR\x04data\x1a7\n" +
+	"\x04data\x18\x01 \x03(\v2,.plugin.v1.PluginV1.KeyValueResult.DataEntryR\x04data\x12\x12\n" +
+	"\x04keys\x18\x02 \x03(\tR\x04keys\x1a7\n" +
 	"\tDataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\xcd\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\xde\x02\n" +
 	"\tAuthField\x12;\n" +
 	"\x04type\x18\x01 \x01(\x0e2'.plugin.v1.PluginV1.AuthField.FieldTypeR\x04type\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -2244,7 +2994,7 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\x05value\x18\x04 \x01(\tR\x05value\x12\x1a\n" +
 	"\brequired\x18\x05 \x01(\bR\brequired\x12\x18\n" +
 	"\aoptions\x18\x06 \x03(\tR\aoptions\x12 \n" +
-	"\vplaceholder\x18\a \x01(\tR\vplaceholder\"k\n" +
+	"\vplaceholder\x18\a \x01(\tR\vplaceholder\"|\n" +
 	"\tFieldType\x12\x11\n" +
 	"\rFIELD_UNKNOWN\x10\x00\x12\b\n" +
 	"\x04TEXT\x10\x01\x12\n" +
@@ -2254,7 +3004,8 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\bCHECKBOX\x10\x04\x12\n" +
 	"\n" +
 	"\x06SELECT\x10\x05\x12\r\n" +
-	"\tFILE_PATH\x10\x06\x1ag\n" +
+	"\tFILE_PATH\x10\x06\x12\x0f\n" +
+	"\vMULTISELECT\x10\a\x1ag\n" +
 	"\bAuthForm\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x125\n" +
@@ -2265,38 +3016,50 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\n" +
 	"FormsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
-	"\x05value\x18\x02 \x01(\v2\x1c.plugin.v1.PluginV1.AuthFormR\x05value:\x028\x01\x1a\xb1\x01\n" +
+	"\x05value\x18\x02 \x01(\v2\x1c.plugin.v1.PluginV1.AuthFormR\x05value:\x028\x01\x1a\xce\x01\n" +
 	"\x15ConnectionTreeRequest\x12Y\n" +
 	"\n" +
 	"connection\x18\x01 \x03(\v29.plugin.v1.PluginV1.ConnectionTreeRequest.ConnectionEntryR\n" +
-	"connection\x1a=\n" +
+	"connection\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\x1a=\n" +
 	"\x0fConnectionEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aV\n" +
 	"\x16ConnectionTreeResponse\x12<\n" +
-	"\x05nodes\x18\x01 \x03(\v2&.plugin.v1.PluginV1.ConnectionTreeNodeR\x05nodes\x1a\xff\x01\n" +
+	"\x05nodes\x18\x01 \x03(\v2&.plugin.v1.PluginV1.ConnectionTreeNodeR\x05nodes\x1a\x9b\x02\n" +
 	"\x12ConnectionTreeNode\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05label\x18\x02 \x01(\tR\x05label\x12B\n" +
 	"\bchildren\x18\x03 \x03(\v2&.plugin.v1.PluginV1.ConnectionTreeNodeR\bchildren\x12B\n" +
 	"\aactions\x18\x04 \x03(\v2(.plugin.v1.PluginV1.ConnectionTreeActionR\aactions\x129\n" +
-	"\tnode_type\x18\x05 \x01(\x0e2\x1c.plugin.v1.PluginV1.NodeTypeR\bnodeType\x1a\x87\x01\n" +
+	"\tnode_type\x18\x05 \x01(\x0e2\x1c.plugin.v1.PluginV1.NodeTypeR\bnodeType\x12\x1a\n" +
+	"\bexpanded\x18\x06 \x01(\bR\bexpanded\x1a\xa9\x01\n" +
 	"\x14ConnectionTreeAction\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x14\n" +
 	"\x05query\x18\x03 \x01(\tR\x05query\x12\x16\n" +
 	"\x06hidden\x18\x04 \x01(\bR\x06hidden\x12\x17\n" +
-	"\anew_tab\x18\x05 \x01(\bR\x06newTab\x1a\xb1\x01\n" +
+	"\anew_tab\x18\x05 \x01(\bR\x06newTab\x12 \n" +
+	"\vdestructive\x18\x06 \x01(\bR\vdestructive\x1a\xc5\x01\n" +
 	"\x15TestConnectionRequest\x12Y\n" +
 	"\n" +
 	"connection\x18\x01 \x03(\v29.plugin.v1.PluginV1.TestConnectionRequest.ConnectionEntryR\n" +
-	"connection\x1a=\n" +
+	"connection\x12\x12\n" +
+	"\x04form\x18\x02 \x01(\tR\x04form\x1a=\n" +
 	"\x0fConnectionEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aB\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a\x91\x02\n" +
 	"\x16TestConnectionResponse\x12\x0e\n" +
 	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x1a\xf7\x01\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\"\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x03H\x00R\tlatencyMs\x88\x01\x01\x12[\n" +
+	"\vserver_info\x18\x04 \x03(\v2:.plugin.v1.PluginV1.TestConnectionResponse.ServerInfoEntryR\n" +
+	"serverInfo\x1a=\n" +
+	"\x0fServerInfoEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\r\n" +
+	"\v_latency_ms\x1a\xf7\x01\n" +
 	"\x1aGetCompletionFieldsRequest\x12^\n" +
 	"\n" +
 	"connection\x18\x01 \x03(\v2>.plugin.v1.PluginV1.GetCompletionFieldsRequest.ConnectionEntryR\n" +
@@ -2338,11 +3101,53 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\x06DELETE\x10\x03\x1aC\n" +
 	"\x11MutateRowResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"\x1f\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x1a\xbb\x01\n" +
+	"\x0fValidateRequest\x12S\n" +
+	"\n" +
+	"connection\x18\x01 \x03(\v23.plugin.v1.PluginV1.ValidateRequest.ConnectionEntryR\n" +
+	"connection\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x1a=\n" +
+	"\x0fConnectionEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1ad\n" +
+	"\x10ValidateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12 \n" +
+	"\vunsupported\x18\x03 \x01(\bR\vunsupported\x1a\xc6\x01\n" +
+	"\x12GenerateDDLRequest\x12V\n" +
+	"\n" +
+	"connection\x18\x01 \x03(\v26.plugin.v1.PluginV1.GenerateDDLRequest.ConnectionEntryR\n" +
+	"connection\x12\x19\n" +
+	"\bnode_key\x18\x02 \x01(\tR\anodeKey\x1a=\n" +
+	"\x0fConnectionEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a_\n" +
+	"\x13GenerateDDLResponse\x12\x10\n" +
+	"\x03ddl\x18\x01 \x01(\tR\x03ddl\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12 \n" +
+	"\vunsupported\x18\x03 \x01(\bR\vunsupported\x1a\x11\n" +
+	"\x0fSettingsRequest\x1a\xcf\x01\n" +
+	"\aSetting\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12;\n" +
+	"\x04type\x18\x03 \x01(\x0e2'.plugin.v1.PluginV1.AuthField.FieldTypeR\x04type\x12#\n" +
+	"\rdefault_value\x18\x04 \x01(\tR\fdefaultValue\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x18\n" +
+	"\aoptions\x18\x06 \x03(\tR\aoptions\x1aK\n" +
+	"\x10SettingsResponse\x127\n" +
+	"\bsettings\x18\x01 \x03(\v2\x1b.plugin.v1.PluginV1.SettingR\bsettings\"\x1f\n" +
 	"\x04Type\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\n" +
 	"\n" +
-	"\x06DRIVER\x10\x01\"\xe6\x01\n" +
+	"\x06DRIVER\x10\x01\"\xcc\x01\n" +
+	"\tErrorCode\x12\x16\n" +
+	"\x12ERROR_CODE_UNKNOWN\x10\x00\x12\x1a\n" +
+	"\x16ERROR_CODE_AUTH_FAILED\x10\x01\x12\x16\n" +
+	"\x12ERROR_CODE_TIMEOUT\x10\x02\x12\x1b\n" +
+	"\x17ERROR_CODE_SYNTAX_ERROR\x10\x03\x12 \n" +
+	"\x1cERROR_CODE_CONNECTION_FAILED\x10\x04\x12\x18\n" +
+	"\x14ERROR_CODE_NOT_FOUND\x10\x05\x12\x1a\n" +
+	"\x16ERROR_CODE_UNSUPPORTED\x10\x06\"\xe6\x01\n" +
 	"\bNodeType\x12\x15\n" +
 	"\x11NODE_TYPE_UNKNOWN\x10\x00\x12\x16\n" +
 	"\x12NODE_TYPE_DATABASE\x10\x01\x12\x13\n" +
@@ -2353,7 +3158,7 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\x10NODE_TYPE_ACTION\x10\x06\x12\x18\n" +
 	"\x14NODE_TYPE_COLLECTION\x10\a\x12\x11\n" +
 	"\rNODE_TYPE_KEY\x10\b\x12\x13\n" +
-	"\x0fNODE_TYPE_GROUP\x10\t2\x8c\x06\n" +
+	"\x0fNODE_TYPE_GROUP\x10\t2\x9a\b\n" +
 	"\rPluginService\x12I\n" +
 	"\x04Info\x12\x1f.plugin.v1.PluginV1.InfoRequest\x1a .plugin.v1.PluginV1.InfoResponse\x12I\n" +
 	"\x04Exec\x12\x1f.plugin.v1.PluginV1.ExecRequest\x1a .plugin.v1.PluginV1.ExecResponse\x12X\n" +
@@ -2362,7 +3167,10 @@ const file_contracts_plugin_v1_plugin_proto_rawDesc = "" +
 	"\x0eDescribeSchema\x12).plugin.v1.PluginV1.DescribeSchemaRequest\x1a*.plugin.v1.PluginV1.DescribeSchemaResponse\x12g\n" +
 	"\x0eTestConnection\x12).plugin.v1.PluginV1.TestConnectionRequest\x1a*.plugin.v1.PluginV1.TestConnectionResponse\x12v\n" +
 	"\x13GetCompletionFields\x12..plugin.v1.PluginV1.GetCompletionFieldsRequest\x1a/.plugin.v1.PluginV1.GetCompletionFieldsResponse\x12X\n" +
-	"\tMutateRow\x12$.plugin.v1.PluginV1.MutateRowRequest\x1a%.plugin.v1.PluginV1.MutateRowResponseB\x14Z\x12plugin/v1;pluginpbb\x06proto3"
+	"\tMutateRow\x12$.plugin.v1.PluginV1.MutateRowRequest\x1a%.plugin.v1.PluginV1.MutateRowResponse\x12U\n" +
+	"\bValidate\x12#.plugin.v1.PluginV1.ValidateRequest\x1a$.plugin.v1.PluginV1.ValidateResponse\x12^\n" +
+	"\vGenerateDDL\x12&.plugin.v1.PluginV1.GenerateDDLRequest\x1a'.plugin.v1.PluginV1.GenerateDDLResponse\x12U\n" +
+	"\bSettings\x12#.plugin.v1.PluginV1.SettingsRequest\x1a$.plugin.v1.PluginV1.SettingsResponseB\x14Z\x12plugin/v1;pluginpbb\x06proto3"
 
 var (
 	file_contracts_plugin_v1_plugin_proto_rawDescOnce sync.Once
@@ -2376,112 +3184,140 @@ func file_contracts_plugin_v1_plugin_proto_rawDescGZIP() []byte {
 	return file_contracts_plugin_v1_plugin_proto_rawDescData
 }
 
-var file_contracts_plugin_v1_plugin_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_contracts_plugin_v1_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
+var file_contracts_plugin_v1_plugin_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_contracts_plugin_v1_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 55)
 var file_contracts_plugin_v1_plugin_proto_goTypes = []any{
 	(PluginV1_Type)(0),                           // 0: plugin.v1.PluginV1.Type
-	(PluginV1_NodeType)(0),                       // 1: plugin.v1.PluginV1.NodeType
-	(PluginV1_AuthField_FieldType)(0),            // 2: plugin.v1.PluginV1.AuthField.FieldType
-	(PluginV1_MutateRowRequest_OperationType)(0), // 3: plugin.v1.PluginV1.MutateRowRequest.OperationType
-	(*PluginV1)(nil),                             // 4: plugin.v1.PluginV1
-	(*PluginV1_InfoRequest)(nil),                 // 5: plugin.v1.PluginV1.InfoRequest
-	(*PluginV1_InfoResponse)(nil),                // 6: plugin.v1.PluginV1.InfoResponse
-	(*PluginV1_ExecRequest)(nil),                 // 7: plugin.v1.PluginV1.ExecRequest
-	(*PluginV1_ExecResponse)(nil),                // 8: plugin.v1.PluginV1.ExecResponse
-	(*PluginV1_ExecResult)(nil),                  // 9: plugin.v1.PluginV1.ExecResult
-	(*PluginV1_Column)(nil),                      // 10: plugin.v1.PluginV1.Column
-	(*PluginV1_SqlResult)(nil),                   // 11: plugin.v1.PluginV1.SqlResult
-	(*PluginV1_DescribeSchemaRequest)(nil),       // 12: plugin.v1.PluginV1.DescribeSchemaRequest
-	(*PluginV1_DescribeSchemaResponse)(nil),      // 13: plugin.v1.PluginV1.DescribeSchemaResponse
-	(*PluginV1_TableSchema)(nil),                 // 14: plugin.v1.PluginV1.TableSchema
-	(*PluginV1_ColumnSchema)(nil),                // 15: plugin.v1.PluginV1.ColumnSchema
-	(*PluginV1_IndexSchema)(nil),                 // 16: plugin.v1.PluginV1.IndexSchema
-	(*PluginV1_Row)(nil),                         // 17: plugin.v1.PluginV1.Row
-	(*PluginV1_DocumentResult)(nil),              // 18: plugin.v1.PluginV1.DocumentResult
-	(*PluginV1_KeyValueResult)(nil),              // 19: plugin.v1.PluginV1.KeyValueResult
-	(*PluginV1_AuthField)(nil),                   // 20: plugin.v1.PluginV1.AuthField
-	(*PluginV1_AuthForm)(nil),                    // 21: plugin.v1.PluginV1.AuthForm
-	(*PluginV1_AuthFormsRequest)(nil),            // 22: plugin.v1.PluginV1.AuthFormsRequest
-	(*PluginV1_AuthFormsResponse)(nil),           // 23: plugin.v1.PluginV1.AuthFormsResponse
-	(*PluginV1_ConnectionTreeRequest)(nil),       // 24: plugin.v1.PluginV1.ConnectionTreeRequest
-	(*PluginV1_ConnectionTreeResponse)(nil),      // 25: plugin.v1.PluginV1.ConnectionTreeResponse
-	(*PluginV1_ConnectionTreeNode)(nil),          // 26: plugin.v1.PluginV1.ConnectionTreeNode
-	(*PluginV1_ConnectionTreeAction)(nil),        // 27: plugin.v1.PluginV1.ConnectionTreeAction
-	(*PluginV1_TestConnectionRequest)(nil),       // 28: plugin.v1.PluginV1.TestConnectionRequest
-	(*PluginV1_TestConnectionResponse)(nil),      // 29: plugin.v1.PluginV1.TestConnectionResponse
-	(*PluginV1_GetCompletionFieldsRequest)(nil),  // 30: plugin.v1.PluginV1.GetCompletionFieldsRequest
-	(*PluginV1_FieldInfo)(nil),                   // 31: plugin.v1.PluginV1.FieldInfo
-	(*PluginV1_GetCompletionFieldsResponse)(nil), // 32: plugin.v1.PluginV1.GetCompletionFieldsResponse
-	(*PluginV1_MutateRowRequest)(nil),            // 33: plugin.v1.PluginV1.MutateRowRequest
-	(*PluginV1_MutateRowResponse)(nil),           // 34: plugin.v1.PluginV1.MutateRowResponse
-	nil,                                          // 35: plugin.v1.PluginV1.InfoResponse.MetadataEntry
-	nil,                                          // 36: plugin.v1.PluginV1.InfoResponse.SettingsEntry
-	nil,                                          // 37: plugin.v1.PluginV1.ExecRequest.ConnectionEntry
-	nil,                                          // 38: plugin.v1.PluginV1.ExecRequest.OptionsEntry
-	nil,                                          // 39: plugin.v1.PluginV1.DescribeSchemaRequest.ConnectionEntry
-	nil,                                          // 40: plugin.v1.PluginV1.KeyValueResult.DataEntry
-	nil,                                          // 41: plugin.v1.PluginV1.AuthFormsResponse.FormsEntry
-	nil,                                          // 42: plugin.v1.PluginV1.ConnectionTreeRequest.ConnectionEntry
-	nil,                                          // 43: plugin.v1.PluginV1.TestConnectionRequest.ConnectionEntry
-	nil,                                          // 44: plugin.v1.PluginV1.GetCompletionFieldsRequest.ConnectionEntry
-	nil,                                          // 45: plugin.v1.PluginV1.MutateRowRequest.ConnectionEntry
-	nil,                                          // 46: plugin.v1.PluginV1.MutateRowRequest.ValuesEntry
-	(*structpb.Struct)(nil),                      // 47: google.protobuf.Struct
+	(PluginV1_ErrorCode)(0),                      // 1: plugin.v1.PluginV1.ErrorCode
+	(PluginV1_NodeType)(0),                       // 2: plugin.v1.PluginV1.NodeType
+	(PluginV1_AuthField_FieldType)(0),            // 3: plugin.v1.PluginV1.AuthField.FieldType
+	(PluginV1_MutateRowRequest_OperationType)(0), // 4: plugin.v1.PluginV1.MutateRowRequest.OperationType
+	(*PluginV1)(nil),                             // 5: plugin.v1.PluginV1
+	(*PluginV1_InfoRequest)(nil),                 // 6: plugin.v1.PluginV1.InfoRequest
+	(*PluginV1_InfoResponse)(nil),                // 7: plugin.v1.PluginV1.InfoResponse
+	(*PluginV1_ExecRequest)(nil),                 // 8: plugin.v1.PluginV1.ExecRequest
+	(*PluginV1_ExecResponse)(nil),                // 9: plugin.v1.PluginV1.ExecResponse
+	(*PluginV1_ExecResult)(nil),                  // 10: plugin.v1.PluginV1.ExecResult
+	(*PluginV1_Column)(nil),                      // 11: plugin.v1.PluginV1.Column
+	(*PluginV1_SqlResult)(nil),                   // 12: plugin.v1.PluginV1.SqlResult
+	(*PluginV1_DescribeSchemaRequest)(nil),       // 13: plugin.v1.PluginV1.DescribeSchemaRequest
+	(*PluginV1_DescribeSchemaResponse)(nil),      // 14: plugin.v1.PluginV1.DescribeSchemaResponse
+	(*PluginV1_TableSchema)(nil),                 // 15: plugin.v1.PluginV1.TableSchema
+	(*PluginV1_ColumnSchema)(nil),                // 16: plugin.v1.PluginV1.ColumnSchema
+	(*PluginV1_IndexSchema)(nil),                 // 17: plugin.v1.PluginV1.IndexSchema
+	(*PluginV1_Row)(nil),                         // 18: plugin.v1.PluginV1.Row
+	(*PluginV1_DocumentResult)(nil),              // 19: plugin.v1.PluginV1.DocumentResult
+	(*PluginV1_DocumentBatch)(nil),               // 20: plugin.v1.PluginV1.DocumentBatch
+	(*PluginV1_KeyValueResult)(nil),              // 21: plugin.v1.PluginV1.KeyValueResult
+	(*PluginV1_AuthField)(nil),                   // 22: plugin.v1.PluginV1.AuthField
+	(*PluginV1_AuthForm)(nil),                    // 23: plugin.v1.PluginV1.AuthForm
+	(*PluginV1_AuthFormsRequest)(nil),            // 24: plugin.v1.PluginV1.AuthFormsRequest
+	(*PluginV1_AuthFormsResponse)(nil),           // 25: plugin.v1.PluginV1.AuthFormsResponse
+	(*PluginV1_ConnectionTreeRequest)(nil),       // 26: plugin.v1.PluginV1.ConnectionTreeRequest
+	(*PluginV1_ConnectionTreeResponse)(nil),      // 27: plugin.v1.PluginV1.ConnectionTreeResponse
+	(*PluginV1_ConnectionTreeNode)(nil),          // 28: plugin.v1.PluginV1.ConnectionTreeNode
+	(*PluginV1_ConnectionTreeAction)(nil),        // 29: plugin.v1.PluginV1.ConnectionTreeAction
+	(*PluginV1_TestConnectionRequest)(nil),       // 30: plugin.v1.PluginV1.TestConnectionRequest
+	(*PluginV1_TestConnectionResponse)(nil),      // 31: plugin.v1.PluginV1.TestConnectionResponse
+	(*PluginV1_GetCompletionFieldsRequest)(nil),  // 32: plugin.v1.PluginV1.GetCompletionFieldsRequest
+	(*PluginV1_FieldInfo)(nil),                   // 33: plugin.v1.PluginV1.FieldInfo
+	(*PluginV1_GetCompletionFieldsResponse)(nil), // 34: plugin.v1.PluginV1.GetCompletionFieldsResponse
+	(*PluginV1_MutateRowRequest)(nil),            // 35: plugin.v1.PluginV1.MutateRowRequest
+	(*PluginV1_MutateRowResponse)(nil),           // 36: plugin.v1.PluginV1.MutateRowResponse
+	(*PluginV1_ValidateRequest)(nil),             // 37: plugin.v1.PluginV1.ValidateRequest
+	(*PluginV1_ValidateResponse)(nil),            // 38: plugin.v1.PluginV1.ValidateResponse
+	(*PluginV1_GenerateDDLRequest)(nil),          // 39: plugin.v1.PluginV1.GenerateDDLRequest
+	(*PluginV1_GenerateDDLResponse)(nil),         // 40: plugin.v1.PluginV1.GenerateDDLResponse
+	(*PluginV1_SettingsRequest)(nil),             // 41: plugin.v1.PluginV1.SettingsRequest
+	(*PluginV1_Setting)(nil),                     // 42: plugin.v1.PluginV1.Setting
+	(*PluginV1_SettingsResponse)(nil),            // 43: plugin.v1.PluginV1.SettingsResponse
+	nil,                                          // 44: plugin.v1.PluginV1.InfoResponse.MetadataEntry
+	nil,                                          // 45: plugin.v1.PluginV1.InfoResponse.SettingsEntry
+	nil,                                          // 46: plugin.v1.PluginV1.ExecRequest.ConnectionEntry
+	nil,                                          // 47: plugin.v1.PluginV1.ExecRequest.OptionsEntry
+	nil,                                          // 48: plugin.v1.PluginV1.ExecResult.MetadataEntry
+	nil,                                          // 49: plugin.v1.PluginV1.DescribeSchemaRequest.ConnectionEntry
+	nil,                                          // 50: plugin.v1.PluginV1.KeyValueResult.DataEntry
+	nil,                                          // 51: plugin.v1.PluginV1.AuthFormsResponse.FormsEntry
+	nil,                                          // 52: plugin.v1.PluginV1.ConnectionTreeRequest.ConnectionEntry
+	nil,                                          // 53: plugin.v1.PluginV1.TestConnectionRequest.ConnectionEntry
+	nil,                                          // 54: plugin.v1.PluginV1.TestConnectionResponse.ServerInfoEntry
+	nil,                                          // 55: plugin.v1.PluginV1.GetCompletionFieldsRequest.ConnectionEntry
+	nil,                                          // 56: plugin.v1.PluginV1.MutateRowRequest.ConnectionEntry
+	nil,                                          // 57: plugin.v1.PluginV1.MutateRowRequest.ValuesEntry
+	nil,                                          // 58: plugin.v1.PluginV1.ValidateRequest.ConnectionEntry
+	nil,                                          // 59: plugin.v1.PluginV1.GenerateDDLRequest.ConnectionEntry
+	(*structpb.Struct)(nil),                      // 60: google.protobuf.Struct
 }
 var file_contracts_plugin_v1_plugin_proto_depIdxs = []int32{
 	0,  // 0: plugin.v1.PluginV1.InfoResponse.type:type_name -> plugin.v1.PluginV1.Type
-	35, // 1: plugin.v1.PluginV1.InfoResponse.metadata:type_name -> plugin.v1.PluginV1.InfoResponse.MetadataEntry
-	36, // 2: plugin.v1.PluginV1.InfoResponse.settings:type_name -> plugin.v1.PluginV1.InfoResponse.SettingsEntry
-	37, // 3: plugin.v1.PluginV1.ExecRequest.connection:type_name -> plugin.v1.PluginV1.ExecRequest.ConnectionEntry
-	38, // 4: plugin.v1.PluginV1.ExecRequest.options:type_name -> plugin.v1.PluginV1.ExecRequest.OptionsEntry
-	9,  // 5: plugin.v1.PluginV1.ExecResponse.result:type_name -> plugin.v1.PluginV1.ExecResult
-	11, // 6: plugin.v1.PluginV1.ExecResult.sql:type_name -> plugin.v1.PluginV1.SqlResult
-	18, // 7: plugin.v1.PluginV1.ExecResult.document:type_name -> plugin.v1.PluginV1.DocumentResult
-	19, // 8: plugin.v1.PluginV1.ExecResult.kv:type_name -> plugin.v1.PluginV1.KeyValueResult
-	10, // 9: plugin.v1.PluginV1.SqlResult.columns:type_name -> plugin.v1.PluginV1.Column
-	17, // 10: plugin.v1.PluginV1.SqlResult.rows:type_name -> plugin.v1.PluginV1.Row
-	39, // 11: plugin.v1.PluginV1.DescribeSchemaRequest.connection:type_name -> plugin.v1.PluginV1.DescribeSchemaRequest.ConnectionEntry
-	14, // 12: plugin.v1.PluginV1.DescribeSchemaResponse.tables:type_name -> plugin.v1.PluginV1.TableSchema
-	15, // 13: plugin.v1.PluginV1.TableSchema.columns:type_name -> plugin.v1.PluginV1.ColumnSchema
-	16, // 14: plugin.v1.PluginV1.TableSchema.indexes:type_name -> plugin.v1.PluginV1.IndexSchema
-	47, // 15: plugin.v1.PluginV1.DocumentResult.documents:type_name -> google.protobuf.Struct
-	40, // 16: plugin.v1.PluginV1.KeyValueResult.data:type_name -> plugin.v1.PluginV1.KeyValueResult.DataEntry
-	2,  // 17: plugin.v1.PluginV1.AuthField.type:type_name -> plugin.v1.PluginV1.AuthField.FieldType
-	20, // 18: plugin.v1.PluginV1.AuthForm.fields:type_name -> plugin.v1.PluginV1.AuthField
-	41, // 19: plugin.v1.PluginV1.AuthFormsResponse.forms:type_name -> plugin.v1.PluginV1.AuthFormsResponse.FormsEntry
-	42, // 20: plugin.v1.PluginV1.ConnectionTreeRequest.connection:type_name -> plugin.v1.PluginV1.ConnectionTreeRequest.ConnectionEntry
-	26, // 21: plugin.v1.PluginV1.ConnectionTreeResponse.nodes:type_name -> plugin.v1.PluginV1.ConnectionTreeNode
-	26, // 22: plugin.v1.PluginV1.ConnectionTreeNode.children:type_name -> plugin.v1.PluginV1.ConnectionTreeNode
-	27, // 23: plugin.v1.PluginV1.ConnectionTreeNode.actions:type_name -> plugin.v1.PluginV1.ConnectionTreeAction
-	1,  // 24: plugin.v1.PluginV1.ConnectionTreeNode.node_type:type_name -> plugin.v1.PluginV1.NodeType
-	43, // 25: plugin.v1.PluginV1.TestConnectionRequest.connection:type_name -> plugin.v1.PluginV1.TestConnectionRequest.ConnectionEntry
-	44, // 26: plugin.v1.PluginV1.GetCompletionFieldsRequest.connection:type_name -> plugin.v1.PluginV1.GetCompletionFieldsRequest.ConnectionEntry
-	31, // 27: plugin.v1.PluginV1.GetCompletionFieldsResponse.fields:type_name -> plugin.v1.PluginV1.FieldInfo
-	45, // 28: plugin.v1.PluginV1.MutateRowRequest.connection:type_name -> plugin.v1.PluginV1.MutateRowRequest.ConnectionEntry
-	3,  // 29: plugin.v1.PluginV1.MutateRowRequest.operation:type_name -> plugin.v1.PluginV1.MutateRowRequest.OperationType
-	46, // 30: plugin.v1.PluginV1.MutateRowRequest.values:type_name -> plugin.v1.PluginV1.MutateRowRequest.ValuesEntry
-	21, // 31: plugin.v1.PluginV1.AuthFormsResponse.FormsEntry.value:type_name -> plugin.v1.PluginV1.AuthForm
-	5,  // 32: plugin.v1.PluginService.Info:input_type -> plugin.v1.PluginV1.InfoRequest
-	7,  // 33: plugin.v1.PluginService.Exec:input_type -> plugin.v1.PluginV1.ExecRequest
-	22, // 34: plugin.v1.PluginService.AuthForms:input_type -> plugin.v1.PluginV1.AuthFormsRequest
-	24, // 35: plugin.v1.PluginService.ConnectionTree:input_type -> plugin.v1.PluginV1.ConnectionTreeRequest
-	12, // 36: plugin.v1.PluginService.DescribeSchema:input_type -> plugin.v1.PluginV1.DescribeSchemaRequest
-	28, // 37: plugin.v1.PluginService.TestConnection:input_type -> plugin.v1.PluginV1.TestConnectionRequest
-	30, // 38: plugin.v1.PluginService.GetCompletionFields:input_type -> plugin.v1.PluginV1.GetCompletionFieldsRequest
-	33, // 39: plugin.v1.PluginService.MutateRow:input_type -> plugin.v1.PluginV1.MutateRowRequest
-	6,  // 40: plugin.v1.PluginService.Info:output_type -> plugin.v1.PluginV1.InfoResponse
-	8,  // 41: plugin.v1.PluginService.Exec:output_type -> plugin.v1.PluginV1.ExecResponse
-	23, // 42: plugin.v1.PluginService.AuthForms:output_type -> plugin.v1.PluginV1.AuthFormsResponse
-	25, // 43: plugin.v1.PluginService.ConnectionTree:output_type -> plugin.v1.PluginV1.ConnectionTreeResponse
-	13, // 44: plugin.v1.PluginService.DescribeSchema:output_type -> plugin.v1.PluginV1.DescribeSchemaResponse
-	29, // 45: plugin.v1.PluginService.TestConnection:output_type -> plugin.v1.PluginV1.TestConnectionResponse
-	32, // 46: plugin.v1.PluginService.GetCompletionFields:output_type -> plugin.v1.PluginV1.GetCompletionFieldsResponse
-	34, // 47: plugin.v1.PluginService.MutateRow:output_type -> plugin.v1.PluginV1.MutateRowResponse
-	40, // [40:48] is the sub-list for method output_type
-	32, // [32:40] is the sub-list for method input_type
-	32, // [32:32] is the sub-list for extension type_name
-	32, // [32:32] is the sub-list for extension extendee
-	0,  // [0:32] is the sub-list for field type_name
+	44, // 1: plugin.v1.PluginV1.InfoResponse.metadata:type_name -> plugin.v1.PluginV1.InfoResponse.MetadataEntry
+	45, // 2: plugin.v1.PluginV1.InfoResponse.settings:type_name -> plugin.v1.PluginV1.InfoResponse.SettingsEntry
+	46, // 3: plugin.v1.PluginV1.ExecRequest.connection:type_name -> plugin.v1.PluginV1.ExecRequest.ConnectionEntry
+	47, // 4: plugin.v1.PluginV1.ExecRequest.options:type_name -> plugin.v1.PluginV1.ExecRequest.OptionsEntry
+	10, // 5: plugin.v1.PluginV1.ExecResponse.result:type_name -> plugin.v1.PluginV1.ExecResult
+	1,  // 6: plugin.v1.PluginV1.ExecResponse.error_code:type_name -> plugin.v1.PluginV1.ErrorCode
+	12, // 7: plugin.v1.PluginV1.ExecResult.sql:type_name -> plugin.v1.PluginV1.SqlResult
+	19, // 8: plugin.v1.PluginV1.ExecResult.document:type_name -> plugin.v1.PluginV1.DocumentResult
+	21, // 9: plugin.v1.PluginV1.ExecResult.kv:type_name -> plugin.v1.PluginV1.KeyValueResult
+	48, // 10: plugin.v1.PluginV1.ExecResult.metadata:type_name -> plugin.v1.PluginV1.ExecResult.MetadataEntry
+	11, // 11: plugin.v1.PluginV1.SqlResult.columns:type_name -> plugin.v1.PluginV1.Column
+	18, // 12: plugin.v1.PluginV1.SqlResult.rows:type_name -> plugin.v1.PluginV1.Row
+	49, // 13: plugin.v1.PluginV1.DescribeSchemaRequest.connection:type_name -> plugin.v1.PluginV1.DescribeSchemaRequest.ConnectionEntry
+	15, // 14: plugin.v1.PluginV1.DescribeSchemaResponse.tables:type_name -> plugin.v1.PluginV1.TableSchema
+	16, // 15: plugin.v1.PluginV1.TableSchema.columns:type_name -> plugin.v1.PluginV1.ColumnSchema
+	17, // 16: plugin.v1.PluginV1.TableSchema.indexes:type_name -> plugin.v1.PluginV1.IndexSchema
+	60, // 17: plugin.v1.PluginV1.DocumentResult.documents:type_name -> google.protobuf.Struct
+	60, // 18: plugin.v1.PluginV1.DocumentBatch.documents:type_name -> google.protobuf.Struct
+	1,  // 19: plugin.v1.PluginV1.DocumentBatch.error_code:type_name -> plugin.v1.PluginV1.ErrorCode
+	50, // 20: plugin.v1.PluginV1.KeyValueResult.data:type_name -> plugin.v1.PluginV1.KeyValueResult.DataEntry
+	3,  // 21: plugin.v1.PluginV1.AuthField.type:type_name -> plugin.v1.PluginV1.AuthField.FieldType
+	22, // 22: plugin.v1.PluginV1.AuthForm.fields:type_name -> plugin.v1.PluginV1.AuthField
+	51, // 23: plugin.v1.PluginV1.AuthFormsResponse.forms:type_name -> plugin.v1.PluginV1.AuthFormsResponse.FormsEntry
+	52, // 24: plugin.v1.PluginV1.ConnectionTreeRequest.connection:type_name -> plugin.v1.PluginV1.ConnectionTreeRequest.ConnectionEntry
+	28, // 25: plugin.v1.PluginV1.ConnectionTreeResponse.nodes:type_name -> plugin.v1.PluginV1.ConnectionTreeNode
+	28, // 26: plugin.v1.PluginV1.ConnectionTreeNode.children:type_name -> plugin.v1.PluginV1.ConnectionTreeNode
+	29, // 27: plugin.v1.PluginV1.ConnectionTreeNode.actions:type_name -> plugin.v1.PluginV1.ConnectionTreeAction
+	2,  // 28: plugin.v1.PluginV1.ConnectionTreeNode.node_type:type_name -> plugin.v1.PluginV1.NodeType
+	53, // 29: plugin.v1.PluginV1.TestConnectionRequest.connection:type_name -> plugin.v1.PluginV1.TestConnectionRequest.ConnectionEntry
+	54, // 30: plugin.v1.PluginV1.TestConnectionResponse.server_info:type_name -> plugin.v1.PluginV1.TestConnectionResponse.ServerInfoEntry
+	55, // 31: plugin.v1.PluginV1.GetCompletionFieldsRequest.connection:type_name -> plugin.v1.PluginV1.GetCompletionFieldsRequest.ConnectionEntry
+	33, // 32: plugin.v1.PluginV1.GetCompletionFieldsResponse.fields:type_name -> plugin.v1.PluginV1.FieldInfo
+	56, // 33: plugin.v1.PluginV1.MutateRowRequest.connection:type_name -> plugin.v1.PluginV1.MutateRowRequest.ConnectionEntry
+	4,  // 34: plugin.v1.PluginV1.MutateRowRequest.operation:type_name -> plugin.v1.PluginV1.MutateRowRequest.OperationType
+	57, // 35: plugin.v1.PluginV1.MutateRowRequest.values:type_name -> plugin.v1.PluginV1.MutateRowRequest.ValuesEntry
+	58, // 36: plugin.v1.PluginV1.ValidateRequest.connection:type_name -> plugin.v1.PluginV1.ValidateRequest.ConnectionEntry
+	59, // 37: plugin.v1.PluginV1.GenerateDDLRequest.connection:type_name -> plugin.v1.PluginV1.GenerateDDLRequest.ConnectionEntry
+	3,  // 38: plugin.v1.PluginV1.Setting.type:type_name -> plugin.v1.PluginV1.AuthField.FieldType
+	42, // 39: plugin.v1.PluginV1.SettingsResponse.settings:type_name -> plugin.v1.PluginV1.Setting
+	23, // 40: plugin.v1.PluginV1.AuthFormsResponse.FormsEntry.value:type_name -> plugin.v1.PluginV1.AuthForm
+	6,  // 41: plugin.v1.PluginService.Info:input_type -> plugin.v1.PluginV1.InfoRequest
+	8,  // 42: plugin.v1.PluginService.Exec:input_type -> plugin.v1.PluginV1.ExecRequest
+	24, // 43: plugin.v1.PluginService.AuthForms:input_type -> plugin.v1.PluginV1.AuthFormsRequest
+	26, // 44: plugin.v1.PluginService.ConnectionTree:input_type -> plugin.v1.PluginV1.ConnectionTreeRequest
+	13, // 45: plugin.v1.PluginService.DescribeSchema:input_type -> plugin.v1.PluginV1.DescribeSchemaRequest
+	30, // 46: plugin.v1.PluginService.TestConnection:input_type -> plugin.v1.PluginV1.TestConnectionRequest
+	32, // 47: plugin.v1.PluginService.GetCompletionFields:input_type -> plugin.v1.PluginV1.GetCompletionFieldsRequest
+	35, // 48: plugin.v1.PluginService.MutateRow:input_type -> plugin.v1.PluginV1.MutateRowRequest
+	37, // 49: plugin.v1.PluginService.Validate:input_type -> plugin.v1.PluginV1.ValidateRequest
+	39, // 50: plugin.v1.PluginService.GenerateDDL:input_type -> plugin.v1.PluginV1.GenerateDDLRequest
+	41, // 51: plugin.v1.PluginService.Settings:input_type -> plugin.v1.PluginV1.SettingsRequest
+	7,  // 52: plugin.v1.PluginService.Info:output_type -> plugin.v1.PluginV1.InfoResponse
+	9,  // 53: plugin.v1.PluginService.Exec:output_type -> plugin.v1.PluginV1.ExecResponse
+	25, // 54: plugin.v1.PluginService.AuthForms:output_type -> plugin.v1.PluginV1.AuthFormsResponse
+	27, // 55: plugin.v1.PluginService.ConnectionTree:output_type -> plugin.v1.PluginV1.ConnectionTreeResponse
+	14, // 56: plugin.v1.PluginService.DescribeSchema:output_type -> plugin.v1.PluginV1.DescribeSchemaResponse
+	31, // 57: plugin.v1.PluginService.TestConnection:output_type -> plugin.v1.PluginV1.TestConnectionResponse
+	34, // 58: plugin.v1.PluginService.GetCompletionFields:output_type -> plugin.v1.PluginV1.GetCompletionFieldsResponse
+	36, // 59: plugin.v1.PluginService.MutateRow:output_type -> plugin.v1.PluginV1.MutateRowResponse
+	38, // 60: plugin.v1.PluginService.Validate:output_type -> plugin.v1.PluginV1.ValidateResponse
+	40, // 61: plugin.v1.PluginService.GenerateDDL:output_type -> plugin.v1.PluginV1.GenerateDDLResponse
+	43, // 62: plugin.v1.PluginService.Settings:output_type -> plugin.v1.PluginV1.SettingsResponse
+	52, // [52:63] is the sub-list for method output_type
+	41, // [41:52] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_contracts_plugin_v1_plugin_proto_init() }
@@ -2494,13 +3330,15 @@ func file_contracts_plugin_v1_plugin_proto_init() {
 		(*PluginV1_ExecResult_Document)(nil),
 		(*PluginV1_ExecResult_Kv)(nil),
 	}
+	file_contracts_plugin_v1_plugin_proto_msgTypes[7].OneofWrappers = []any{}
+	file_contracts_plugin_v1_plugin_proto_msgTypes[26].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_contracts_plugin_v1_plugin_proto_rawDesc), len(file_contracts_plugin_v1_plugin_proto_rawDesc)),
-			NumEnums:      4,
-			NumMessages:   43,
+			NumEnums:      5,
+			NumMessages:   55,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
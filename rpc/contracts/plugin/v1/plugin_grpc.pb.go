@@ -27,6 +27,9 @@ const (
 	PluginService_TestConnection_FullMethodName      = "/plugin.v1.PluginService/TestConnection"
 	PluginService_GetCompletionFields_FullMethodName = "/plugin.v1.PluginService/GetCompletionFields"
 	PluginService_MutateRow_FullMethodName           = "/plugin.v1.PluginService/MutateRow"
+	PluginService_Validate_FullMethodName            = "/plugin.v1.PluginService/Validate"
+	PluginService_GenerateDDL_FullMethodName         = "/plugin.v1.PluginService/GenerateDDL"
+	PluginService_Settings_FullMethodName            = "/plugin.v1.PluginService/Settings"
 )
 
 // PluginServiceClient is the client API for PluginService service.
@@ -77,6 +80,21 @@ type PluginServiceClient interface {
 	// RPC is OPTIONAL – plugins that do not implement it should return
 	// success=false and an appropriate error message.
 	MutateRow(ctx context.Context, in *PluginV1_MutateRowRequest, opts ...grpc.CallOption) (*PluginV1_MutateRowResponse, error)
+	// Validate parses and/or plans a query without executing it (e.g. Postgres
+	// `PREPARE`) so the editor can surface syntax errors before the user runs
+	// a potentially destructive statement.  This RPC is OPTIONAL – plugins that
+	// do not implement it report unsupported=true.
+	Validate(ctx context.Context, in *PluginV1_ValidateRequest, opts ...grpc.CallOption) (*PluginV1_ValidateResponse, error)
+	// GenerateDDL returns the statement(s) that recreate the object behind a
+	// connection tree node (e.g. `CREATE TABLE` plus its indexes for SQL
+	// drivers) so the UI can offer "copy as CREATE".  This RPC is OPTIONAL –
+	// plugins that do not implement it report unsupported=true.
+	GenerateDDL(ctx context.Context, in *PluginV1_GenerateDDLRequest, opts ...grpc.CallOption) (*PluginV1_GenerateDDLResponse, error)
+	// Settings declares the plugin's user-configurable options (e.g. a default
+	// row cap) so the host can render them in a plugin-settings panel and pass
+	// the chosen values back in ExecRequest.options.  This RPC is OPTIONAL –
+	// plugins that do not implement it declare no settings.
+	Settings(ctx context.Context, in *PluginV1_SettingsRequest, opts ...grpc.CallOption) (*PluginV1_SettingsResponse, error)
 }
 
 type pluginServiceClient struct {
@@ -167,6 +185,36 @@ func (c *pluginServiceClient) MutateRow(ctx context.Context, in *PluginV1_Mutate
 	return out, nil
 }
 
+func (c *pluginServiceClient) Validate(ctx context.Context, in *PluginV1_ValidateRequest, opts ...grpc.CallOption) (*PluginV1_ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginV1_ValidateResponse)
+	err := c.cc.Invoke(ctx, PluginService_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) GenerateDDL(ctx context.Context, in *PluginV1_GenerateDDLRequest, opts ...grpc.CallOption) (*PluginV1_GenerateDDLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginV1_GenerateDDLResponse)
+	err := c.cc.Invoke(ctx, PluginService_GenerateDDL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) Settings(ctx context.Context, in *PluginV1_SettingsRequest, opts ...grpc.CallOption) (*PluginV1_SettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginV1_SettingsResponse)
+	err := c.cc.Invoke(ctx, PluginService_Settings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PluginServiceServer is the server API for PluginService service.
 // All implementations must embed UnimplementedPluginServiceServer
 // for forward compatibility.
@@ -215,6 +263,21 @@ type PluginServiceServer interface {
 	// RPC is OPTIONAL – plugins that do not implement it should return
 	// success=false and an appropriate error message.
 	MutateRow(context.Context, *PluginV1_MutateRowRequest) (*PluginV1_MutateRowResponse, error)
+	// Validate parses and/or plans a query without executing it (e.g. Postgres
+	// `PREPARE`) so the editor can surface syntax errors before the user runs
+	// a potentially destructive statement.  This RPC is OPTIONAL – plugins that
+	// do not implement it report unsupported=true.
+	Validate(context.Context, *PluginV1_ValidateRequest) (*PluginV1_ValidateResponse, error)
+	// GenerateDDL returns the statement(s) that recreate the object behind a
+	// connection tree node (e.g. `CREATE TABLE` plus its indexes for SQL
+	// drivers) so the UI can offer "copy as CREATE".  This RPC is OPTIONAL –
+	// plugins that do not implement it report unsupported=true.
+	GenerateDDL(context.Context, *PluginV1_GenerateDDLRequest) (*PluginV1_GenerateDDLResponse, error)
+	// Settings declares the plugin's user-configurable options (e.g. a default
+	// row cap) so the host can render them in a plugin-settings panel and pass
+	// the chosen values back in ExecRequest.options.  This RPC is OPTIONAL –
+	// plugins that do not implement it declare no settings.
+	Settings(context.Context, *PluginV1_SettingsRequest) (*PluginV1_SettingsResponse, error)
 	mustEmbedUnimplementedPluginServiceServer()
 }
 
@@ -249,6 +312,15 @@ func (UnimplementedPluginServiceServer) GetCompletionFields(context.Context, *Pl
 func (UnimplementedPluginServiceServer) MutateRow(context.Context, *PluginV1_MutateRowRequest) (*PluginV1_MutateRowResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method MutateRow not implemented")
 }
+func (UnimplementedPluginServiceServer) Validate(context.Context, *PluginV1_ValidateRequest) (*PluginV1_ValidateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedPluginServiceServer) GenerateDDL(context.Context, *PluginV1_GenerateDDLRequest) (*PluginV1_GenerateDDLResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GenerateDDL not implemented")
+}
+func (UnimplementedPluginServiceServer) Settings(context.Context, *PluginV1_SettingsRequest) (*PluginV1_SettingsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Settings not implemented")
+}
 func (UnimplementedPluginServiceServer) mustEmbedUnimplementedPluginServiceServer() {}
 func (UnimplementedPluginServiceServer) testEmbeddedByValue()                       {}
 
@@ -414,6 +486,60 @@ func _PluginService_MutateRow_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PluginService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginV1_ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).Validate(ctx, req.(*PluginV1_ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_GenerateDDL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginV1_GenerateDDLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).GenerateDDL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_GenerateDDL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).GenerateDDL(ctx, req.(*PluginV1_GenerateDDLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_Settings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginV1_SettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).Settings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_Settings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).Settings(ctx, req.(*PluginV1_SettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PluginService_ServiceDesc is the grpc.ServiceDesc for PluginService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -453,6 +579,18 @@ var PluginService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MutateRow",
 			Handler:    _PluginService_MutateRow_Handler,
 		},
+		{
+			MethodName: "Validate",
+			Handler:    _PluginService_Validate_Handler,
+		},
+		{
+			MethodName: "GenerateDDL",
+			Handler:    _PluginService_GenerateDDL_Handler,
+		},
+		{
+			MethodName: "Settings",
+			Handler:    _PluginService_Settings_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "contracts/plugin/v1/plugin.proto",
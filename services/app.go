@@ -202,15 +202,56 @@ func (a *App) ClosePluginsWindow() {
 	}
 }
 
-// OpenFileDialog opens a native file picker and returns the selected file path.
-// Returns an empty string if the user cancels.
+// OpenFileDialog opens a native file picker for SQLite database files and
+// returns the selected file path. Returns an empty string if the user cancels.
 func (a *App) OpenFileDialog() (string, error) {
-	return a.App.Dialog.OpenFile().
-		SetTitle("Select SQLite Database File").
-		CanChooseFiles(true).
-		AddFilter("SQLite Database", "*.db;*.sqlite;*.sqlite3").
+	return a.OpenFileDialogWithFilters("Select SQLite Database File", sqliteFileFilters)
+}
+
+// OpenFileDialogWithFilters opens a native file picker with the given title
+// and type filters (an "All Files" filter is always offered) and returns the
+// selected file path, so auth form fields other than SQLite files (e.g. a CA
+// certificate) can reuse the picker. Returns an empty string if the user
+// cancels.
+func (a *App) OpenFileDialogWithFilters(title string, filters []FileFilter) (string, error) {
+	filters, err := fileDialogFilters(filters)
+	if err != nil {
+		return "", err
+	}
+	if title == "" {
+		title = "Select File"
+	}
+	dialog := a.App.Dialog.OpenFile().
+		SetTitle(title).
+		CanChooseFiles(true)
+	for _, f := range filters {
+		dialog.AddFilter(f.Name, f.Pattern)
+	}
+	return dialog.PromptForSingleSelection()
+}
+
+// SaveResults opens a native save dialog filtered for the given export format
+// ("csv" or "json") and writes the already-encoded content to the chosen
+// file. It returns the written path, or an empty string if the user cancels.
+func (a *App) SaveResults(format string, content string) (string, error) {
+	filter, err := exportFilterFor(format)
+	if err != nil {
+		return "", err
+	}
+	path, err := a.App.Dialog.SaveFile().
+		SetMessage("Save Results").
+		SetFilename("results"+filter.Extension).
+		CanCreateDirectories(true).
+		AddFilter(filter.Name, filter.Pattern).
 		AddFilter("All Files", "*").
 		PromptForSingleSelection()
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", nil
+	}
+	return writeExportFile(path, filter, content)
 }
 
 // CloseConnectionsWindow hides the connections window and sends it to the back.
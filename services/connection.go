@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/felixdotgo/querybox/pkg/driverid"
@@ -24,8 +25,17 @@ type Connection struct {
 	Name          string `json:"name"`
 	DriverType    string `json:"driver_type"`
 	CredentialKey string `json:"credential_key"`
-	CreatedAt     string `json:"created_at"`
-	UpdatedAt     string `json:"updated_at"`
+	// Color is an optional user-chosen label colour (e.g. "#e03131") used to
+	// tell prod/staging/dev apart at a glance. Empty means no colour.
+	Color string `json:"color"`
+	// Group is the folder the connection is filed under in the connections
+	// list. Empty means ungrouped.
+	Group string `json:"group"`
+	// Notes is optional free text the user attaches to the connection (e.g.
+	// "read replica, do not write"). Empty means no notes.
+	Notes     string `json:"notes"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
 }
 
 // ConnectionService is the application-facing service that exposes connection
@@ -36,6 +46,10 @@ type ConnectionService struct {
 	db   *sql.DB
 	cred credmanager.CredentialStore
 	app  *application.App
+
+	// statuses caches the last known health of each connection; see
+	// RecordConnectionStatus.
+	statuses connectionStatuses
 }
 
 // SetApp injects the Wails application reference so the service can emit
@@ -44,6 +58,16 @@ func (s *ConnectionService) SetApp(app *application.App) {
 	s.app = app
 }
 
+// CredentialBackend returns the label of the credential store in use
+// ("keyring", "sqlite" or "memory"), or "" when the store does not report
+// one.
+func (s *ConnectionService) CredentialBackend() string {
+	if b, ok := s.cred.(interface{ Backend() string }); ok {
+		return b.Backend()
+	}
+	return ""
+}
+
 // dataDir returns the directory where application data (e.g. the SQLite DB)
 // should be stored.  Its behaviour is intentionally simple so callers can
 // reason about backups, migrations, and runtime diagnostics.  The path is
@@ -107,6 +131,11 @@ func NewConnectionService() (*ConnectionService, error) {
 		return nil, fmt.Errorf("initialize connections schema: %w", err)
 	}
 
+	if err := migrateConnections(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
 	// Use the same directory as connections.db so both databases land in the
 	// same per-user config location regardless of the working directory.
 	svc := &ConnectionService{db: db, cred: credmanager.NewWithPath(filepath.Join(dir, "credentials.db"))}
@@ -157,24 +186,122 @@ func (s *ConnectionService) hasColumn(col string) (bool, error) {
 	return false, nil
 }
 
-// ListConnections returns all stored connections ordered by creation time
-// (newest first).
+// connectionMigrations are the schema changes applied on top of the base
+// `connections` table, in order. Entry i upgrades the schema to version i+1;
+// append new migrations to the end and never edit or reorder existing ones.
+var connectionMigrations = []string{
+	// 1: explicit user-defined ordering (see ReorderConnections). NULL means
+	// the row has never been reordered and sorts by creation time.
+	`ALTER TABLE connections ADD COLUMN sort_index INTEGER`,
+	// 2: optional per-connection colour label.
+	`ALTER TABLE connections ADD COLUMN color TEXT NOT NULL DEFAULT ''`,
+	// 3: folder the connection is grouped under; '' means ungrouped.
+	`ALTER TABLE connections ADD COLUMN group_name TEXT NOT NULL DEFAULT ''`,
+	// 4: optional free-text notes.
+	`ALTER TABLE connections ADD COLUMN notes TEXT NOT NULL DEFAULT ''`,
+}
+
+// migrateConnections brings the connections schema up to date using the
+// version recorded in the `schema_version` table. Each pending migration runs
+// in its own transaction together with the version bump.
+func migrateConnections(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`); err != nil {
+		return fmt.Errorf("initialize schema_version: %w", err)
+	}
+	var version int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	for i := version; i < len(connectionMigrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(connectionMigrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("apply migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_version`); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("record migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_version (version) VALUES (?)`, i+1); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ListConnections returns all stored connections. Rows positioned by
+// ReorderConnections follow their sort index; rows that have never been
+// reordered (including connections created afterwards) come first, ordered
+// by creation time (newest first).
 func (s *ConnectionService) ListConnections(ctx context.Context) ([]Connection, error) {
 	if !s.closeable() {
 		return nil, errors.New("connections database not initialized")
 	}
-	rows, err := s.db.QueryContext(ctx, `SELECT id, name, driver_type, credential_key, created_at, updated_at FROM connections ORDER BY created_at DESC`)
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, driver_type, credential_key, color, group_name, notes, created_at, updated_at FROM connections ORDER BY sort_index IS NOT NULL, sort_index ASC, created_at DESC`)
 	if err != nil {
 		emitLog(s.app, LogLevelError, fmt.Sprintf("ListConnections: query failed: %v", err))
 		return nil, fmt.Errorf("query connections: %w", err)
 	}
 	defer rows.Close()
 
+	out, err := scanConnections(rows)
+	if err != nil {
+		return nil, err
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("ListConnections: found %d connection(s)", len(out)))
+	return out, nil
+}
+
+// SearchConnections returns the connections whose name or driver type
+// contains query (case-insensitive), in the same order as ListConnections.
+// An empty or whitespace-only query returns every connection.
+func (s *ConnectionService) SearchConnections(ctx context.Context, query string) ([]Connection, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return s.ListConnections(ctx)
+	}
+	if !s.closeable() {
+		return nil, errors.New("connections database not initialized")
+	}
+	pattern := "%" + escapeLike(query) + "%"
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, driver_type, credential_key, color, group_name, notes, created_at, updated_at FROM connections
+		WHERE name LIKE ? ESCAPE '\' OR driver_type LIKE ? ESCAPE '\'
+		ORDER BY sort_index IS NOT NULL, sort_index ASC, created_at DESC`, pattern, pattern)
+	if err != nil {
+		emitLog(s.app, LogLevelError, fmt.Sprintf("SearchConnections: query failed: %v", err))
+		return nil, fmt.Errorf("search connections: %w", err)
+	}
+	defer rows.Close()
+
+	out, err := scanConnections(rows)
+	if err != nil {
+		return nil, err
+	}
+	emitLog(s.app, LogLevelDebug, fmt.Sprintf("SearchConnections: %d match(es) for %q", len(out), query))
+	return out, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so user input is matched
+// literally; callers must pair it with `ESCAPE '\'`.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// scanConnections reads every row of a `SELECT id, name, driver_type,
+// credential_key, color, group_name, notes, created_at, updated_at` query.
+func scanConnections(rows *sql.Rows) ([]Connection, error) {
 	var out []Connection
 	for rows.Next() {
 		var r Connection
 		var credKey sql.NullString
-		if err := rows.Scan(&r.ID, &r.Name, &r.DriverType, &credKey, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		if err := rows.Scan(&r.ID, &r.Name, &r.DriverType, &credKey, &r.Color, &r.Group, &r.Notes, &r.CreatedAt, &r.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan connections: %w", err)
 		}
 		// ensure driver_type is normalized for callers
@@ -187,7 +314,6 @@ func (s *ConnectionService) ListConnections(ctx context.Context) ([]Connection,
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate connections: %w", err)
 	}
-	emitLog(s.app, LogLevelInfo, fmt.Sprintf("ListConnections: found %d connection(s)", len(out)))
 	return out, nil
 }
 
@@ -201,8 +327,8 @@ func (s *ConnectionService) GetConnection(ctx context.Context, id string) (Conne
 	}
 	var r Connection
 	var credKey sql.NullString
-	row := s.db.QueryRowContext(ctx, `SELECT id, name, driver_type, credential_key, created_at, updated_at FROM connections WHERE id = ?`, id)
-	if err := row.Scan(&r.ID, &r.Name, &r.DriverType, &credKey, &r.CreatedAt, &r.UpdatedAt); err != nil {
+	row := s.db.QueryRowContext(ctx, `SELECT id, name, driver_type, credential_key, color, group_name, notes, created_at, updated_at FROM connections WHERE id = ?`, id)
+	if err := row.Scan(&r.ID, &r.Name, &r.DriverType, &credKey, &r.Color, &r.Group, &r.Notes, &r.CreatedAt, &r.UpdatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Connection{}, fmt.Errorf("database connection not found")
 		}
@@ -224,8 +350,9 @@ func normalizeDriverType(dt string) string {
 // CreateConnection inserts a new connection record and returns it. The
 // provided `credential` (typically the frontend-serialized auth form) is
 // stored in the OS keyring and the DB only keeps the key reference.  The
-// driverType is normalized so that ".exe" suffixes are never stored.
-func (s *ConnectionService) CreateConnection(ctx context.Context, name, driverType, credential string) (Connection, error) {
+// driverType is normalized so that ".exe" suffixes are never stored. color is
+// an optional UI label colour and notes optional free text; both may be empty.
+func (s *ConnectionService) CreateConnection(ctx context.Context, name, driverType, credential, color, notes string) (Connection, error) {
     driverType = normalizeDriverType(driverType)
 	if name == "" || driverType == "" {
 		return Connection{}, errors.New("name and driverType are required")
@@ -241,7 +368,7 @@ func (s *ConnectionService) CreateConnection(ctx context.Context, name, driverTy
 		emitLog(s.app, LogLevelError, fmt.Sprintf("CreateConnection: failed to store credential for '%s': %v", name, err))
 		return Connection{}, fmt.Errorf("store credential: %w", err)
 	}
-	if _, err := s.db.ExecContext(ctx, `INSERT INTO connections (id, name, driver_type, credential_key, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`, id, name, driverType, key, now, now); err != nil {
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO connections (id, name, driver_type, credential_key, color, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, id, name, driverType, key, color, notes, now, now); err != nil {
 		emitLog(s.app, LogLevelError, fmt.Sprintf("CreateConnection: failed to insert connection '%s': %v", name, err))
 		return Connection{}, fmt.Errorf("insert database connection: %w", err)
 	}
@@ -251,6 +378,8 @@ func (s *ConnectionService) CreateConnection(ctx context.Context, name, driverTy
 		Name:          name,
 		DriverType:    driverType,
 		CredentialKey: key,
+		Color:         color,
+		Notes:         notes,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
@@ -290,10 +419,11 @@ func (s *ConnectionService) GetCredential(ctx context.Context, id string) (strin
 	return cred, nil
 }
 
-// UpdateConnection updates the name and credential of an existing connection.
-// The credential key in the keyring is reused — only the stored value is
-// overwritten — so the DB row never changes its credential_key reference.
-func (s *ConnectionService) UpdateConnection(ctx context.Context, id, name, credential string) (Connection, error) {
+// UpdateConnection updates the name, credential, colour label and notes of an
+// existing connection. The credential key in the keyring is reused — only the
+// stored value is overwritten — so the DB row never changes its
+// credential_key reference.
+func (s *ConnectionService) UpdateConnection(ctx context.Context, id, name, credential, color, notes string) (Connection, error) {
 	if id == "" || name == "" {
 		return Connection{}, errors.New("id and name are required")
 	}
@@ -315,7 +445,7 @@ func (s *ConnectionService) UpdateConnection(ctx context.Context, id, name, cred
 	}
 
 	now := time.Now().UTC().Format(time.RFC3339Nano)
-	res, err := s.db.ExecContext(ctx, `UPDATE connections SET name = ?, updated_at = ? WHERE id = ?`, name, now, id)
+	res, err := s.db.ExecContext(ctx, `UPDATE connections SET name = ?, color = ?, notes = ?, updated_at = ? WHERE id = ?`, name, color, notes, now, id)
 	if err != nil {
 		emitLog(s.app, LogLevelError, fmt.Sprintf("UpdateConnection: failed to update connection '%s': %v", id, err))
 		return Connection{}, fmt.Errorf("update database connection: %w", err)
@@ -329,6 +459,9 @@ func (s *ConnectionService) UpdateConnection(ctx context.Context, id, name, cred
 		Name:          name,
 		DriverType:    existing.DriverType,
 		CredentialKey: existing.CredentialKey,
+		Color:         color,
+		Group:         existing.Group,
+		Notes:         notes,
 		CreatedAt:     existing.CreatedAt,
 		UpdatedAt:     now,
 	}
@@ -337,6 +470,232 @@ func (s *ConnectionService) UpdateConnection(ctx context.Context, id, name, cred
 	return updated, nil
 }
 
+// CloneConnection creates a copy of connection sourceID under a fresh id, for
+// example a staging copy of a production connection. The driver, group,
+// colour label and notes are copied. The source credential is copied too
+// unless credentialOverride is non-empty, in which case that is stored
+// instead. An empty newName defaults to "<source name> (copy)". If the row
+// cannot be inserted the stored credential is removed again, so a failed
+// clone leaves nothing behind.
+func (s *ConnectionService) CloneConnection(ctx context.Context, sourceID, newName, credentialOverride string) (Connection, error) {
+	if sourceID == "" {
+		return Connection{}, errors.New("source id is required")
+	}
+	if !s.closeable() {
+		return Connection{}, errors.New("connections database not initialized")
+	}
+	src, err := s.GetConnection(ctx, sourceID)
+	if err != nil {
+		emitLog(s.app, LogLevelError, fmt.Sprintf("CloneConnection: source connection '%s' not found: %v", sourceID, err))
+		return Connection{}, err
+	}
+	if newName == "" {
+		newName = src.Name + " (copy)"
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("CloneConnection: cloning '%s' as '%s'", src.Name, newName))
+
+	credential := credentialOverride
+	if credential == "" && src.CredentialKey != "" {
+		if credential, err = s.cred.Get(src.CredentialKey); err != nil {
+			emitLog(s.app, LogLevelError, fmt.Sprintf("CloneConnection: keyring lookup failed for '%s': %v", sourceID, err))
+			return Connection{}, fmt.Errorf("fetch credential: %w", err)
+		}
+	}
+
+	id := uuid.New().String()
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	key := "connection:" + id
+	if err := s.cred.Store(key, credential); err != nil {
+		emitLog(s.app, LogLevelError, fmt.Sprintf("CloneConnection: failed to store credential for '%s': %v", newName, err))
+		return Connection{}, fmt.Errorf("store credential: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO connections (id, name, driver_type, credential_key, color, group_name, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, newName, src.DriverType, key, src.Color, src.Group, src.Notes, now, now); err != nil {
+		_ = s.cred.Delete(key) // best-effort
+		emitLog(s.app, LogLevelError, fmt.Sprintf("CloneConnection: failed to insert connection '%s': %v", newName, err))
+		return Connection{}, fmt.Errorf("insert database connection: %w", err)
+	}
+	conn := Connection{
+		ID:            id,
+		Name:          newName,
+		DriverType:    src.DriverType,
+		CredentialKey: key,
+		Color:         src.Color,
+		Group:         src.Group,
+		Notes:         src.Notes,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("CloneConnection: '%s' created successfully (id: %s)", newName, id))
+	emitConnectionCreated(s.app, conn)
+	return conn, nil
+}
+
+// ReorderConnections persists a user-defined ordering, typically after a
+// drag-and-drop in the connections list. orderedIDs[i] receives sort index i;
+// connections not listed keep their current index. The whole reorder is
+// applied atomically and fails if any id is unknown.
+func (s *ConnectionService) ReorderConnections(ctx context.Context, orderedIDs []string) error {
+	if !s.closeable() {
+		return errors.New("connections database not initialized")
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("ReorderConnections: reordering %d connection(s)", len(orderedIDs)))
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin reorder: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for i, id := range orderedIDs {
+		res, err := tx.ExecContext(ctx, `UPDATE connections SET sort_index = ? WHERE id = ?`, i, id)
+		if err != nil {
+			emitLog(s.app, LogLevelError, fmt.Sprintf("ReorderConnections: failed to update connection '%s': %v", id, err))
+			return fmt.Errorf("update sort index: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			emitLog(s.app, LogLevelWarn, fmt.Sprintf("ReorderConnections: connection '%s' not found", id))
+			return fmt.Errorf("database connection not found: %s", id)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		emitLog(s.app, LogLevelError, fmt.Sprintf("ReorderConnections: commit failed: %v", err))
+		return fmt.Errorf("commit reorder: %w", err)
+	}
+	emitLog(s.app, LogLevelInfo, "ReorderConnections: order saved")
+	return nil
+}
+
+// MoveConnectionsToGroup files the connections identified by ids under group
+// with a single UPDATE, typically after a multi-item drag-and-drop in the
+// connections list. An empty (or whitespace-only) group moves them back to
+// ungrouped. Unknown and empty ids are skipped; the connections actually
+// moved are returned and an EventConnectionUpdated is emitted for each.
+func (s *ConnectionService) MoveConnectionsToGroup(ctx context.Context, ids []string, group string) ([]Connection, error) {
+	if !s.closeable() {
+		return nil, errors.New("connections database not initialized")
+	}
+	group = strings.TrimSpace(group)
+	args := []interface{}{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			args = append(args, id)
+		}
+	}
+	if len(args) == 0 {
+		return []Connection{}, nil
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("MoveConnectionsToGroup: moving %d connection(s) to group '%s'", len(args), group))
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin move: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	update := append([]interface{}{group, now}, args...)
+	if _, err := tx.ExecContext(ctx, `UPDATE connections SET group_name = ?, updated_at = ? WHERE id IN (`+placeholders+`)`, update...); err != nil {
+		emitLog(s.app, LogLevelError, fmt.Sprintf("MoveConnectionsToGroup: update failed: %v", err))
+		return nil, fmt.Errorf("update connection group: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT id, name, driver_type, credential_key, color, group_name, notes, created_at, updated_at FROM connections WHERE id IN (`+placeholders+`)
+		ORDER BY sort_index IS NOT NULL, sort_index ASC, created_at DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query moved connections: %w", err)
+	}
+	moved, err := scanConnections(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		emitLog(s.app, LogLevelError, fmt.Sprintf("MoveConnectionsToGroup: commit failed: %v", err))
+		return nil, fmt.Errorf("commit move: %w", err)
+	}
+
+	if skipped := len(args) - len(moved); skipped > 0 {
+		emitLog(s.app, LogLevelWarn, fmt.Sprintf("MoveConnectionsToGroup: skipped %d unknown connection(s)", skipped))
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("MoveConnectionsToGroup: %d connection(s) moved", len(moved)))
+	if moved == nil {
+		moved = []Connection{}
+	}
+	for _, c := range moved {
+		emitConnectionUpdated(s.app, c)
+	}
+	return moved, nil
+}
+
+// DeleteConnectionsResult reports the outcome of DeleteConnections.
+type DeleteConnectionsResult struct {
+	Deleted []string          `json:"deleted"`
+	Failed  map[string]string `json:"failed"` // id -> reason
+}
+
+// DeleteConnections removes several connections in a single transaction and
+// emits one EventConnectionsDeleted for the batch.  Ids that are empty or do
+// not exist are reported in Failed and do not abort the rest of the batch;
+// an error is returned only when the database itself fails, in which case
+// nothing is deleted.  Keyring secrets of deleted connections are removed
+// best-effort after the commit.
+func (s *ConnectionService) DeleteConnections(ctx context.Context, ids []string) (DeleteConnectionsResult, error) {
+	if !s.closeable() {
+		return DeleteConnectionsResult{}, errors.New("connections database not initialized")
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("DeleteConnections: deleting %d connection(s)", len(ids)))
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return DeleteConnectionsResult{}, fmt.Errorf("begin delete: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result := DeleteConnectionsResult{Deleted: []string{}, Failed: map[string]string{}}
+	var credKeys []string
+	for _, id := range ids {
+		if id == "" {
+			result.Failed[id] = "empty id"
+			continue
+		}
+		var credKey sql.NullString
+		err := tx.QueryRowContext(ctx, `SELECT credential_key FROM connections WHERE id = ?`, id).Scan(&credKey)
+		if errors.Is(err, sql.ErrNoRows) {
+			result.Failed[id] = "database connection not found"
+			continue
+		}
+		if err != nil {
+			return DeleteConnectionsResult{}, fmt.Errorf("lookup database connection before delete: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM connections WHERE id = ?`, id); err != nil {
+			emitLog(s.app, LogLevelError, fmt.Sprintf("DeleteConnections: failed to delete connection '%s': %v", id, err))
+			return DeleteConnectionsResult{}, fmt.Errorf("delete database connection: %w", err)
+		}
+		if credKey.Valid && credKey.String != "" {
+			credKeys = append(credKeys, credKey.String)
+		}
+		result.Deleted = append(result.Deleted, id)
+	}
+	if err := tx.Commit(); err != nil {
+		emitLog(s.app, LogLevelError, fmt.Sprintf("DeleteConnections: commit failed: %v", err))
+		return DeleteConnectionsResult{}, fmt.Errorf("commit delete: %w", err)
+	}
+	for _, key := range credKeys {
+		_ = s.cred.Delete(key) // best-effort
+	}
+
+	for id, reason := range result.Failed {
+		emitLog(s.app, LogLevelWarn, fmt.Sprintf("DeleteConnections: skipped connection '%s': %s", id, reason))
+	}
+	emitLog(s.app, LogLevelInfo, fmt.Sprintf("DeleteConnections: %d connection(s) deleted", len(result.Deleted)))
+	if len(result.Deleted) > 0 {
+		s.forgetConnectionStatus(result.Deleted...)
+		emitConnectionsDeleted(s.app, result.Deleted)
+	}
+	return result, nil
+}
+
 // DeleteConnection removes a connection by id and attempts to remove the
 // associated secret from the keyring as a best-effort cleanup.
 func (s *ConnectionService) DeleteConnection(ctx context.Context, id string) error {
@@ -367,6 +726,7 @@ func (s *ConnectionService) DeleteConnection(ctx context.Context, id string) err
 		return fmt.Errorf("database connection not found")
 	}
 	emitLog(s.app, LogLevelInfo, fmt.Sprintf("DeleteConnection: connection '%s' deleted successfully", id))
+	s.forgetConnectionStatus(id)
 	emitConnectionDeleted(s.app, id)
 	return nil
 }
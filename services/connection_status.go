@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// connectionStatusTTL is how long a recorded status is reported before it is
+// considered stale and the connection has to be tested again.
+const connectionStatusTTL = 5 * time.Minute
+
+// ConnectionStatus is the last known health of a connection, as reported by
+// a TestConnection or Warmup run. It lets the connections list show a
+// green/red indicator without re-testing on every render.
+type ConnectionStatus struct {
+	ConnectionID string `json:"connection_id"`
+	OK           bool   `json:"ok"`
+	Message      string `json:"message"`
+	CheckedAt    string `json:"checked_at"` // RFC3339 UTC
+}
+
+// connectionStatuses is the in-memory status cache of a ConnectionService.
+// Statuses are deliberately not persisted: after a restart they would be
+// stale anyway. The zero value is ready to use.
+type connectionStatuses struct {
+	mu      sync.Mutex
+	entries map[string]statusEntry
+}
+
+type statusEntry struct {
+	status ConnectionStatus
+	at     time.Time
+}
+
+// statusNow is the clock used for status timestamps; tests override it.
+var statusNow = time.Now
+
+// RecordConnectionStatus stores the outcome of testing connection id, replacing
+// any earlier status, and emits EventConnectionStatus so open lists update.
+func (s *ConnectionService) RecordConnectionStatus(ctx context.Context, id string, ok bool, message string) (ConnectionStatus, error) {
+	if id == "" {
+		return ConnectionStatus{}, errors.New("empty id")
+	}
+	now := statusNow()
+	st := ConnectionStatus{
+		ConnectionID: id,
+		OK:           ok,
+		Message:      message,
+		CheckedAt:    now.UTC().Format(time.RFC3339),
+	}
+	s.statuses.mu.Lock()
+	if s.statuses.entries == nil {
+		s.statuses.entries = make(map[string]statusEntry)
+	}
+	s.statuses.entries[id] = statusEntry{status: st, at: now}
+	s.statuses.mu.Unlock()
+
+	if !ok {
+		emitLog(s.app, LogLevelWarn, fmt.Sprintf("RecordConnectionStatus: connection '%s' failed: %s", id, message))
+	}
+	emitConnectionStatus(s.app, st)
+	return st, nil
+}
+
+// GetConnectionStatus returns the last recorded status of connection id, or
+// nil when none was recorded or it is older than connectionStatusTTL.
+func (s *ConnectionService) GetConnectionStatus(ctx context.Context, id string) (*ConnectionStatus, error) {
+	if id == "" {
+		return nil, errors.New("empty id")
+	}
+	s.statuses.mu.Lock()
+	defer s.statuses.mu.Unlock()
+	e, ok := s.statuses.entries[id]
+	if !ok {
+		return nil, nil
+	}
+	if statusNow().Sub(e.at) >= connectionStatusTTL {
+		delete(s.statuses.entries, id)
+		return nil, nil
+	}
+	st := e.status
+	return &st, nil
+}
+
+// forgetConnectionStatus drops the status of deleted connections.
+func (s *ConnectionService) forgetConnectionStatus(ids ...string) {
+	s.statuses.mu.Lock()
+	defer s.statuses.mu.Unlock()
+	for _, id := range ids {
+		delete(s.statuses.entries, id)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConnectionStatusSetGet(t *testing.T) {
+	svc := &ConnectionService{}
+	ctx := context.Background()
+
+	if st, err := svc.GetConnectionStatus(ctx, "c1"); err != nil || st != nil {
+		t.Fatalf("unknown connection: got %v, %v; want nil, nil", st, err)
+	}
+
+	if _, err := svc.RecordConnectionStatus(ctx, "c1", false, "connection refused"); err != nil {
+		t.Fatalf("RecordConnectionStatus: %v", err)
+	}
+	if _, err := svc.RecordConnectionStatus(ctx, "c1", true, "Connection successful"); err != nil {
+		t.Fatalf("RecordConnectionStatus: %v", err)
+	}
+	st, err := svc.GetConnectionStatus(ctx, "c1")
+	if err != nil {
+		t.Fatalf("GetConnectionStatus: %v", err)
+	}
+	if st == nil || !st.OK || st.Message != "Connection successful" || st.ConnectionID != "c1" {
+		t.Fatalf("latest status should win, got %+v", st)
+	}
+	if _, err := time.Parse(time.RFC3339, st.CheckedAt); err != nil {
+		t.Errorf("CheckedAt %q is not RFC3339: %v", st.CheckedAt, err)
+	}
+
+	if _, err := svc.RecordConnectionStatus(ctx, "", true, ""); err == nil {
+		t.Error("expected error for empty id")
+	}
+}
+
+func TestConnectionStatusExpires(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	orig := statusNow
+	statusNow = func() time.Time { return now }
+	defer func() { statusNow = orig }()
+
+	svc := &ConnectionService{}
+	ctx := context.Background()
+	if _, err := svc.RecordConnectionStatus(ctx, "c1", true, "ok"); err != nil {
+		t.Fatalf("RecordConnectionStatus: %v", err)
+	}
+
+	now = now.Add(connectionStatusTTL - time.Second)
+	if st, _ := svc.GetConnectionStatus(ctx, "c1"); st == nil {
+		t.Fatal("status should still be fresh just before the TTL")
+	}
+	now = now.Add(time.Second)
+	if st, _ := svc.GetConnectionStatus(ctx, "c1"); st != nil {
+		t.Fatalf("status should have expired, got %+v", st)
+	}
+}
+
+func TestDeleteConnectionForgetsStatus(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	conn, err := svc.CreateConnection(ctx, "db", "sqlite", "", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection: %v", err)
+	}
+	if _, err := svc.RecordConnectionStatus(ctx, conn.ID, true, "ok"); err != nil {
+		t.Fatalf("RecordConnectionStatus: %v", err)
+	}
+	if err := svc.DeleteConnection(ctx, conn.ID); err != nil {
+		t.Fatalf("DeleteConnection: %v", err)
+	}
+	if st, _ := svc.GetConnectionStatus(ctx, conn.ID); st != nil {
+		t.Errorf("status of a deleted connection should be gone, got %+v", st)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -72,13 +73,13 @@ func TestConnectionService_UpdateConnection(t *testing.T) {
 	ctx := context.Background()
 
 	// Create a connection to update.
-	created, err := svc.CreateConnection(ctx, "original", "postgresql", `{"form":"basic","values":{"host":"localhost"}}`)
+	created, err := svc.CreateConnection(ctx, "original", "postgresql", `{"form":"basic","values":{"host":"localhost"}}`, "", "")
 	if err != nil {
 		t.Fatalf("CreateConnection failed: %v", err)
 	}
 
 	// Update name and credential.
-	updated, err := svc.UpdateConnection(ctx, created.ID, "renamed", `{"form":"basic","values":{"host":"remotehost"}}`)
+	updated, err := svc.UpdateConnection(ctx, created.ID, "renamed", `{"form":"basic","values":{"host":"remotehost"}}`, "", "")
 	if err != nil {
 		t.Fatalf("UpdateConnection failed: %v", err)
 	}
@@ -113,7 +114,7 @@ func TestConnectionService_UpdateConnection_UnknownID(t *testing.T) {
 	}
 	defer svc.Shutdown()
 
-	_, uerr := svc.UpdateConnection(context.Background(), "does-not-exist", "newname", "cred")
+	_, uerr := svc.UpdateConnection(context.Background(), "does-not-exist", "newname", "cred", "", "")
 	if uerr == nil {
 		t.Fatal("expected error for unknown connection ID, got nil")
 	}
@@ -131,7 +132,7 @@ func TestConnectionService_DriverTypeNormalization(t *testing.T) {
 
 	ctx := context.Background()
 	// intentionally include a fake ".exe" suffix to simulate Windows input
-	created, err := svc.CreateConnection(ctx, "foo", "dbdriver.exe", "cred")
+	created, err := svc.CreateConnection(ctx, "foo", "dbdriver.exe", "cred", "", "")
 	if err != nil {
 		t.Fatalf("CreateConnection failed: %v", err)
 	}
@@ -157,3 +158,417 @@ func TestConnectionService_DriverTypeNormalization(t *testing.T) {
 		t.Fatal("created connection not found in list")
 	}
 }
+
+// newIsolatedConnectionService opens a ConnectionService backed by a fresh
+// temporary config directory so tests that depend on the full contents of
+// the connections table are not affected by other rows.
+func newIsolatedConnectionService(t *testing.T) *ConnectionService {
+	t.Helper()
+	orig := userConfigDirFunc
+	dir := t.TempDir()
+	userConfigDirFunc = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { userConfigDirFunc = orig })
+
+	svc, err := NewConnectionService()
+	if err != nil {
+		t.Skip("database not available, skipping test")
+	}
+	t.Cleanup(svc.Shutdown)
+	return svc
+}
+
+func connectionIDs(list []Connection) []string {
+	ids := make([]string, len(list))
+	for i, c := range list {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func TestConnectionService_ReorderConnections(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	var created []Connection
+	for _, name := range []string{"a", "b", "c"} {
+		c, err := svc.CreateConnection(ctx, name, "sqlite", "cred", "", "")
+		if err != nil {
+			t.Fatalf("CreateConnection(%s) failed: %v", name, err)
+		}
+		created = append(created, c)
+	}
+
+	want := []string{created[1].ID, created[0].ID, created[2].ID}
+	if err := svc.ReorderConnections(ctx, want); err != nil {
+		t.Fatalf("ReorderConnections failed: %v", err)
+	}
+
+	list, err := svc.ListConnections(ctx)
+	if err != nil {
+		t.Fatalf("ListConnections failed: %v", err)
+	}
+	if got := connectionIDs(list); !reflect.DeepEqual(got, want) {
+		t.Fatalf("order after reorder = %v; want %v", got, want)
+	}
+
+	// The order must survive a restart of the service.
+	svc.Shutdown()
+	reopened, err := NewConnectionService()
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Shutdown()
+	list, err = reopened.ListConnections(ctx)
+	if err != nil {
+		t.Fatalf("ListConnections after reopen failed: %v", err)
+	}
+	if got := connectionIDs(list); !reflect.DeepEqual(got, want) {
+		t.Fatalf("order after reopen = %v; want %v", got, want)
+	}
+}
+
+func TestConnectionService_ReorderConnections_UnknownID(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	c, err := svc.CreateConnection(ctx, "a", "sqlite", "cred", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+	if err := svc.ReorderConnections(ctx, []string{c.ID, "does-not-exist"}); err == nil {
+		t.Fatal("expected error for unknown connection ID, got nil")
+	}
+}
+
+func TestConnectionService_SearchConnections(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	prod, err := svc.CreateConnection(ctx, "Production DB", "postgresql", "cred", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+	local, err := svc.CreateConnection(ctx, "local_cache", "sqlite", "cred", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"partial name, case-insensitive", "prod", []string{prod.ID}},
+		{"driver match", "SQLite", []string{local.ID}},
+		{"wildcards are literal", "%", nil},
+		{"underscore is literal", "ca_h", nil},
+		{"underscore matches itself", "_cache", []string{local.ID}},
+		{"empty query returns all", "  ", []string{local.ID, prod.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.SearchConnections(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("SearchConnections(%q) failed: %v", tt.query, err)
+			}
+			ids := connectionIDs(got)
+			if len(ids) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SearchConnections(%q) = %v; want %v", tt.query, ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectionService_ColorRoundTrip(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateConnection(ctx, "prod", "postgresql", "cred", "#e03131", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+	if created.Color != "#e03131" {
+		t.Errorf("CreateConnection color = %q; want %q", created.Color, "#e03131")
+	}
+	plain, err := svc.CreateConnection(ctx, "dev", "sqlite", "cred", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	list, err := svc.ListConnections(ctx)
+	if err != nil {
+		t.Fatalf("ListConnections failed: %v", err)
+	}
+	colors := map[string]string{}
+	for _, c := range list {
+		colors[c.ID] = c.Color
+	}
+	if colors[created.ID] != "#e03131" || colors[plain.ID] != "" {
+		t.Errorf("listed colors = %v", colors)
+	}
+
+	if _, err := svc.UpdateConnection(ctx, created.ID, "prod", "cred", "#2f9e44", ""); err != nil {
+		t.Fatalf("UpdateConnection failed: %v", err)
+	}
+	got, err := svc.GetConnection(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetConnection failed: %v", err)
+	}
+	if got.Color != "#2f9e44" {
+		t.Errorf("color after update = %q; want %q", got.Color, "#2f9e44")
+	}
+}
+
+func TestConnectionService_NotesRoundTrip(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	created, err := svc.CreateConnection(ctx, "prod", "postgresql", "cred", "", "owned by data team\nread-only replica")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+	if created.Notes != "owned by data team\nread-only replica" {
+		t.Errorf("CreateConnection notes = %q", created.Notes)
+	}
+	plain, err := svc.CreateConnection(ctx, "dev", "sqlite", "cred", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	list, err := svc.ListConnections(ctx)
+	if err != nil {
+		t.Fatalf("ListConnections failed: %v", err)
+	}
+	notes := map[string]string{}
+	for _, c := range list {
+		notes[c.ID] = c.Notes
+	}
+	if notes[created.ID] != created.Notes || notes[plain.ID] != "" {
+		t.Errorf("listed notes = %v", notes)
+	}
+
+	if _, err := svc.UpdateConnection(ctx, created.ID, "prod", "cred", "", "decommissioned"); err != nil {
+		t.Fatalf("UpdateConnection failed: %v", err)
+	}
+	got, err := svc.GetConnection(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetConnection failed: %v", err)
+	}
+	if got.Notes != "decommissioned" {
+		t.Errorf("notes after update = %q; want %q", got.Notes, "decommissioned")
+	}
+}
+
+func TestConnectionService_CloneConnection(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	src, err := svc.CreateConnection(ctx, "prod", "postgresql", `{"form":"basic","values":{"host":"prod-db"}}`, "#e03131", "read replica, do not write")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+	if _, err := svc.MoveConnectionsToGroup(ctx, []string{src.ID}, "Production"); err != nil {
+		t.Fatalf("MoveConnectionsToGroup failed: %v", err)
+	}
+
+	t.Run("reuses credential", func(t *testing.T) {
+		clone, err := svc.CloneConnection(ctx, src.ID, "prod copy", "")
+		if err != nil {
+			t.Fatalf("CloneConnection failed: %v", err)
+		}
+		if clone.ID == src.ID || clone.CredentialKey == src.CredentialKey {
+			t.Errorf("clone shares id or credential key with source: %+v", clone)
+		}
+		got, err := svc.GetConnection(ctx, clone.ID)
+		if err != nil {
+			t.Fatalf("GetConnection failed: %v", err)
+		}
+		if got.Name != "prod copy" || got.DriverType != "postgresql" || got.Color != "#e03131" ||
+			got.Group != "Production" || got.Notes != "read replica, do not write" {
+			t.Errorf("cloned metadata = %+v", got)
+		}
+		cred, err := svc.GetCredential(ctx, clone.ID)
+		if err != nil {
+			t.Fatalf("GetCredential failed: %v", err)
+		}
+		if cred != `{"form":"basic","values":{"host":"prod-db"}}` {
+			t.Errorf("cloned credential = %q", cred)
+		}
+	})
+
+	t.Run("overrides credential", func(t *testing.T) {
+		staging := `{"form":"basic","values":{"host":"staging-db"}}`
+		clone, err := svc.CloneConnection(ctx, src.ID, "", staging)
+		if err != nil {
+			t.Fatalf("CloneConnection failed: %v", err)
+		}
+		if clone.Name != "prod (copy)" {
+			t.Errorf("default clone name = %q", clone.Name)
+		}
+		if cred, _ := svc.GetCredential(ctx, clone.ID); cred != staging {
+			t.Errorf("clone credential = %q, want the override", cred)
+		}
+		// the source keeps its own credential
+		if cred, _ := svc.GetCredential(ctx, src.ID); cred != `{"form":"basic","values":{"host":"prod-db"}}` {
+			t.Errorf("source credential changed to %q", cred)
+		}
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		if _, err := svc.CloneConnection(ctx, "missing", "x", ""); err == nil {
+			t.Error("expected an error for an unknown source id")
+		}
+	})
+}
+
+func TestConnectionService_DeleteConnections(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	var created []Connection
+	for _, name := range []string{"a", "b", "c"} {
+		c, err := svc.CreateConnection(ctx, name, "sqlite", "cred", "", "")
+		if err != nil {
+			t.Fatalf("CreateConnection(%s) failed: %v", name, err)
+		}
+		created = append(created, c)
+	}
+
+	res, err := svc.DeleteConnections(ctx, []string{created[0].ID, created[2].ID})
+	if err != nil {
+		t.Fatalf("DeleteConnections failed: %v", err)
+	}
+	if want := []string{created[0].ID, created[2].ID}; !reflect.DeepEqual(res.Deleted, want) {
+		t.Errorf("Deleted = %v; want %v", res.Deleted, want)
+	}
+	if len(res.Failed) != 0 {
+		t.Errorf("unexpected failures: %v", res.Failed)
+	}
+
+	list, err := svc.ListConnections(ctx)
+	if err != nil {
+		t.Fatalf("ListConnections failed: %v", err)
+	}
+	if got := connectionIDs(list); !reflect.DeepEqual(got, []string{created[1].ID}) {
+		t.Errorf("remaining connections = %v; want [%s]", got, created[1].ID)
+	}
+}
+
+func TestConnectionService_DeleteConnections_PartialFailure(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	c, err := svc.CreateConnection(ctx, "a", "sqlite", "cred", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	res, err := svc.DeleteConnections(ctx, []string{"does-not-exist", c.ID, ""})
+	if err != nil {
+		t.Fatalf("DeleteConnections failed: %v", err)
+	}
+	if !reflect.DeepEqual(res.Deleted, []string{c.ID}) {
+		t.Errorf("Deleted = %v; want [%s]", res.Deleted, c.ID)
+	}
+	if _, ok := res.Failed["does-not-exist"]; !ok || len(res.Failed) != 2 {
+		t.Errorf("Failed = %v; want the unknown and empty ids", res.Failed)
+	}
+
+	list, err := svc.ListConnections(ctx)
+	if err != nil {
+		t.Fatalf("ListConnections failed: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected all existing connections deleted, got %v", connectionIDs(list))
+	}
+}
+
+func TestConnectionService_MoveConnectionsToGroup(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	var created []Connection
+	for _, name := range []string{"a", "b", "c"} {
+		c, err := svc.CreateConnection(ctx, name, "sqlite", "cred", "", "")
+		if err != nil {
+			t.Fatalf("CreateConnection(%s) failed: %v", name, err)
+		}
+		created = append(created, c)
+	}
+
+	moved, err := svc.MoveConnectionsToGroup(ctx, []string{created[0].ID, created[2].ID}, " Production ")
+	if err != nil {
+		t.Fatalf("MoveConnectionsToGroup failed: %v", err)
+	}
+	if len(moved) != 2 {
+		t.Fatalf("moved %d connection(s); want 2", len(moved))
+	}
+	for _, c := range moved {
+		if c.Group != "Production" {
+			t.Errorf("moved connection %s has group %q; want %q", c.ID, c.Group, "Production")
+		}
+	}
+
+	groups := map[string]string{}
+	list, err := svc.ListConnections(ctx)
+	if err != nil {
+		t.Fatalf("ListConnections failed: %v", err)
+	}
+	for _, c := range list {
+		groups[c.ID] = c.Group
+	}
+	want := map[string]string{created[0].ID: "Production", created[1].ID: "", created[2].ID: "Production"}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v; want %v", groups, want)
+	}
+
+	// an update must not drop the group
+	updated, err := svc.UpdateConnection(ctx, created[0].ID, "a2", "cred", "", "")
+	if err != nil {
+		t.Fatalf("UpdateConnection failed: %v", err)
+	}
+	if updated.Group != "Production" {
+		t.Errorf("UpdateConnection returned group %q; want %q", updated.Group, "Production")
+	}
+
+	// an empty group ungroups
+	if _, err := svc.MoveConnectionsToGroup(ctx, []string{created[0].ID}, ""); err != nil {
+		t.Fatalf("MoveConnectionsToGroup(ungroup) failed: %v", err)
+	}
+	got, err := svc.GetConnection(ctx, created[0].ID)
+	if err != nil {
+		t.Fatalf("GetConnection failed: %v", err)
+	}
+	if got.Group != "" {
+		t.Errorf("group after ungrouping = %q; want empty", got.Group)
+	}
+}
+
+func TestConnectionService_MoveConnectionsToGroup_SkipsUnknownIDs(t *testing.T) {
+	svc := newIsolatedConnectionService(t)
+	ctx := context.Background()
+
+	c, err := svc.CreateConnection(ctx, "a", "sqlite", "cred", "", "")
+	if err != nil {
+		t.Fatalf("CreateConnection failed: %v", err)
+	}
+
+	moved, err := svc.MoveConnectionsToGroup(ctx, []string{"does-not-exist", c.ID, "", c.ID}, "staging")
+	if err != nil {
+		t.Fatalf("MoveConnectionsToGroup failed: %v", err)
+	}
+	if got := connectionIDs(moved); !reflect.DeepEqual(got, []string{c.ID}) {
+		t.Errorf("moved = %v; want [%s]", got, c.ID)
+	}
+
+	moved, err = svc.MoveConnectionsToGroup(ctx, []string{"does-not-exist"}, "staging")
+	if err != nil {
+		t.Fatalf("MoveConnectionsToGroup(unknown only) failed: %v", err)
+	}
+	if len(moved) != 0 {
+		t.Errorf("expected nothing moved, got %v", connectionIDs(moved))
+	}
+}
@@ -27,6 +27,9 @@ const (
 	defaultDBFile = "credentials.db"
 )
 
+// ErrSecretNotFound is returned when no secret is stored under a key.
+var ErrSecretNotFound = errors.New("secret not found")
+
 // CredManager provides a credential store backed by the OS keyring when
 // available (Keychain on macOS, Credential Manager on Windows, libsecret /
 // KWallet on Linux). When the keyring is not usable – headless servers,
@@ -159,7 +162,66 @@ func (c *CredManager) Get(key string) (string, error) {
 	if ok {
 		return s, nil
 	}
-	return "", errors.New("secret not found")
+	return "", ErrSecretNotFound
+}
+
+// Rotate replaces the secret stored under key with newSecret. Unlike Store it
+// never creates an entry: it returns ErrSecretNotFound when key is not already
+// stored. The SQLite fallback checks and replaces inside one transaction; the
+// keyring offers no transactions, so there the existence check and the write
+// are separate calls.
+func (c *CredManager) Rotate(key, newSecret string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	if c.useKeyring {
+		if _, err := keyringGet(serviceName, key); err != nil {
+			if errors.Is(err, keyring.ErrNotFound) {
+				return ErrSecretNotFound
+			}
+			return err
+		}
+		return keyringSet(serviceName, key, newSecret)
+	}
+	if c.db != nil {
+		rotated, err := c.rotateDB(key, newSecret)
+		if err != nil {
+			return err
+		}
+		if rotated {
+			return nil
+		}
+		// not in the db; it may live in the in-memory map if an earlier
+		// Store could not write to the database
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.fallback[key]; !ok {
+		return ErrSecretNotFound
+	}
+	c.fallback[key] = newSecret
+	return nil
+}
+
+// rotateDB updates key in the SQLite fallback, reporting whether it existed.
+func (c *CredManager) rotateDB(key, newSecret string) (bool, error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	var exists int
+	err = tx.QueryRow(`SELECT 1 FROM credentials WHERE key = ?`, key).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(`UPDATE credentials SET secret = ? WHERE key = ?`, newSecret, key); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
 }
 
 // Delete removes a secret. Only the active backend is consulted.
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	keyring "github.com/zalando/go-keyring"
 )
 
 // fakeKeyring is an in-process keyring backed by a plain map. It is used to
@@ -35,7 +37,7 @@ func (f *fakeKeyring) get(service, key string) (string, error) {
 	}
 	v, ok := f.data[service+"/"+key]
 	if !ok {
-		return "", errors.New("not found")
+		return "", keyring.ErrNotFound
 	}
 	return v, nil
 }
@@ -233,6 +235,72 @@ func TestStoreGetDelete_Memory(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Tests: Rotate
+// ---------------------------------------------------------------------------
+
+// rotateBackends lists a setup for each backend so Rotate is exercised
+// against all three.
+var rotateBackends = []struct {
+	backend   string
+	available bool
+	path      func(t *testing.T) string
+}{
+	{"keyring", true, tempDB},
+	{"sqlite", false, tempDB},
+	{"memory", false, func(*testing.T) string { return "/proc/impossible/path/creds.db" }},
+}
+
+func TestRotate_Existing(t *testing.T) {
+	for _, tt := range rotateBackends {
+		t.Run(tt.backend, func(t *testing.T) {
+			restore := installFake(newFake(tt.available))
+			defer restore()
+			cm := NewWithPath(tt.path(t))
+			defer cm.Close()
+			if cm.Backend() != tt.backend {
+				t.Fatalf("expected backend=%s, got %q", tt.backend, cm.Backend())
+			}
+
+			if err := cm.Store("conn", "old"); err != nil {
+				t.Fatalf("Store: %v", err)
+			}
+			if err := cm.Rotate("conn", "new"); err != nil {
+				t.Fatalf("Rotate: %v", err)
+			}
+			got, err := cm.Get("conn")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if got != "new" {
+				t.Fatalf("Get after Rotate returned %q; want %q", got, "new")
+			}
+		})
+	}
+}
+
+func TestRotate_Missing(t *testing.T) {
+	for _, tt := range rotateBackends {
+		t.Run(tt.backend, func(t *testing.T) {
+			restore := installFake(newFake(tt.available))
+			defer restore()
+			cm := NewWithPath(tt.path(t))
+			defer cm.Close()
+
+			if err := cm.Rotate("absent", "new"); !errors.Is(err, ErrSecretNotFound) {
+				t.Fatalf("Rotate on missing key: got %v; want ErrSecretNotFound", err)
+			}
+			// the failed rotate must not have created the entry
+			if _, err := cm.Get("absent"); err == nil {
+				t.Fatal("Rotate on missing key should not store a secret")
+			}
+			if err := cm.Rotate("", "v"); err == nil {
+				t.Fatal("Rotate with empty key should return error")
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Tests: input validation
 // ---------------------------------------------------------------------------
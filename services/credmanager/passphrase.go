@@ -0,0 +1,99 @@
+package credmanager
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrWrongPassphrase is returned by DecryptWithPassphrase when the blob does
+// not authenticate under the given passphrase: either the passphrase is wrong
+// or the blob was modified.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// passphraseMagic prefixes every sealed blob so the format can change later
+// without guessing.
+var passphraseMagic = []byte("QBX1")
+
+// scrypt cost parameters (the values recommended for interactive logins)
+// and the sizes of the random salt and AES-256 key.
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	passphraseKey = 32
+	saltSize      = 16
+)
+
+// EncryptWithPassphrase seals plaintext with AES-256-GCM under a key derived
+// from passphrase with scrypt.  The result is an opaque base64 string holding
+// the format marker, salt, nonce and ciphertext, safe to write to a file that
+// may leave the machine (e.g. an export containing credentials).
+func EncryptWithPassphrase(plaintext []byte, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", errors.New("passphrase is required")
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	gcm, err := passphraseAEAD(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	out := append(append(append([]byte{}, passphraseMagic...), salt...), nonce...)
+	// the header is authenticated too, so it cannot be swapped undetected
+	out = gcm.Seal(out, nonce, plaintext, out)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// DecryptWithPassphrase reverses EncryptWithPassphrase.  A blob that is not
+// in the expected format yields a descriptive error; one that fails to
+// authenticate yields ErrWrongPassphrase.
+func DecryptWithPassphrase(blob, passphrase string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(blob)
+	if err != nil || !bytes.HasPrefix(raw, passphraseMagic) {
+		return nil, errors.New("not an encrypted querybox blob")
+	}
+	header := len(passphraseMagic) + saltSize
+	if len(raw) < header {
+		return nil, errors.New("encrypted blob is truncated")
+	}
+	gcm, err := passphraseAEAD(passphrase, raw[len(passphraseMagic):header])
+	if err != nil {
+		return nil, err
+	}
+	header += gcm.NonceSize()
+	if len(raw) < header+gcm.Overhead() {
+		return nil, errors.New("encrypted blob is truncated")
+	}
+	plaintext, err := gcm.Open(nil, raw[header-gcm.NonceSize():header], raw[header:], raw[:header])
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// passphraseAEAD derives the AES-256 key for passphrase and salt and wraps
+// it in GCM.
+func passphraseAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, passphraseKey)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
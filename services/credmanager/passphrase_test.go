@@ -0,0 +1,69 @@
+package credmanager
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// exportDocument stands in for a connection export: connection metadata with
+// its credential blobs inlined.
+const exportDocument = `{"version":1,"connections":[{"name":"prod","driver_type":"postgresql","credential":"{\"form\":\"basic\",\"values\":{\"host\":\"db\",\"password\":\"s3cret\"}}"}]}`
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	blob, err := EncryptWithPassphrase([]byte(exportDocument), "correct horse")
+	if err != nil {
+		t.Fatalf("EncryptWithPassphrase: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(blob)
+	if err != nil {
+		t.Fatalf("blob is not base64: %v", err)
+	}
+	if strings.Contains(string(raw), "s3cret") || strings.Contains(string(raw), "prod") {
+		t.Fatal("blob leaks plaintext")
+	}
+
+	got, err := DecryptWithPassphrase(blob, "correct horse")
+	if err != nil {
+		t.Fatalf("DecryptWithPassphrase: %v", err)
+	}
+	if string(got) != exportDocument {
+		t.Errorf("round trip = %q, want %q", got, exportDocument)
+	}
+
+	// a fresh salt and nonce make every blob distinct
+	again, _ := EncryptWithPassphrase([]byte(exportDocument), "correct horse")
+	if again == blob {
+		t.Error("encrypting twice produced the same blob")
+	}
+}
+
+func TestPassphraseWrongPassphrase(t *testing.T) {
+	blob, err := EncryptWithPassphrase([]byte(exportDocument), "correct horse")
+	if err != nil {
+		t.Fatalf("EncryptWithPassphrase: %v", err)
+	}
+	if _, err := DecryptWithPassphrase(blob, "battery staple"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase err = %v, want ErrWrongPassphrase", err)
+	}
+
+	// flipping a ciphertext byte is caught the same way
+	raw, _ := base64.StdEncoding.DecodeString(blob)
+	raw[len(raw)-1] ^= 0xff
+	if _, err := DecryptWithPassphrase(base64.StdEncoding.EncodeToString(raw), "correct horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("tampered blob err = %v, want ErrWrongPassphrase", err)
+	}
+}
+
+func TestPassphraseRejectsMalformedBlob(t *testing.T) {
+	for _, blob := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte(exportDocument)), base64.StdEncoding.EncodeToString([]byte("QBX1abc"))} {
+		_, err := DecryptWithPassphrase(blob, "x")
+		if err == nil || errors.Is(err, ErrWrongPassphrase) {
+			t.Errorf("DecryptWithPassphrase(%q) err = %v, want a format error", blob, err)
+		}
+	}
+	if _, err := EncryptWithPassphrase([]byte("{}"), ""); err == nil {
+		t.Error("expected an empty passphrase to be rejected")
+	}
+}
@@ -7,6 +7,9 @@ type CredentialStore interface {
 	Store(key, secret string) error
 	Get(key string) (string, error)
 	Delete(key string) error
+	// Rotate replaces an existing secret and fails with ErrSecretNotFound
+	// rather than creating one.
+	Rotate(key, newSecret string) error
 }
 
 // Verify CredManager implements CredentialStore at compile time.
@@ -22,6 +22,10 @@ const (
 	// EventConnectionDeleted is emitted after a connection is successfully removed.
 	EventConnectionDeleted = "connection:deleted"
 
+	// EventConnectionsDeleted is emitted once after DeleteConnections removes
+	// one or more connections, instead of one EventConnectionDeleted each.
+	EventConnectionsDeleted = "connection:deleted-batch"
+
 	// EventMenuLogsToggled is emitted by the native menu to request the frontend toggle the logs panel.
 	EventMenuLogsToggled = "menu:logs-toggled"
 
@@ -37,6 +41,15 @@ const (
 	// EventPluginsReady is emitted by the plugin manager once the initial async
 	// scan has completed and ListPlugins() returns a populated result.
 	EventPluginsReady = "plugins:ready"
+
+	// EventTreeInvalidate is emitted by the plugin manager after a tree action
+	// that changes the schema (create/drop database or table) succeeds, so the
+	// frontend discards and refetches that connection's tree.
+	EventTreeInvalidate = "tree:invalidate"
+
+	// EventConnectionStatus is emitted when a connection's health status is
+	// recorded, carrying the ConnectionStatus.
+	EventConnectionStatus = "connection:status-changed"
 )
 
 // LogLevel represents the severity of a log entry.
@@ -73,6 +86,17 @@ type ConnectionDeletedEvent struct {
 	ID string `json:"id"`
 }
 
+// ConnectionsDeletedEvent is the payload emitted on EventConnectionsDeleted.
+type ConnectionsDeletedEvent struct {
+	IDs []string `json:"ids"`
+}
+
+// TreeInvalidateEvent is the payload emitted on EventTreeInvalidate.
+type TreeInvalidateEvent struct {
+	ConnectionID string `json:"connection_id"`
+	ActionType   string `json:"action_type"`
+}
+
 // EditConnectionWindowOpenedEvent is the payload emitted on EventEditConnectionWindowOpened.
 type EditConnectionWindowOpenedEvent struct {
 	ID string `json:"id"`
@@ -97,17 +121,20 @@ func (w *WailsEmitter) EmitEvent(name string, data interface{}) {
 	}
 }
 
-// emitLog is a nil-safe helper that emits an EventAppLog event on the Wails app.
-// If app is nil the call is a no-op so services remain functional in tests.
+// emitLog records a log entry in the shared buffer (see RecentLogs) and emits
+// it as an EventAppLog event on the Wails app. If app is nil only the buffer
+// is updated so services remain functional in tests.
 func emitLog(app *application.App, level LogLevel, message string) {
-	if app == nil {
-		return
-	}
-	app.Event.Emit(EventAppLog, LogEntry{
+	entry := LogEntry{
 		Level:     level,
 		Message:   message,
 		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
-	})
+	}
+	RecordLog(entry)
+	if app == nil {
+		return
+	}
+	app.Event.Emit(EventAppLog, entry)
 }
 
 // emitConnectionCreated emits EventConnectionCreated with the new connection as payload.
@@ -133,3 +160,19 @@ func emitConnectionDeleted(app *application.App, id string) {
 	}
 	app.Event.Emit(EventConnectionDeleted, ConnectionDeletedEvent{ID: id})
 }
+
+// emitConnectionStatus emits EventConnectionStatus with the recorded status.
+func emitConnectionStatus(app *application.App, st ConnectionStatus) {
+	if app == nil {
+		return
+	}
+	app.Event.Emit(EventConnectionStatus, st)
+}
+
+// emitConnectionsDeleted emits EventConnectionsDeleted with the removed connections' IDs.
+func emitConnectionsDeleted(app *application.App, ids []string) {
+	if app == nil {
+		return
+	}
+	app.Event.Emit(EventConnectionsDeleted, ConnectionsDeletedEvent{IDs: ids})
+}
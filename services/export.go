@@ -0,0 +1,45 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// exportFilter describes the native save-dialog filter used for one result
+// export format.
+type exportFilter struct {
+	Name      string // label shown in the dialog, e.g. "CSV Files"
+	Pattern   string // dialog glob, e.g. "*.csv"
+	Extension string // extension appended when the user omits one
+}
+
+// exportFilters maps the export formats accepted by SaveResults to their
+// dialog filters. Format keys are lower-case.
+var exportFilters = map[string]exportFilter{
+	"csv":  {Name: "CSV Files", Pattern: "*.csv", Extension: ".csv"},
+	"json": {Name: "JSON Files", Pattern: "*.json", Extension: ".json"},
+}
+
+// exportFilterFor returns the dialog filter for format or an error when the
+// format is not supported.
+func exportFilterFor(format string) (exportFilter, error) {
+	f, ok := exportFilters[strings.ToLower(strings.TrimSpace(format))]
+	if !ok {
+		return exportFilter{}, fmt.Errorf("unsupported export format %q", format)
+	}
+	return f, nil
+}
+
+// writeExportFile writes content to path, appending the filter's extension
+// when the chosen file name has none. It returns the path actually written.
+func writeExportFile(path string, filter exportFilter, content string) (string, error) {
+	if filepath.Ext(path) == "" {
+		path += filter.Extension
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+	return path, nil
+}
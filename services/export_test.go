@@ -0,0 +1,62 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportFilterFor(t *testing.T) {
+	tests := []struct {
+		format  string
+		pattern string
+		wantErr bool
+	}{
+		{"csv", "*.csv", false},
+		{"JSON", "*.json", false},
+		{" csv ", "*.csv", false},
+		{"xlsx", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		f, err := exportFilterFor(tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("exportFilterFor(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			continue
+		}
+		if f.Pattern != tt.pattern {
+			t.Errorf("exportFilterFor(%q) pattern = %q, want %q", tt.format, f.Pattern, tt.pattern)
+		}
+	}
+}
+
+func TestWriteExportFile(t *testing.T) {
+	dir := t.TempDir()
+	filter, _ := exportFilterFor("csv")
+
+	// missing extension is appended
+	got, err := writeExportFile(filepath.Join(dir, "results"), filter, "a,b\n1,2\n")
+	if err != nil {
+		t.Fatalf("writeExportFile: %v", err)
+	}
+	if want := filepath.Join(dir, "results.csv"); got != want {
+		t.Errorf("path = %q, want %q", got, want)
+	}
+	if b, err := os.ReadFile(got); err != nil || string(b) != "a,b\n1,2\n" {
+		t.Errorf("unexpected file content %q (err %v)", b, err)
+	}
+
+	// an explicit extension chosen by the user is kept
+	got, err = writeExportFile(filepath.Join(dir, "out.txt"), filter, "x")
+	if err != nil {
+		t.Fatalf("writeExportFile: %v", err)
+	}
+	if filepath.Ext(got) != ".txt" {
+		t.Errorf("user extension replaced: %q", got)
+	}
+
+	// write errors are reported
+	if _, err := writeExportFile(filepath.Join(dir, "missing", "x.csv"), filter, "x"); err == nil {
+		t.Errorf("expected error writing into a missing directory")
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileFilter is one entry of a native open-file dialog's type filter.
+type FileFilter struct {
+	Name    string `json:"name"`    // label shown in the dialog, e.g. "PEM Certificates"
+	Pattern string `json:"pattern"` // ";"-separated globs, e.g. "*.pem;*.crt"
+}
+
+// allFilesFilter is appended to every open dialog so users can still pick a
+// file with an unexpected extension.
+var allFilesFilter = FileFilter{Name: "All Files", Pattern: "*"}
+
+// sqliteFileFilters are the filters used by OpenFileDialog.
+var sqliteFileFilters = []FileFilter{
+	{Name: "SQLite Database", Pattern: "*.db;*.sqlite;*.sqlite3"},
+}
+
+// fileDialogFilters normalises caller-supplied filters for the open dialog:
+// globs are trimmed, a missing name defaults to the pattern and the "All
+// Files" filter is appended unless one matching "*" is already present. A
+// filter without a pattern is an error.
+func fileDialogFilters(filters []FileFilter) ([]FileFilter, error) {
+	out := make([]FileFilter, 0, len(filters)+1)
+	hasAll := false
+	for _, f := range filters {
+		var globs []string
+		for _, g := range strings.Split(f.Pattern, ";") {
+			if g = strings.TrimSpace(g); g != "" {
+				globs = append(globs, g)
+			}
+		}
+		if len(globs) == 0 {
+			return nil, fmt.Errorf("file filter %q has no pattern", f.Name)
+		}
+		pattern := strings.Join(globs, ";")
+		name := strings.TrimSpace(f.Name)
+		if name == "" {
+			name = pattern
+		}
+		if pattern == "*" {
+			hasAll = true
+		}
+		out = append(out, FileFilter{Name: name, Pattern: pattern})
+	}
+	if !hasAll {
+		out = append(out, allFilesFilter)
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFileDialogFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []FileFilter
+		want    []FileFilter
+		wantErr bool
+	}{
+		{
+			name: "no filters",
+			want: []FileFilter{allFilesFilter},
+		},
+		{
+			name:    "sqlite",
+			filters: sqliteFileFilters,
+			want:    []FileFilter{{Name: "SQLite Database", Pattern: "*.db;*.sqlite;*.sqlite3"}, allFilesFilter},
+		},
+		{
+			name:    "globs trimmed and name defaulted",
+			filters: []FileFilter{{Pattern: " *.pem ; *.crt ;"}},
+			want:    []FileFilter{{Name: "*.pem;*.crt", Pattern: "*.pem;*.crt"}, allFilesFilter},
+		},
+		{
+			name:    "existing catch-all is not duplicated",
+			filters: []FileFilter{{Name: "CSV", Pattern: "*.csv"}, {Name: "Anything", Pattern: "*"}},
+			want:    []FileFilter{{Name: "CSV", Pattern: "*.csv"}, {Name: "Anything", Pattern: "*"}},
+		},
+		{
+			name:    "empty pattern",
+			filters: []FileFilter{{Name: "CSV", Pattern: " ; "}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fileDialogFilters(tt.filters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fileDialogFilters error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fileDialogFilters = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// defaultLogFileMaxSize is the size at which the log file is rotated.
+const defaultLogFileMaxSize = 5 << 20
+
+// logLevelRank orders levels for LogFile's minimum-level filter. Unknown
+// levels rank with info.
+var logLevelRank = map[LogLevel]int{
+	LogLevelDebug: 0,
+	LogLevelInfo:  1,
+	LogLevelWarn:  2,
+	LogLevelError: 3,
+}
+
+// LogFile appends log entries to a file as JSON lines (one LogEntry object
+// per line) so users can attach the log to bug reports. When a write would
+// take the file past maxSize it is renamed to "<path>.1", replacing any
+// previous backup, and a new file is started; at most about twice maxSize
+// is kept on disk. It is safe for concurrent use.
+type LogFile struct {
+	mu       sync.Mutex
+	path     string
+	minLevel LogLevel
+	maxSize  int64
+	f        *os.File
+	size     int64
+}
+
+// OpenLogFile opens (creating it and its directory if needed) the log file
+// at path, appending to any existing content. Entries below minLevel are
+// dropped; an empty minLevel keeps everything. A non-positive maxSize uses
+// the 5 MiB default.
+func OpenLogFile(path string, minLevel LogLevel, maxSize int64) (*LogFile, error) {
+	if maxSize <= 0 {
+		maxSize = defaultLogFileMaxSize
+	}
+	// log entries can include queries and connection details, so the
+	// directory and file are readable by the user only
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	l := &LogFile{path: path, minLevel: minLevel, maxSize: maxSize}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *LogFile) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+// Path returns the file the entries are written to.
+func (l *LogFile) Path() string { return l.path }
+
+// Write appends e unless its level is below the minimum.
+func (l *LogFile) Write(e LogEntry) error {
+	if l.minLevel != "" && rankOf(e.Level) < rankOf(l.minLevel) {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return fmt.Errorf("log file %s is closed", l.path)
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.f.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// rotate moves the current file to "<path>.1" and starts a new one.
+func (l *LogFile) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	l.f = nil
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		// keep appending to the current file rather than losing entries
+		if oerr := l.open(); oerr != nil {
+			return oerr
+		}
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	return l.open()
+}
+
+// Close closes the file; later writes fail.
+func (l *LogFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+func rankOf(level LogLevel) int {
+	if r, ok := logLevelRank[level]; ok {
+		return r
+	}
+	return logLevelRank[LogLevelInfo]
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func readLogLines(t *testing.T, path string) []LogEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	var out []LogEntry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e LogEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("line %q is not a JSON LogEntry: %v", sc.Text(), err)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func TestLogFileWritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "querybox.log")
+	l, err := OpenLogFile(path, LogLevelInfo, 0)
+	if err != nil {
+		t.Fatalf("OpenLogFile: %v", err)
+	}
+	entries := []LogEntry{
+		{Level: LogLevelDebug, Message: "dropped", Timestamp: "2026-01-01T00:00:00Z"},
+		{Level: LogLevelInfo, Message: "ExecPlugin: running query", Timestamp: "2026-01-01T00:00:01Z"},
+		{Level: LogLevelError, Message: "multi\nline \"quoted\"", Timestamp: "2026-01-01T00:00:02Z"},
+	}
+	for _, e := range entries {
+		if err := l.Write(e); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got := readLogLines(t, path)
+	if len(got) != 2 || got[0] != entries[1] || got[1] != entries[2] {
+		t.Errorf("logged entries = %+v; want the info and error entries", got)
+	}
+	b, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(b), `{"level":"info","message":"ExecPlugin: running query","timestamp":"2026-01-01T00:00:01Z"}`+"\n") {
+		t.Errorf("unexpected line format: %q", b)
+	}
+
+	if err := l.Write(entries[1]); err == nil {
+		t.Error("expected an error writing to a closed log file")
+	}
+
+	if runtime.GOOS != "windows" {
+		for p, want := range map[string]os.FileMode{path: 0o600, filepath.Dir(path): 0o700} {
+			info, err := os.Stat(p)
+			if err != nil {
+				t.Fatalf("stat %s: %v", p, err)
+			}
+			if got := info.Mode().Perm(); got != want {
+				t.Errorf("%s mode = %o, want %o", p, got, want)
+			}
+		}
+	}
+}
+
+func TestLogFileRotatesAtThreshold(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "querybox.log")
+	e := LogEntry{Level: LogLevelInfo, Message: "0123456789", Timestamp: "2026-01-01T00:00:00Z"}
+	line, _ := json.Marshal(e)
+	lineSize := int64(len(line) + 1)
+
+	// room for exactly three lines per file
+	l, err := OpenLogFile(path, "", 3*lineSize)
+	if err != nil {
+		t.Fatalf("OpenLogFile: %v", err)
+	}
+	defer l.Close()
+	for i := 0; i < 3; i++ {
+		if err := l.Write(e); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("rotated before reaching the threshold (stat err %v)", err)
+	}
+
+	if err := l.Write(e); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n := len(readLogLines(t, path+".1")); n != 3 {
+		t.Errorf("backup holds %d lines; want 3", n)
+	}
+	if n := len(readLogLines(t, path)); n != 1 {
+		t.Errorf("new file holds %d lines; want 1", n)
+	}
+
+	// reopening appends and keeps counting towards the threshold
+	l.Close()
+	l, err = OpenLogFile(path, "", 3*lineSize)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		l.Write(e)
+	}
+	if n := len(readLogLines(t, path)); n != 1 {
+		t.Errorf("after second rotation the file holds %d lines; want 1", n)
+	}
+	if n := len(readLogLines(t, path+".1")); n != 3 {
+		t.Errorf("after second rotation the backup holds %d lines; want 3", n)
+	}
+}
+
+func TestSetFileLoggingReceivesRecordedLogs(t *testing.T) {
+	svc := NewLogService()
+	path := filepath.Join(t.TempDir(), "app.log")
+	if _, err := svc.SetFileLogging(path, "bogus"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	got, err := svc.SetFileLogging(path, LogLevelWarn)
+	if err != nil {
+		t.Fatalf("SetFileLogging: %v", err)
+	}
+	defer svc.DisableFileLogging()
+	if got != path || svc.LogFilePath() != path {
+		t.Errorf("path = %q, LogFilePath = %q; want %q", got, svc.LogFilePath(), path)
+	}
+
+	emitLog(nil, LogLevelInfo, "below threshold")
+	emitLog(nil, LogLevelWarn, "Test: written")
+	if err := svc.DisableFileLogging(); err != nil {
+		t.Fatalf("DisableFileLogging: %v", err)
+	}
+	emitLog(nil, LogLevelError, "after disable")
+
+	lines := readLogLines(t, path)
+	if len(lines) != 1 || lines[0].Message != "Test: written" {
+		t.Errorf("file entries = %+v; want only the warn entry", lines)
+	}
+	if svc.LogFilePath() != "" {
+		t.Errorf("LogFilePath after disable = %q", svc.LogFilePath())
+	}
+}
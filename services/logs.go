@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// defaultLogBufferSize is the number of log entries retained for RecentLogs.
+const defaultLogBufferSize = 1000
+
+// LogBuffer is a fixed-capacity ring buffer of LogEntry values. Once full,
+// each new entry evicts the oldest one. It is safe for concurrent use.
+type LogBuffer struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int  // index the next entry is written to
+	full    bool // true once the buffer has wrapped at least once
+}
+
+// NewLogBuffer returns an empty buffer holding at most capacity entries.
+// A non-positive capacity is treated as 1.
+func NewLogBuffer(capacity int) *LogBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LogBuffer{entries: make([]LogEntry, capacity)}
+}
+
+// Append records e, evicting the oldest entry when the buffer is full.
+func (b *LogBuffer) Append(e LogEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Recent returns up to limit of the newest entries in chronological order
+// (oldest first). A non-positive limit returns everything retained.
+func (b *LogBuffer) Recent(limit int) []LogEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	size := b.next
+	if b.full {
+		size = len(b.entries)
+	}
+	if limit <= 0 || limit > size {
+		limit = size
+	}
+	out := make([]LogEntry, limit)
+	// the newest entry sits just before next; walk back limit entries
+	start := (b.next - limit + len(b.entries)) % len(b.entries)
+	for i := 0; i < limit; i++ {
+		out[i] = b.entries[(start+i)%len(b.entries)]
+	}
+	return out
+}
+
+// appLogs retains every entry emitted on EventAppLog by any service so the
+// logs panel can show history after it is reopened.
+var appLogs = NewLogBuffer(defaultLogBufferSize)
+
+// fileLog is the optional file sink configured through
+// LogService.SetFileLogging; nil when file logging is off.
+var (
+	fileLogMu sync.Mutex
+	fileLog   *LogFile
+)
+
+// RecordLog appends e to the shared application log buffer and, when file
+// logging is enabled, to the log file. Services outside this package (e.g.
+// the plugin manager) call it alongside emitting EventAppLog.
+func RecordLog(e LogEntry) {
+	appLogs.Append(e)
+	fileLogMu.Lock()
+	defer fileLogMu.Unlock()
+	if fileLog != nil {
+		_ = fileLog.Write(e) // best-effort; the buffer still has the entry
+	}
+}
+
+// DefaultLogFilePath is where SetFileLogging writes when no path is given.
+func DefaultLogFilePath() string {
+	return filepath.Join(dataDir(), "logs", "querybox.log")
+}
+
+// LogService exposes the retained application log to the frontend.
+type LogService struct{}
+
+// NewLogService creates a LogService backed by the shared log buffer.
+func NewLogService() *LogService {
+	return &LogService{}
+}
+
+// RecentLogs returns up to limit of the most recent log entries, oldest
+// first. A non-positive limit returns the whole buffer.
+func (s *LogService) RecentLogs(limit int) []LogEntry {
+	return appLogs.Recent(limit)
+}
+
+// SetFileLogging starts writing log entries at or above level to path as
+// JSON lines, replacing any previous log file. An empty path uses
+// DefaultLogFilePath and an empty level keeps every entry. It returns the
+// path in use.
+func (s *LogService) SetFileLogging(path string, level LogLevel) (string, error) {
+	if path == "" {
+		path = DefaultLogFilePath()
+	}
+	if _, ok := logLevelRank[level]; level != "" && !ok {
+		return "", fmt.Errorf("unknown log level %q", level)
+	}
+	l, err := OpenLogFile(path, level, defaultLogFileMaxSize)
+	if err != nil {
+		return "", err
+	}
+	fileLogMu.Lock()
+	prev := fileLog
+	fileLog = l
+	fileLogMu.Unlock()
+	if prev != nil {
+		prev.Close()
+	}
+	return path, nil
+}
+
+// DisableFileLogging stops writing to the log file and closes it.
+func (s *LogService) DisableFileLogging() error {
+	fileLogMu.Lock()
+	prev := fileLog
+	fileLog = nil
+	fileLogMu.Unlock()
+	if prev == nil {
+		return nil
+	}
+	return prev.Close()
+}
+
+// LogFilePath returns the file log entries are written to, or "" when file
+// logging is off.
+func (s *LogService) LogFilePath() string {
+	fileLogMu.Lock()
+	defer fileLogMu.Unlock()
+	if fileLog == nil {
+		return ""
+	}
+	return fileLog.Path()
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func logMessages(entries []LogEntry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Message
+	}
+	return out
+}
+
+func TestLogBufferEvictsOldest(t *testing.T) {
+	b := NewLogBuffer(3)
+	if got := b.Recent(0); len(got) != 0 {
+		t.Fatalf("empty buffer returned %v", got)
+	}
+
+	for i := 1; i <= 5; i++ {
+		b.Append(LogEntry{Level: LogLevelInfo, Message: fmt.Sprintf("m%d", i)})
+	}
+	if got, want := logMessages(b.Recent(0)), []string{"m3", "m4", "m5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Recent(0) = %v; want %v", got, want)
+	}
+	if got, want := logMessages(b.Recent(2)), []string{"m4", "m5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Recent(2) = %v; want %v", got, want)
+	}
+	if got := b.Recent(10); len(got) != 3 {
+		t.Errorf("Recent(10) returned %d entries; want capacity 3", len(got))
+	}
+}
+
+func TestLogBufferBeforeWrap(t *testing.T) {
+	b := NewLogBuffer(4)
+	b.Append(LogEntry{Message: "a"})
+	b.Append(LogEntry{Message: "b"})
+	if got, want := logMessages(b.Recent(0)), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Recent(0) = %v; want %v", got, want)
+	}
+}
+
+func TestEmitLogRecordsWithoutApp(t *testing.T) {
+	emitLog(nil, LogLevelWarn, "TestEmitLogRecordsWithoutApp: buffered")
+	got := NewLogService().RecentLogs(1)
+	if len(got) != 1 || got[0].Message != "TestEmitLogRecordsWithoutApp: buffered" || got[0].Level != LogLevelWarn {
+		t.Errorf("RecentLogs(1) = %+v", got)
+	}
+}
@@ -0,0 +1,152 @@
+package pluginmgr
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/felixdotgo/querybox/pkg/driverid"
+	_ "modernc.org/sqlite"
+)
+
+const settingsDBFile = "settings.db"
+
+// blocklist records the plugins the user has disabled. The set is cached in
+// memory and mirrored to a small SQLite table so it survives restarts and
+// rescans. When the database cannot be opened the blocklist keeps working
+// in-memory only, mirroring the CredManager fallback behaviour.
+type blocklist struct {
+	mu    sync.RWMutex
+	names map[string]struct{}
+	// db is nil when persistence is unavailable (or in tests).
+	db *sql.DB
+}
+
+// newMemoryBlocklist returns a blocklist that is never persisted.
+func newMemoryBlocklist() *blocklist {
+	return &blocklist{names: make(map[string]struct{})}
+}
+
+// settingsDBPath returns the location of the shared settings database under
+// the per-user config directory (next to connections.db).
+func settingsDBPath() (string, error) {
+	dir, err := userPluginDirFunc()
+	if err != nil || dir == "" {
+		return "", fmt.Errorf("user config dir unavailable")
+	}
+	return filepath.Join(dir, "querybox", settingsDBFile), nil
+}
+
+// openBlocklist opens (creating if necessary) the disabled_plugins table in
+// the SQLite file at dbPath and loads its contents. On any error it returns
+// an in-memory blocklist together with the error so callers can log it.
+func openBlocklist(dbPath string) (*blocklist, error) {
+	b := newMemoryBlocklist()
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+		return b, fmt.Errorf("create settings directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return b, fmt.Errorf("open settings database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+
+	create := `CREATE TABLE IF NOT EXISTS disabled_plugins (
+		name TEXT PRIMARY KEY
+	);`
+	if _, err := db.Exec(create); err != nil {
+		_ = db.Close()
+		return b, fmt.Errorf("initialize disabled_plugins schema: %w", err)
+	}
+
+	rows, err := db.Query(`SELECT name FROM disabled_plugins`)
+	if err != nil {
+		_ = db.Close()
+		return b, fmt.Errorf("load disabled plugins: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			_ = db.Close()
+			return newMemoryBlocklist(), fmt.Errorf("load disabled plugins: %w", err)
+		}
+		b.names[name] = struct{}{}
+	}
+	b.db = db
+	return b, nil
+}
+
+// Contains reports whether the plugin name is disabled. A nil blocklist
+// contains nothing so hand-built Managers in tests need not set one.
+func (b *blocklist) Contains(name string) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.names[driverid.Normalize(name)]
+	return ok
+}
+
+// Add disables name. The in-memory set is updated even if persisting fails.
+func (b *blocklist) Add(name string) error {
+	name = driverid.Normalize(name)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.names[name] = struct{}{}
+	if b.db == nil {
+		return nil
+	}
+	if _, err := b.db.Exec(`INSERT OR IGNORE INTO disabled_plugins (name) VALUES (?)`, name); err != nil {
+		return fmt.Errorf("persist disabled plugin: %w", err)
+	}
+	return nil
+}
+
+// Remove re-enables name. The in-memory set is updated even if persisting fails.
+func (b *blocklist) Remove(name string) error {
+	name = driverid.Normalize(name)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.names, name)
+	if b.db == nil {
+		return nil
+	}
+	if _, err := b.db.Exec(`DELETE FROM disabled_plugins WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("persist enabled plugin: %w", err)
+	}
+	return nil
+}
+
+// List returns the disabled plugin names in sorted order.
+func (b *blocklist) List() []string {
+	if b == nil {
+		return []string{}
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	ret := make([]string, 0, len(b.names))
+	for name := range b.names {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Close releases the underlying database, if any.
+func (b *blocklist) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.db != nil {
+		_ = b.db.Close()
+		b.db = nil
+	}
+}
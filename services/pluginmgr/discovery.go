@@ -3,7 +3,9 @@ package pluginmgr
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +13,8 @@ import (
 	"time"
 
 	"github.com/felixdotgo/querybox/pkg/driverid"
+	"github.com/felixdotgo/querybox/pkg/plugin"
+	"github.com/felixdotgo/querybox/services"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
 )
 
@@ -98,10 +102,11 @@ func populateUserDir(userDir, bundle string) {
 }
 
 // scanOnce updates the in-memory plugin registry by inspecting the folder. For
-// newly discovered executables, it will attempt to probe `plugin info` for
-// metadata. Failures are recorded in PluginInfo.LastError but do not prevent
-// discovery.
-func (m *Manager) scanOnce() {
+// newly discovered executables, and known ones whose binary changed since it
+// was last probed, it will attempt to probe `plugin info` for metadata.
+// Failures are recorded in PluginInfo.LastError but do not prevent discovery.
+// It returns the number of binaries probed.
+func (m *Manager) scanOnce() int {
 	m.scanMu.Lock()
 	defer m.scanMu.Unlock()
 
@@ -112,14 +117,27 @@ func (m *Manager) scanOnce() {
 		name   string
 		full   string
 		dirIdx int // index in m.dirs where this candidate came from
+		stat   os.FileInfo
 	}
 	var toProbe []candidate
+	dirErrs := map[string]string{}
+	var failedDirs []string
 
 	m.mu.Lock()
 	for idx, dir := range m.dirs {
 		files, err := os.ReadDir(dir)
 		if err != nil {
-			continue // missing/ unreadable dirs are simply skipped
+			// A missing directory is normal (e.g. no bundle in development)
+			// unless New failed to create it, in which case that error is
+			// kept. Anything else, typically a permissions problem, is
+			// recorded for Diagnostics and logged below.
+			if !errors.Is(err, fs.ErrNotExist) {
+				dirErrs[dir] = err.Error()
+				failedDirs = append(failedDirs, fmt.Sprintf("%s: %v", dir, err))
+			} else if prev, ok := m.dirErrors[dir]; ok {
+				dirErrs[dir] = prev
+			}
+			continue
 		}
 		for _, f := range files {
 			if f.IsDir() {
@@ -136,15 +154,28 @@ func (m *Manager) scanOnce() {
 			if !isExecutable(full) {
 				continue
 			}
+			if m.disabled.Contains(name) {
+				// user-disabled plugins are left out of the registry entirely
+				continue
+			}
+			fi, err := os.Stat(full)
+			if err != nil {
+				continue
+			}
 			found[name] = struct{}{}
 			existing, exists := m.plugins[name]
-			if !exists || existing.LastError != "" {
-				toProbe = append(toProbe, candidate{name: name, full: full, dirIdx: idx})
+			if !exists || existing.LastError != "" || existing.binaryChanged(fi) {
+				toProbe = append(toProbe, candidate{name: name, full: full, dirIdx: idx, stat: fi})
 			}
 		}
 	}
+	m.dirErrors = dirErrs
 	m.mu.Unlock()
 
+	for _, msg := range failedDirs {
+		m.emitLog(services.LogLevelError, "scanOnce: cannot read plugin directory "+msg)
+	}
+
 	// probe metadata concurrently (same as before)
 	type result struct {
 		name string
@@ -159,7 +190,8 @@ func (m *Manager) scanOnce() {
 			// Use normalized `name` (no extension) for ID; keep the original
 			// filename as a fallback for display if plugin metadata doesn't
 			// provide a nicer human name.
-			info := PluginInfo{ID: c.name, Name: c.name, Path: c.full, Running: false}
+			info := PluginInfo{ID: c.name, Name: c.name, Path: c.full, Running: false,
+				binModTime: c.stat.ModTime(), binSize: c.stat.Size()}
 			meta, err := probeInfoFunc(c.full)
 			if err != nil && c.dirIdx == 0 && len(m.dirs) > 1 {
 				// primary directory probe failed; try fallback bundle entry if present
@@ -174,6 +206,7 @@ func (m *Manager) scanOnce() {
 			}
 			if err != nil {
 				info.LastError = err.Error()
+				info.rejected = failsHandshake(err)
 			} else {
 				if meta.Name != "" {
 					info.Name = meta.Name
@@ -202,17 +235,21 @@ func (m *Manager) scanOnce() {
 	wg.Wait()
 	close(resCh)
 
-	// update map and prune missing entries
+	// update map and prune missing entries; cached auth forms of re-probed
+	// or removed plugins are dropped with them
 	m.mu.Lock()
 	for r := range resCh {
 		m.plugins[r.name] = r.info
+		delete(m.authForms, r.name)
 	}
 	for name := range m.plugins {
 		if _, ok := found[name]; !ok {
 			delete(m.plugins, name)
+			delete(m.authForms, name)
 		}
 	}
 	m.mu.Unlock()
+	return len(toProbe)
 }
 
 // isExecutable checks whether the given path looks like an executable file.
@@ -232,6 +269,24 @@ func isExecutable(path string) bool {
 	return mode&0111 != 0
 }
 
+// errNotQueryboxPlugin is reported for executables whose `info` output is a
+// JSON object without the plugin.Protocol handshake marker.
+var errNotQueryboxPlugin = errors.New("not a querybox plugin")
+
+// errInfoReported is wrapped around the message of a plugin whose `info`
+// command failed with a CLIError envelope on stdout.  Such a binary speaks
+// the protocol and merely could not describe itself, so it is the one probe
+// failure that does not fail the handshake.
+var errInfoReported = errors.New("probe info failed")
+
+// failsHandshake reports whether a probe error means the executable is not
+// known to be a querybox plugin: anything other than an error the plugin
+// reported itself.  Such binaries are left out of ListPlugins and never run
+// as drivers; Diagnostics still shows their LastError.
+func failsHandshake(err error) bool {
+	return !errors.Is(err, errInfoReported)
+}
+
 // probeInfo executes `binary info` and decodes the JSON InfoResponse. If the
 // plugin doesn't implement `info` the call will error and we return that error.
 //
@@ -246,62 +301,94 @@ func probeInfo(fullpath string) (PluginInfo, error) {
 	hideWindow(cmd)
 	out, err := cmd.Output()
 	if err != nil {
+		if msg, ok := plugin.ParseCLIError(out); ok {
+			return PluginInfo{}, fmt.Errorf("%w: %s", errInfoReported, msg)
+		}
 		return PluginInfo{}, fmt.Errorf("probe info failed: %w", err)
 	}
 
-	// Unmarshal known string fields directly. The "type" field needs
-	// special handling because newer plugins emit it as a string enum
-	// (via protojson) while older ones used a numeric value.
-	var resp struct {
-		Name        string            `json:"name"`
-		Version     string            `json:"version"`
-		Description string            `json:"description"`
-		URL         string            `json:"url"`
-		Author      string            `json:"author"`
-		Capabilities []string         `json:"capabilities"`
-		Tags        []string          `json:"tags"`
-		License     string            `json:"license"`
-		IconURL     string            `json:"icon_url"`
-		Contact     string            `json:"contact"`
-		Metadata    map[string]string `json:"metadata"`
-		Settings    map[string]string `json:"settings"`
-		// Type is decoded as json.RawMessage to handle both numeric and string enum values.
-		RawType     json.RawMessage   `json:"type"`
+	var raw map[string]interface{}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return PluginInfo{}, fmt.Errorf("probe info failed: invalid info json: %w", err)
 	}
-	if err := json.Unmarshal(out, &resp); err != nil {
-		return PluginInfo{}, fmt.Errorf("invalid info json: %w", err)
+	if p, _ := raw["protocol"].(string); p != plugin.Protocol {
+		return PluginInfo{}, errNotQueryboxPlugin
 	}
+	return probeInfoFromRaw(raw)
+}
+
+// probeInfoFromRaw converts a decoded `plugin info` payload into PluginInfo.
+// protojson emits lowerCamelCase field names (e.g. "iconUrl",
+// "exampleQueries") while older or hand-written plugins use the proto names
+// ("icon_url", "example_queries"); both are accepted.
+// The "type" field may be numeric (older plugins) or a string enum name.
+func probeInfoFromRaw(raw map[string]interface{}) (PluginInfo, error) {
+	norm := make(map[string]interface{}, len(raw)+1)
+	for k, v := range raw {
+		norm[k] = v
+	}
+	if v, ok := raw["iconUrl"]; ok {
+		if _, dup := raw["icon_url"]; !dup {
+			norm["icon_url"] = v
+		}
+	}
+	delete(norm, "iconUrl")
+	if v, ok := raw["exampleQueries"]; ok {
+		if _, dup := raw["example_queries"]; !dup {
+			norm["example_queries"] = v
+		}
+	}
+	delete(norm, "exampleQueries")
 
-	// interpret the type field from numeric or string enum
 	typ := 0
-	if len(resp.RawType) > 0 {
-		var numVal float64
-		if json.Unmarshal(resp.RawType, &numVal) == nil {
-			typ = int(numVal)
-		} else {
-			var strVal string
-			if json.Unmarshal(resp.RawType, &strVal) == nil {
-				if val, ok := pluginpb.PluginV1_Type_value[strVal]; ok {
-					typ = int(val)
-				}
-			}
+	switch v := norm["type"].(type) {
+	case float64:
+		typ = int(v)
+	case string:
+		if val, ok := pluginpb.PluginV1_Type_value[v]; ok {
+			typ = int(val)
 		}
 	}
+	delete(norm, "type")
+
+	var resp struct {
+		Name         string            `json:"name"`
+		Version      string            `json:"version"`
+		Description  string            `json:"description"`
+		URL          string            `json:"url"`
+		Author       string            `json:"author"`
+		Capabilities []string          `json:"capabilities"`
+		Tags         []string          `json:"tags"`
+		License      string            `json:"license"`
+		IconURL      string            `json:"icon_url"`
+		Contact      string            `json:"contact"`
+		Metadata     map[string]string `json:"metadata"`
+		Settings     map[string]string `json:"settings"`
+		Examples     []string          `json:"example_queries"`
+	}
+	b, err := json.Marshal(norm)
+	if err != nil {
+		return PluginInfo{}, fmt.Errorf("invalid info json: %w", err)
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return PluginInfo{}, fmt.Errorf("invalid info json: %w", err)
+	}
 
 	return PluginInfo{
-		Name:        resp.Name,
-		Type:        typ,
-		Version:     resp.Version,
-		Description: resp.Description,
-		URL:         resp.URL,
-		Author:      resp.Author,
-		Capabilities: resp.Capabilities,
-		Tags:        resp.Tags,
-		License:     resp.License,
-		IconURL:     resp.IconURL,
-		Contact:     resp.Contact,
-		Metadata:    resp.Metadata,
-		Settings:    resp.Settings,
+		Name:           resp.Name,
+		Type:           typ,
+		Version:        resp.Version,
+		Description:    resp.Description,
+		URL:            resp.URL,
+		Author:         resp.Author,
+		Capabilities:   resp.Capabilities,
+		Tags:           resp.Tags,
+		License:        resp.License,
+		IconURL:        resp.IconURL,
+		Contact:        resp.Contact,
+		Metadata:       resp.Metadata,
+		Settings:       resp.Settings,
+		ExampleQueries: resp.Examples,
 	}, nil
 }
 
@@ -311,6 +398,7 @@ func probeInfo(fullpath string) (PluginInfo, error) {
 func (m *Manager) Rescan() error {
 	m.mu.Lock()
 	m.plugins = make(map[string]PluginInfo)
+	m.authForms = nil
 	m.mu.Unlock()
 	m.scanOnce()
 	// after a manual rescan we also fire the ready event so listeners can
@@ -319,3 +407,15 @@ func (m *Manager) Rescan() error {
 	m.emitPluginsReady()
 	return nil
 }
+
+// Reload refreshes the registry without discarding it: plugins whose binary
+// changed on disk (modification time or size) since they were probed are
+// re-probed so an in-place upgrade updates their version and description,
+// added and removed binaries are picked up, and unchanged plugins keep their
+// cached metadata. Like Rescan it fires plugins:ready when done.
+func (m *Manager) Reload() error {
+	n := m.scanOnce()
+	m.emitLog(services.LogLevelInfo, fmt.Sprintf("Reload: re-probed %d plugin(s)", n))
+	m.emitPluginsReady()
+	return nil
+}
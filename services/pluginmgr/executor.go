@@ -1,12 +1,14 @@
 package pluginmgr
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
 	"github.com/felixdotgo/querybox/services"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // PluginExecutor abstracts the subprocess execution of plugin commands.
@@ -30,7 +33,97 @@ var _ PluginExecutor = (*Manager)(nil)
 // RunCommand is the public implementation of PluginExecutor. It delegates to
 // the internal runPluginCommand with a fixed caller label.
 func (m *Manager) RunCommand(name, command string, timeout time.Duration, req []byte) ([]byte, error) {
-	return m.runPluginCommand("RunCommand", name, command, timeout, req)
+	return m.runPluginCommand(context.Background(), "RunCommand", name, command, timeout, req, nil)
+}
+
+// defaultMaxConcurrentExecs is the default number of ExecPlugin /
+// GetConnectionTree subprocesses allowed to run concurrently.
+func defaultMaxConcurrentExecs() int {
+	return runtime.NumCPU()
+}
+
+// SetMaxConcurrentExecs changes how many ExecPlugin/GetConnectionTree
+// subprocesses may run at once; n <= 0 removes the limit. Calls already
+// holding a slot are unaffected.
+func (m *Manager) SetMaxConcurrentExecs(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n <= 0 {
+		m.execSlots = nil
+		return
+	}
+	m.execSlots = make(chan struct{}, n)
+}
+
+// RetryPolicy configures automatic retries of transient connection failures
+// (connection refused, host unreachable, ...) in ExecPlugin and
+// TestConnection. Deterministic failures such as syntax errors are never
+// retried.
+type RetryPolicy struct {
+	// Attempts is the total number of tries including the first; values
+	// below 2 disable retries.
+	Attempts int `json:"attempts"`
+	// Backoff is the delay before the first retry; it doubles for each
+	// further retry.
+	Backoff time.Duration `json:"backoff"`
+}
+
+// SetRetryPolicy replaces the manager's retry policy. Retries are disabled
+// until this is called.
+func (m *Manager) SetRetryPolicy(p RetryPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retry = p
+}
+
+// withRetry calls op, repeating it while transient reports the outcome as a
+// transient failure and the manager's RetryPolicy allows another attempt.
+// The wait between attempts is abandoned when ctx is done, returning the
+// last outcome.
+func withRetry[T any](ctx context.Context, m *Manager, caller, name string, op func() (T, error), transient func(T, error) bool) (T, error) {
+	m.mu.Lock()
+	policy := m.retry
+	m.mu.Unlock()
+
+	delay := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		res, err := op()
+		if attempt >= policy.Attempts || !transient(res, err) {
+			return res, err
+		}
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("%s: transient failure, retrying in %s (attempt %d of %d, driver: %s)", caller, delay, attempt+1, policy.Attempts, name))
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return res, err
+		}
+		delay *= 2
+	}
+}
+
+// acquireExecSlot blocks until an execution slot is free or ctx is done. The
+// returned release func must be called once the subprocess has exited.
+func (m *Manager) acquireExecSlot(ctx context.Context, caller, name string) (func(), error) {
+	m.mu.Lock()
+	slots := m.execSlots
+	m.mu.Unlock()
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	default:
+	}
+	m.emitLog(services.LogLevelDebug, fmt.Sprintf("%s: queued, %d plugin process(es) already running (driver: %s)", caller, cap(slots), name))
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s: cancelled while queued: %w", caller, ctx.Err())
+	}
 }
 
 // runPluginCommand resolves the named plugin, spawns its binary with the given
@@ -41,7 +134,15 @@ func (m *Manager) RunCommand(name, command string, timeout time.Duration, req []
 //
 // The `caller` parameter is a label used in log/error messages (e.g.
 // "ExecPlugin", "GetConnectionTree") so that each call site produces
-// recognisable diagnostics.
+// recognisable diagnostics.  env holds extra environment variables for the
+// plugin on top of QUERYBOX_PLUGIN_NAME, e.g. connectionEnv for a call made
+// on behalf of a stored connection.
+//
+// The subprocess is bound to ctx as well as the timeout: cancelling ctx (for
+// example when the frontend aborts a bound call because the user clicked Stop
+// or closed the tab) interrupts the plugin process so it can cancel its
+// statement server-side, and kills it if it is still running after
+// pluginStopGrace.
 //
 // Serialization contract: requests are serialized with encoding/json because
 // all request structs are plain Go types (no proto enums or oneofs). Responses
@@ -50,42 +151,51 @@ func (m *Manager) RunCommand(name, command string, timeout time.Duration, req []
 // also switching request serialization to protojson.Marshal -- encoding/json
 // would emit numeric enum values and Go field names instead of proto names,
 // causing parse errors on the plugin side.
-func (m *Manager) runPluginCommand(caller, name, command string, timeout time.Duration, reqBytes []byte) ([]byte, error) {
+func (m *Manager) runPluginCommand(ctx context.Context, caller, name, command string, timeout time.Duration, reqBytes []byte, env []string) ([]byte, error) {
 	name = driverid.Normalize(name)
 	m.mu.Lock()
 	info, ok := m.plugins[name]
+	disabled := m.disabled.Contains(name)
 	m.mu.Unlock()
+	if disabled {
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("%s: plugin '%s' is disabled", caller, name))
+		return nil, fmt.Errorf("%s: plugin %s is disabled; enable it in the Plugins window", caller, name)
+	}
 	if !ok {
 		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: plugin '%s' not found", caller, name))
 		return nil, fmt.Errorf("%s: plugin %s not found", caller, name)
 	}
+	if info.rejected {
+		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: plugin '%s' was rejected: %s", caller, name, info.LastError))
+		return nil, fmt.Errorf("%s: plugin %s was rejected: %s", caller, name, info.LastError)
+	}
 	full := info.Path
 	if !isExecutable(full) {
 		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: plugin '%s' is not executable", caller, name))
 		return nil, fmt.Errorf("%s: plugin %s is not executable", caller, name)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	parent := ctx
+	ctx, cancel := context.WithTimeout(parent, timeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, full, command)
+	cmd.Cancel = func() error { return interruptPlugin(cmd.Process) }
+	cmd.WaitDelay = pluginStopGrace
 	hideWindow(cmd)
 	cmd.Env = append(os.Environ(), "QUERYBOX_PLUGIN_NAME="+name)
+	cmd.Env = append(cmd.Env, env...)
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: stdin pipe error for plugin '%s': %v", caller, name, err))
 		return nil, fmt.Errorf("%s: stdin pipe error: %w", caller, err)
 	}
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: stdout pipe error for plugin '%s': %v", caller, name, err))
-		return nil, fmt.Errorf("%s: stdout pipe error: %w", caller, err)
-	}
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: stderr pipe error for plugin '%s': %v", caller, name, err))
-		return nil, fmt.Errorf("%s: stderr pipe error: %w", caller, err)
-	}
+	// Output is collected by Wait itself so WaitDelay also bounds reading
+	// it: a plugin that ignores the interrupt, or leaves a child holding
+	// its stdout open, is killed and abandoned after pluginStopGrace.
+	var stdoutBuf, stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
 
 	if err := cmd.Start(); err != nil {
 		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: failed to start plugin '%s': %v", caller, name, err))
@@ -99,14 +209,23 @@ func (m *Manager) runPluginCommand(caller, name, command string, timeout time.Du
 		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: stdin close error for plugin '%s': %v", caller, name, cerr))
 	}
 
-	outB, _ := io.ReadAll(stdoutPipe)
-	errB, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
+	err = cmd.Wait()
+	outB, errB := stdoutBuf.Bytes(), stderrBuf.Bytes()
+	if err != nil {
+		if parent.Err() == context.Canceled {
+			m.emitLog(services.LogLevelWarn, fmt.Sprintf("%s: plugin '%s' cancelled", caller, name))
+			return nil, fmt.Errorf("%s: plugin cancelled: %w", caller, context.Canceled)
+		}
 		if ctx.Err() == context.DeadlineExceeded {
 			m.emitLog(services.LogLevelError, fmt.Sprintf("%s: plugin '%s' timed out after %s", caller, name, timeout))
 			return nil, fmt.Errorf("%s: plugin timed out after %s", caller, timeout)
 		}
+		// plugins built on ServeCLI describe fatal errors as JSON on stdout;
+		// prefer that message over the raw exit status and stderr dump.
+		if msg, ok := plugin.ParseCLIError(outB); ok {
+			m.emitLog(services.LogLevelError, fmt.Sprintf("%s: plugin '%s' failed: %s", caller, name, msg))
+			return nil, fmt.Errorf("%s: plugin error: %s", caller, msg)
+		}
 		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: plugin '%s' exited with error: %v", caller, name, err))
 		return nil, fmt.Errorf("%s: plugin exited: %w - stderr: %s", caller, err, string(errB))
 	}
@@ -114,6 +233,44 @@ func (m *Manager) runPluginCommand(caller, name, command string, timeout time.Du
 	return outB, nil
 }
 
+// connectionEnv returns the QUERYBOX_CONNECTION_ID/QUERYBOX_CONNECTION_NAME
+// variables that tell a plugin which stored connection it is serving; empty
+// values are omitted.  Plugins only use them for logging and diagnostics.
+func connectionEnv(id, name string) []string {
+	var env []string
+	if id != "" {
+		env = append(env, "QUERYBOX_CONNECTION_ID="+id)
+	}
+	if name != "" {
+		env = append(env, "QUERYBOX_CONNECTION_NAME="+name)
+	}
+	return env
+}
+
+// pluginLogPrefix marks a stdout line as a diagnostic message rather than
+// part of the JSON response.  Plugins (or the drivers they embed) may print
+// such lines anywhere in their output.
+const pluginLogPrefix = "#log "
+
+// extractPluginLogLines removes every line starting with pluginLogPrefix
+// from a plugin's stdout, emitting each as a log entry, and returns the
+// remaining output for JSON decoding.
+func (m *Manager) extractPluginLogLines(caller, name string, out []byte) []byte {
+	if !bytes.Contains(out, []byte(pluginLogPrefix)) {
+		return out
+	}
+	var kept [][]byte
+	for _, line := range bytes.SplitAfter(out, []byte("\n")) {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if msg, ok := bytes.CutPrefix(trimmed, []byte(pluginLogPrefix)); ok {
+			m.emitLog(services.LogLevelInfo, fmt.Sprintf("%s: plugin '%s': %s", caller, name, bytes.TrimRight(msg, "\r\n")))
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return bytes.Join(kept, nil)
+}
+
 // ExecPlugin runs the named plugin with the provided connection info, query
 // and optional options map.  Under the hood the manager spawns the binary,
 // writes a protobuf-JSON `PluginV1_ExecRequest` to stdin, and reads a
@@ -122,29 +279,200 @@ func (m *Manager) runPluginCommand(caller, name, command string, timeout time.Du
 // Callers receive the structured `plugin.ExecResponse` (alias for the proto
 // type) or an error.  Historically this returned a raw string; callers may need
 // to examine the `Result` field to access rows, documents, or key/value data.
-func (m *Manager) ExecPlugin(name string, connection map[string]string, query string, options map[string]string) (*plugin.ExecResponse, error) {
-	// Truncate long queries in log output to keep messages readable
-	logQuery := query
-	if len(logQuery) > 80 {
-		logQuery = logQuery[:80] + "..."
+//
+// ctx is supplied by the Wails binding layer; cancelling the frontend promise
+// aborts the running plugin process.
+func (m *Manager) ExecPlugin(ctx context.Context, name string, connection map[string]string, query string, options map[string]string) (*plugin.ExecResponse, error) {
+	return m.ExecPluginPage(ctx, name, connection, query, options, 0, 0)
+}
+
+// ConnectionSource looks up stored connections and their credentials, and
+// keeps their last known health status. *services.ConnectionService
+// satisfies it; the indirection keeps the plugin manager independent of the
+// connections database.
+type ConnectionSource interface {
+	GetConnection(ctx context.Context, id string) (services.Connection, error)
+	GetCredential(ctx context.Context, id string) (string, error)
+	RecordConnectionStatus(ctx context.Context, id string, ok bool, message string) (services.ConnectionStatus, error)
+}
+
+// Verify the connections service satisfies ConnectionSource at compile time.
+var _ ConnectionSource = (*services.ConnectionService)(nil)
+
+// SetConnectionSource wires the store RunQuery resolves connection ids
+// against. Call it once at startup.
+func (m *Manager) SetConnectionSource(src ConnectionSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connections = src
+}
+
+// RunQuery executes query against the stored connection connectionID. The
+// driver and credential are resolved on the Go side, so the frontend (e.g.
+// the editor's "Run selection" shortcut) only passes the id instead of
+// fetching and shaping the credential blob itself.
+func (m *Manager) RunQuery(ctx context.Context, connectionID, query string, options map[string]string) (*plugin.ExecResponse, error) {
+	conn, connection, err := m.resolveConnection(ctx, "RunQuery", connectionID)
+	if err != nil {
+		return nil, err
+	}
+	return m.execPluginPage(ctx, conn.DriverType, connection, query, options, 0, 0, connectionEnv(conn.ID, conn.Name))
+}
+
+// storedConnectionEnv returns the connectionEnv for the stored connection
+// id.  The connection's name is added when the ConnectionSource knows it; a
+// failed lookup only loses the name.
+func (m *Manager) storedConnectionEnv(ctx context.Context, id string) []string {
+	m.mu.Lock()
+	src := m.connections
+	m.mu.Unlock()
+	var name string
+	if src != nil {
+		if conn, err := src.GetConnection(ctx, id); err == nil {
+			name = conn.Name
+		}
+	}
+	return connectionEnv(id, name)
+}
+
+// resolveConnection returns the stored connection with the given id and its
+// plugin connection map. The credential is passed as credential_blob, the
+// key every plugin parses via plugin.ParseCredentialBlob; a connection
+// without a stored credential yields an empty map.
+func (m *Manager) resolveConnection(ctx context.Context, caller, id string) (services.Connection, map[string]string, error) {
+	m.mu.Lock()
+	src := m.connections
+	m.mu.Unlock()
+	if src == nil {
+		return services.Connection{}, nil, fmt.Errorf("%s: no connection source configured", caller)
+	}
+	conn, err := src.GetConnection(ctx, id)
+	if err != nil {
+		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: failed to load connection '%s': %v", caller, id, err))
+		return services.Connection{}, nil, fmt.Errorf("%s: %w", caller, err)
+	}
+	if conn.DriverType == "" {
+		return services.Connection{}, nil, fmt.Errorf("%s: connection %s has no driver", caller, id)
 	}
+	cred, err := src.GetCredential(ctx, id)
+	if err != nil {
+		m.emitLog(services.LogLevelError, fmt.Sprintf("%s: failed to load credential for '%s': %v", caller, id, err))
+		return services.Connection{}, nil, fmt.Errorf("%s: %w", caller, err)
+	}
+	connection := map[string]string{}
+	if cred != "" {
+		connection["credential_blob"] = cred
+	}
+	return conn, connection, nil
+}
+
+// ExecPluginPage is ExecPlugin with first-class pagination: offset and limit
+// are forwarded as ExecRequest.Offset/Limit so grid views page consistently
+// across drivers.  limit <= 0 requests the full result.
+func (m *Manager) ExecPluginPage(ctx context.Context, name string, connection map[string]string, query string, options map[string]string, offset, limit int64) (*plugin.ExecResponse, error) {
+	return m.execPluginPage(ctx, name, connection, query, options, offset, limit, nil)
+}
+
+// execPluginPage implements ExecPluginPage, running the plugin with the
+// extra environment variables env (see connectionEnv).
+func (m *Manager) execPluginPage(ctx context.Context, name string, connection map[string]string, query string, options map[string]string, offset, limit int64, env []string) (*plugin.ExecResponse, error) {
+	// Truncate long queries in log output to keep messages readable
+	logQuery := truncateQuery(query)
 	if len(options) > 0 {
 		m.emitLog(services.LogLevelInfo, fmt.Sprintf("ExecPlugin: executing (driver: %s, query: %q, options: %v)", name, logQuery, options))
 	} else {
 		m.emitLog(services.LogLevelInfo, fmt.Sprintf("ExecPlugin: executing (driver: %s, query: %q)", name, logQuery))
 	}
+	if limit > 0 {
+		m.emitLog(services.LogLevelDebug, fmt.Sprintf("ExecPlugin: paginating (driver: %s, offset: %d, limit: %d)", name, offset, limit))
+	}
 
-	// build request envelope; include options map if supplied
-	req := execRequest{Connection: connection, Query: query, Options: options}
+	// build request envelope; include options map, stored plugin settings
+	// and pagination if supplied
+	req := execRequest{Connection: connection, Query: query, Options: m.withSettingOptions(name, options), Offset: offset, Limit: limit}
 	b, err := json.Marshal(&req)
 	if err != nil {
 		return nil, fmt.Errorf("ExecPlugin: marshal request: %w", err)
 	}
 
-	outB, err := m.runPluginCommand("ExecPlugin", name, "exec", defaultPluginTimeout, b)
+	resp, err := withRetry(ctx, m, "ExecPlugin", name, func() (*plugin.ExecResponse, error) {
+		return m.execOnce(ctx, name, b, env)
+	}, func(resp *plugin.ExecResponse, _ error) bool {
+		// only failures to reach the data store are safe to repeat; a query
+		// that timed out, failed, or lost its connection mid-statement may
+		// already have had side effects.
+		return resp.GetErrorCode() == plugin.ErrorCodeConnectionFailed && plugin.IsDialFailure(errors.New(resp.GetError()))
+	})
+	if err != nil {
+		return resp, &ExecError{
+			Driver:  name,
+			Query:   logQuery,
+			Code:    resp.GetErrorCode().String(),
+			Message: err.Error(),
+			err:     err,
+		}
+	}
+	return resp, nil
+}
+
+// withSettingOptions returns options plus the values stored for the plugin's
+// settings as setting.<key> entries.  Keys the caller passed win, and options
+// itself is left unmodified.
+func (m *Manager) withSettingOptions(name string, options map[string]string) map[string]string {
+	m.mu.Lock()
+	stored := m.settingValues.Get(name)
+	m.mu.Unlock()
+	if len(stored) == 0 {
+		return options
+	}
+	merged := plugin.SettingOptions(stored)
+	for k, v := range options {
+		merged[k] = v
+	}
+	return merged
+}
+
+// ExecError is returned by ExecPlugin when a query fails, whether the plugin
+// reported the error or could not be run at all.  Besides the message it
+// carries the driver and the (truncated) query as fields, which the Wails
+// binding layer serialises as the error's cause so the UI can show "query X
+// failed on driver Y" without parsing the message.
+type ExecError struct {
+	Driver  string `json:"driver"`
+	Query   string `json:"query"` // truncated like the log line
+	Code    string `json:"code"`  // plugin.ErrorCode name; ERROR_CODE_UNKNOWN when the plugin did not answer
+	Message string `json:"message"`
+	err     error
+}
+
+func (e *ExecError) Error() string { return e.Message }
+
+func (e *ExecError) Unwrap() error { return e.err }
+
+// maxLoggedQuery is how much of a query is kept in logs and ExecError.
+const maxLoggedQuery = 80
+
+// truncateQuery shortens query to maxLoggedQuery bytes, marking the cut.
+func truncateQuery(query string) string {
+	if len(query) > maxLoggedQuery {
+		return query[:maxLoggedQuery] + "..."
+	}
+	return query
+}
+
+// execOnce runs a single `exec` invocation with the marshalled request b.
+func (m *Manager) execOnce(ctx context.Context, name string, b []byte, env []string) (*plugin.ExecResponse, error) {
+	release, err := m.acquireExecSlot(ctx, "ExecPlugin", name)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+
+	outB, err := m.runPluginCommand(ctx, "ExecPlugin", name, "exec", defaultPluginTimeout, b, env)
+	if err != nil {
+		return nil, err
+	}
+	outB = m.extractPluginLogLines("ExecPlugin", name, outB)
 
 	// if the plugin didn't emit JSON we still want to return something useful
 	// so wrap the raw output in a simple key/value result.  Older clients may
@@ -183,19 +511,14 @@ func (m *Manager) ExecPlugin(name string, connection map[string]string, query st
 				}
 			}
 		}
-		m.emitLog(services.LogLevelError, fmt.Sprintf("ExecPlugin: JSON unmarshal failed for plugin '%s': %v", name, err))
-		// fallback to embedding the raw output in a KV map under "_".
-		return &plugin.ExecResponse{
-			Result: &pluginpb.PluginV1_ExecResult{
-				Payload: &pluginpb.PluginV1_ExecResult_Kv{
-					Kv: &pluginpb.PluginV1_KeyValueResult{
-						Data: map[string]string{"_": string(outB)},
-					},
-				},
-			},
-		}, nil
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("ExecPlugin: plugin '%s' did not return an ExecResponse, rendering raw output: %v", name, err))
+		return fallbackExecResponse(outB), nil
 	}
 	if resp.Error != "" {
+		if resp.ErrorCode != plugin.ErrorCodeUnknown {
+			m.emitLog(services.LogLevelError, fmt.Sprintf("ExecPlugin: plugin '%s' returned error (code: %s): %s", name, resp.ErrorCode, resp.Error))
+			return resp, fmt.Errorf("ExecPlugin: plugin error: %s", resp.Error)
+		}
 		m.emitLog(services.LogLevelError, fmt.Sprintf("ExecPlugin: plugin '%s' returned error: %s", name, resp.Error))
 		return resp, fmt.Errorf("ExecPlugin: plugin error: %s", resp.Error)
 	}
@@ -203,22 +526,120 @@ func (m *Manager) ExecPlugin(name string, connection map[string]string, query st
 	return resp, nil
 }
 
+// fallbackExecResponse renders plugin output that is not an ExecResponse
+// envelope so the user still sees something meaningful.  A JSON object
+// becomes a key/value result of its top-level keys in document order
+// (non-string values are pretty-printed JSON); any other output — a JSON
+// array or scalar, or plain text such as a driver banner — becomes a
+// single-cell table.
+func fallbackExecResponse(outB []byte) *plugin.ExecResponse {
+	trimmed := bytes.TrimSpace(outB)
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &obj); err == nil && obj != nil {
+		pairs := make([]string, 0, 2*len(obj))
+		for _, k := range jsonObjectKeys(trimmed) {
+			pairs = append(pairs, k, formatRawJSON(obj[k]))
+		}
+		return &plugin.ExecResponse{
+			Result: &pluginpb.PluginV1_ExecResult{
+				Payload: &pluginpb.PluginV1_ExecResult_Kv{
+					Kv: plugin.NewKeyValueResult(pairs...),
+				},
+			},
+		}
+	}
+
+	cell := string(trimmed)
+	if json.Valid(trimmed) {
+		cell = formatRawJSON(trimmed)
+	}
+	return &plugin.ExecResponse{
+		Result: &pluginpb.PluginV1_ExecResult{
+			Payload: &pluginpb.PluginV1_ExecResult_Sql{
+				Sql: &pluginpb.PluginV1_SqlResult{
+					Columns: []*pluginpb.PluginV1_Column{{Name: "output"}},
+					Rows:    []*pluginpb.PluginV1_Row{{Values: []string{cell}}},
+				},
+			},
+		},
+	}
+}
+
+// jsonObjectKeys returns the top-level keys of a valid JSON object in the
+// order they appear, so a fallback key/value result lists fields as the
+// plugin wrote them. A repeated key is listed once, at its first position;
+// json.Unmarshal keeps its last value.
+func jsonObjectKeys(obj []byte) []string {
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	if _, err := dec.Token(); err != nil { // opening brace
+		return nil
+	}
+	var keys []string
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		if k := tok.(string); !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			break
+		}
+	}
+	return keys
+}
+
+// formatRawJSON returns a JSON string value unquoted and any other value
+// indented for display.
+func formatRawJSON(raw json.RawMessage) string {
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
 // GetConnectionTree asks the named plugin for its connection tree.  The
-// request contains only the connection map; the plugin defines node structure
-// and actions.  A timeout guards misbehaving plugins.
-func (m *Manager) GetConnectionTree(name string, connection map[string]string) (*plugin.ConnectionTreeResponse, error) {
-	m.emitLog(services.LogLevelInfo, fmt.Sprintf("GetConnectionTree: fetching tree (driver: %s)", name))
+// request contains the connection map and maxDepth; the plugin defines node
+// structure and actions.  maxDepth limits how many levels are returned so the
+// frontend can fetch only the top of a deep tree (1 = top-level nodes only);
+// 0 returns the whole tree.  A timeout guards misbehaving plugins; cancelling
+// ctx aborts the request early.
+func (m *Manager) GetConnectionTree(ctx context.Context, name string, connection map[string]string, maxDepth int) (*plugin.ConnectionTreeResponse, error) {
+	return m.getConnectionTree(ctx, name, connection, maxDepth, nil)
+}
 
-	req := plugin.ConnectionTreeRequest{Connection: connection}
+// getConnectionTree implements GetConnectionTree, running the plugin with
+// the extra environment variables env (see connectionEnv).
+func (m *Manager) getConnectionTree(ctx context.Context, name string, connection map[string]string, maxDepth int, env []string) (*plugin.ConnectionTreeResponse, error) {
+	m.emitLog(services.LogLevelInfo, fmt.Sprintf("GetConnectionTree: fetching tree (driver: %s, max depth: %d)", name, maxDepth))
+
+	req := plugin.ConnectionTreeRequest{Connection: connection, MaxDepth: int32(maxDepth)}
 	b, err := json.Marshal(&req)
 	if err != nil {
 		return nil, fmt.Errorf("GetConnectionTree: marshal request: %w", err)
 	}
 
-	outB, err := m.runPluginCommand("GetConnectionTree", name, "connection-tree", defaultPluginTimeout, b)
+	release, err := m.acquireExecSlot(ctx, "GetConnectionTree", name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	outB, err := m.runPluginCommand(ctx, "GetConnectionTree", name, "connection-tree", defaultPluginTimeout, b, env)
 	if err != nil {
 		return nil, err
 	}
+	outB = m.extractPluginLogLines("GetConnectionTree", name, outB)
 
 	resp := &plugin.ConnectionTreeResponse{}
 	if len(outB) == 0 {
@@ -233,11 +654,52 @@ func (m *Manager) GetConnectionTree(name string, connection map[string]string) (
 	return resp, nil
 }
 
+// GetStoredConnectionTree is GetConnectionTree for the stored connection
+// connectionID: like RunQuery, the driver and credential are resolved on the
+// Go side and the plugin is told which connection it serves.
+func (m *Manager) GetStoredConnectionTree(ctx context.Context, connectionID string, maxDepth int) (*plugin.ConnectionTreeResponse, error) {
+	conn, connection, err := m.resolveConnection(ctx, "GetConnectionTree", connectionID)
+	if err != nil {
+		return nil, err
+	}
+	return m.getConnectionTree(ctx, conn.DriverType, connection, maxDepth, connectionEnv(conn.ID, conn.Name))
+}
+
 // ExecTreeAction is a convenience wrapper for executing the query payload
-// attached to a tree node action.  It simply forwards to ExecPlugin and
-// propagates any provided options map (for example "explain-query").
-func (m *Manager) ExecTreeAction(name string, connection map[string]string, actionQuery string, options map[string]string) (*plugin.ExecResponse, error) {
-	return m.ExecPlugin(name, connection, actionQuery, options)
+// attached to a tree node action.  It forwards to ExecPlugin and propagates
+// any provided options map (for example "explain-query") and the caller's
+// context.  actionType is the action's machine name; when a DDL action
+// (create/drop database or table) succeeds the manager emits tree:invalidate
+// for connectionID so the frontend drops its cached tree and refetches it.
+func (m *Manager) ExecTreeAction(ctx context.Context, name string, connectionID string, connection map[string]string, actionType string, actionQuery string, options map[string]string) (*plugin.ExecResponse, error) {
+	var env []string
+	if connectionID != "" {
+		env = m.storedConnectionEnv(ctx, connectionID)
+	}
+	resp, err := m.execPluginPage(ctx, name, connection, actionQuery, options, 0, 0, env)
+	if err != nil || resp.GetError() != "" {
+		return resp, err
+	}
+	if invalidatesTree(actionType) && connectionID != "" {
+		m.emitLog(services.LogLevelInfo, fmt.Sprintf("ExecTreeAction: '%s' succeeded, invalidating tree (connection: %s)", actionType, connectionID))
+		if m.emitter != nil {
+			m.emitter.EmitEvent(services.EventTreeInvalidate, services.TreeInvalidateEvent{ConnectionID: connectionID, ActionType: actionType})
+		}
+	}
+	return resp, nil
+}
+
+// invalidatesTree reports whether a successful tree action of the given type
+// changes the shape of the connection tree.
+func invalidatesTree(actionType string) bool {
+	switch actionType {
+	case plugin.ConnectionTreeActionCreateDatabase,
+		plugin.ConnectionTreeActionDropDatabase,
+		plugin.ConnectionTreeActionCreateTable,
+		plugin.ConnectionTreeActionDropTable:
+		return true
+	}
+	return false
 }
 
 // MutateRow forwards a single-row mutation request to the specified plugin.
@@ -254,7 +716,7 @@ func (m *Manager) MutateRow(name string, connection map[string]string, operation
 		return nil, fmt.Errorf("MutateRow: marshal request: %w", err)
 	}
 
-	outB, err := m.runPluginCommand("MutateRow", name, "mutate-row", defaultPluginTimeout, b)
+	outB, err := m.runPluginCommand(context.Background(), "MutateRow", name, "mutate-row", defaultPluginTimeout, b, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -283,7 +745,7 @@ func (m *Manager) DescribeSchema(name string, connection map[string]string, data
 		return nil, fmt.Errorf("DescribeSchema: marshal request: %w", err)
 	}
 
-	outB, err := m.runPluginCommand("DescribeSchema", name, "describe-schema", defaultPluginTimeout, b)
+	outB, err := m.runPluginCommand(context.Background(), "DescribeSchema", name, "describe-schema", defaultPluginTimeout, b, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -308,15 +770,51 @@ func (m *Manager) DescribeSchema(name string, connection map[string]string, data
 // case TestConnection returns an error rather than a failed response so the
 // caller can distinguish "unsupported" from "tested and failed".
 func (m *Manager) TestConnection(name string, connection map[string]string) (*plugin.TestConnectionResponse, error) {
-	m.emitLog(services.LogLevelInfo, fmt.Sprintf("TestConnection: testing (driver: %s)", name))
+	return m.testConnection(name, connection, "")
+}
 
-	req := plugin.TestConnectionRequest{Connection: connection}
+// TestConnectionForms tests the same connection once per candidate auth form
+// (AuthForm.key), setting TestConnectionRequest.Form so the plugin reads the
+// credential values through that form.  Results are keyed by form.  Forms are
+// tried in order; an error is returned as soon as the plugin cannot be
+// invoked, since that failure would repeat for every remaining form.
+func (m *Manager) TestConnectionForms(name string, connection map[string]string, forms []string) (map[string]*plugin.TestConnectionResponse, error) {
+	results := make(map[string]*plugin.TestConnectionResponse, len(forms))
+	for _, form := range forms {
+		resp, err := m.testConnection(name, connection, form)
+		if err != nil {
+			return nil, err
+		}
+		results[form] = resp
+	}
+	return results, nil
+}
+
+func (m *Manager) testConnection(name string, connection map[string]string, form string) (*plugin.TestConnectionResponse, error) {
+	if form != "" {
+		m.emitLog(services.LogLevelInfo, fmt.Sprintf("TestConnection: testing form '%s' (driver: %s)", form, name))
+	} else {
+		m.emitLog(services.LogLevelInfo, fmt.Sprintf("TestConnection: testing (driver: %s)", name))
+	}
+
+	req := plugin.TestConnectionRequest{Connection: connection, Form: form}
 	b, err := json.Marshal(&req)
 	if err != nil {
 		return nil, fmt.Errorf("TestConnection: marshal request: %w", err)
 	}
 
-	outB, err := m.runPluginCommand("TestConnection", name, "test-connection", fastPluginTimeout, b)
+	return withRetry(context.Background(), m, "TestConnection", name, func() (*plugin.TestConnectionResponse, error) {
+		return m.testConnectionOnce(name, b)
+	}, func(resp *plugin.TestConnectionResponse, err error) bool {
+		// the response carries no error code, so classify the message
+		return err == nil && !resp.GetOk() && plugin.ClassifyError(errors.New(resp.GetMessage())) == plugin.ErrorCodeConnectionFailed
+	})
+}
+
+// testConnectionOnce runs a single `test-connection` invocation with the
+// marshalled request b.
+func (m *Manager) testConnectionOnce(name string, b []byte) (*plugin.TestConnectionResponse, error) {
+	outB, err := m.runPluginCommand(context.Background(), "TestConnection", name, "test-connection", fastPluginTimeout, b, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -325,7 +823,7 @@ func (m *Manager) TestConnection(name string, connection map[string]string) (*pl
 	if len(outB) == 0 {
 		return &resp, nil
 	}
-	if err := json.Unmarshal(outB, &resp); err != nil {
+	if err := protojson.Unmarshal(outB, &resp); err != nil {
 		return nil, fmt.Errorf("TestConnection: invalid response json: %w", err)
 	}
 
@@ -337,16 +835,118 @@ func (m *Manager) TestConnection(name string, connection map[string]string) (*pl
 	return &resp, nil
 }
 
+// ValidatePlugin asks the named plugin to parse/plan `query` without
+// executing it by invoking the `validate` command.  Plugins built on
+// ServeCLI that do not implement validation answer with unsupported=true; an
+// error is only returned when the plugin cannot be invoked at all.
+func (m *Manager) ValidatePlugin(name string, connection map[string]string, query string) (*plugin.ValidateResponse, error) {
+	m.emitLog(services.LogLevelInfo, fmt.Sprintf("ValidatePlugin: validating (driver: %s)", name))
+
+	req := plugin.ValidateRequest{Connection: connection, Query: query}
+	b, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("ValidatePlugin: marshal request: %w", err)
+	}
+
+	outB, err := m.runPluginCommand(context.Background(), "ValidatePlugin", name, "validate", fastPluginTimeout, b, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &plugin.ValidateResponse{}
+	if len(outB) == 0 {
+		// plugins predating the command may exit cleanly without output
+		resp.Unsupported = true
+		return resp, nil
+	}
+	if err := protojson.Unmarshal(outB, resp); err != nil {
+		m.emitLog(services.LogLevelError, fmt.Sprintf("ValidatePlugin: invalid JSON from '%s': %v", name, err))
+		return nil, fmt.Errorf("ValidatePlugin: invalid json: %w", err)
+	}
+
+	switch {
+	case resp.Unsupported:
+		m.emitLog(services.LogLevelInfo, fmt.Sprintf("ValidatePlugin: (driver: %s) validation unsupported", name))
+	case resp.Valid:
+		m.emitLog(services.LogLevelInfo, fmt.Sprintf("ValidatePlugin: (driver: %s) query is valid", name))
+	default:
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("ValidatePlugin: (driver: %s) query is invalid: %s", name, resp.Message))
+	}
+	return resp, nil
+}
+
+// GenerateDDL asks the named plugin for the DDL recreating the object behind
+// the connection tree node nodeKey by invoking the `ddl` command.  Plugins
+// that cannot generate DDL answer with unsupported=true; a plugin-reported
+// failure is returned as an error alongside the response.
+func (m *Manager) GenerateDDL(name string, connection map[string]string, nodeKey string) (*plugin.GenerateDDLResponse, error) {
+	m.emitLog(services.LogLevelInfo, fmt.Sprintf("GenerateDDL: generating DDL for '%s' (driver: %s)", nodeKey, name))
+
+	req := plugin.GenerateDDLRequest{Connection: connection, NodeKey: nodeKey}
+	b, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("GenerateDDL: marshal request: %w", err)
+	}
+
+	outB, err := m.runPluginCommand(context.Background(), "GenerateDDL", name, "ddl", fastPluginTimeout, b, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &plugin.GenerateDDLResponse{}
+	if len(outB) == 0 {
+		// plugins predating the command may exit cleanly without output
+		resp.Unsupported = true
+		return resp, nil
+	}
+	if err := protojson.Unmarshal(outB, resp); err != nil {
+		m.emitLog(services.LogLevelError, fmt.Sprintf("GenerateDDL: invalid JSON from '%s': %v", name, err))
+		return nil, fmt.Errorf("GenerateDDL: invalid json: %w", err)
+	}
+
+	switch {
+	case resp.Unsupported:
+		m.emitLog(services.LogLevelInfo, fmt.Sprintf("GenerateDDL: (driver: %s) DDL generation unsupported", name))
+	case resp.Error != "":
+		m.emitLog(services.LogLevelError, fmt.Sprintf("GenerateDDL: plugin '%s' returned error: %s", name, resp.Error))
+		return resp, fmt.Errorf("GenerateDDL: plugin error: %s", resp.Error)
+	default:
+		m.emitLog(services.LogLevelInfo, fmt.Sprintf("GenerateDDL: (driver: %s) completed successfully", name))
+	}
+	return resp, nil
+}
+
+// authFormsTTL is how long GetPluginAuthForms reuses a plugin's forms before
+// asking the binary again. Forms only change with the binary, and scans drop
+// the entry of any plugin they re-probe, so the window can be generous.
+const authFormsTTL = 10 * time.Minute
+
+type authFormsEntry struct {
+	forms map[string]*plugin.AuthForm
+	at    time.Time
+}
+
 // GetPluginAuthForms probes the plugin executable for supported authentication
 // forms by invoking `plugin authforms` and decoding the JSON response. If the
 // plugin doesn't implement the command or returns no forms an empty map is
-// returned.
+// returned. Decoded forms are cached per plugin for authFormsTTL so the
+// connection dialog can ask repeatedly without spawning the plugin each time.
 func (m *Manager) GetPluginAuthForms(name string) (map[string]*plugin.AuthForm, error) {
+	key := driverid.Normalize(name)
+	m.mu.Lock()
+	e, ok := m.authForms[key]
+	m.mu.Unlock()
+	if ok && time.Since(e.at) < authFormsTTL {
+		m.emitLog(services.LogLevelDebug, fmt.Sprintf("GetPluginAuthForms: using cached forms (driver: %s)", key))
+		return copyAuthForms(e.forms), nil
+	}
+
 	// Use runPluginCommand for consistent subprocess handling (env vars,
 	// logging, timeout, hideWindow). authforms takes no stdin input.
-	out, err := m.runPluginCommand("GetPluginAuthForms", name, "authforms", fastPluginTimeout, nil)
+	out, err := m.runPluginCommand(context.Background(), "GetPluginAuthForms", name, "authforms", fastPluginTimeout, nil, nil)
 	if err != nil {
-		// treat as not implemented gracefully
+		// treat as not implemented gracefully; not cached, the failure may
+		// be transient
 		return nil, nil
 	}
 	if len(out) == 0 {
@@ -363,7 +963,48 @@ func (m *Manager) GetPluginAuthForms(name string) (map[string]*plugin.AuthForm,
 		}
 		ret[k] = v
 	}
-	return ret, nil
+
+	m.mu.Lock()
+	if m.authForms == nil {
+		m.authForms = make(map[string]authFormsEntry)
+	}
+	m.authForms[key] = authFormsEntry{forms: ret, at: time.Now()}
+	m.mu.Unlock()
+	return copyAuthForms(ret), nil
+}
+
+// copyAuthForms returns a deep copy of forms so callers can't alter the
+// cache.
+func copyAuthForms(forms map[string]*plugin.AuthForm) map[string]*plugin.AuthForm {
+	out := make(map[string]*plugin.AuthForm, len(forms))
+	for k, v := range forms {
+		out[k] = proto.Clone(v).(*plugin.AuthForm)
+	}
+	return out
+}
+
+// GetPluginSettings returns the configurable options the plugin declares via
+// `plugin settings`, for the plugin-settings panel.  Plugins that predate the
+// command (exit non-zero without a JSON error, or print nothing) declare no
+// settings; any other failure, such as a missing plugin or one that cannot be
+// started, is returned.
+func (m *Manager) GetPluginSettings(name string) ([]*plugin.Setting, error) {
+	out, err := m.runPluginCommand(context.Background(), "GetPluginSettings", name, "settings", fastPluginTimeout, nil, nil)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	var resp plugin.SettingsResponse
+	if err := protojson.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("GetPluginSettings: invalid settings json: %w", err)
+	}
+	return resp.GetSettings(), nil
 }
 
 // GetCompletionFields asks the named plugin for discoverable field names for a
@@ -379,7 +1020,7 @@ func (m *Manager) GetCompletionFields(name string, connection map[string]string,
 		return &plugin.GetCompletionFieldsResponse{}, nil
 	}
 
-	outB, err := m.runPluginCommand("GetCompletionFields", name, "completion-fields", fastPluginTimeout, b)
+	outB, err := m.runPluginCommand(context.Background(), "GetCompletionFields", name, "completion-fields", fastPluginTimeout, b, nil)
 	if err != nil {
 		// Non-zero exit is expected for older plugins that don't implement this
 		// command -- return empty response rather than an error so callers don't
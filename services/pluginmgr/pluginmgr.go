@@ -1,10 +1,12 @@
 package pluginmgr
 
 import (
+	"fmt"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/felixdotgo/querybox/pkg/driverid"
 	"github.com/felixdotgo/querybox/pkg/plugin"
 	"github.com/felixdotgo/querybox/services"
 	"github.com/wailsapp/wails/v3/pkg/application"
@@ -38,7 +40,25 @@ type PluginInfo struct {
 	Contact     string            `json:"contact,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
 	Settings    map[string]string `json:"settings,omitempty"`
+	// ExampleQueries are editor snippets declared by the plugin for new
+	// connections.
+	ExampleQueries []string       `json:"example_queries,omitempty"`
 	LastError   string            `json:"lastError,omitempty"`
+
+	// rejected is set when the probe showed the binary is not a querybox
+	// plugin (see failsHandshake); commands are never routed to it.
+	rejected bool
+
+	// binModTime and binSize describe the binary as it was when probed, so
+	// Reload can tell which plugins were replaced on disk.
+	binModTime time.Time
+	binSize    int64
+}
+
+// binaryChanged reports whether fi no longer matches the binary p was probed
+// from.
+func (p PluginInfo) binaryChanged(fi os.FileInfo) bool {
+	return !fi.ModTime().Equal(p.binModTime) || fi.Size() != p.binSize
 }
 
 // Manager discovers executables under one or more plugin directories and
@@ -69,6 +89,46 @@ type Manager struct {
 	scanMu  sync.Mutex // serializes scanOnce calls so concurrent Rescan/init don't interleave
 	plugins map[string]PluginInfo
 
+	// disabled holds the user's persisted plugin blocklist. Disabled plugins
+	// are skipped by scanOnce and refused by runPluginCommand. May be nil in
+	// tests that build a Manager by hand; a nil blocklist disables nothing.
+	disabled *blocklist
+
+	// settingValues holds the values the user stored for plugin settings;
+	// ExecPlugin sends them as setting.<key> options. May be nil in tests
+	// that build a Manager by hand. Guarded by mu.
+	settingValues *settingValues
+
+	// execSlots bounds how many ExecPlugin/GetConnectionTree subprocesses run
+	// at once; callers beyond the limit queue until a slot frees up. A nil
+	// channel (hand-built Managers in tests) means unlimited.
+	execSlots chan struct{}
+
+	// retry is the opt-in policy for repeating ExecPlugin/TestConnection
+	// calls that failed to reach the data store. The zero value disables
+	// retries.
+	retry RetryPolicy
+
+	// dirErrors maps plugin directories that could not be created or read
+	// to the error, so Diagnostics can explain an empty plugin list.
+	// Rebuilt by every scan; guarded by mu.
+	dirErrors map[string]string
+
+	// credBackend labels the credential store in use (see
+	// SetCredentialBackend); reported by Diagnostics only.
+	credBackend string
+
+	// connections resolves stored connection ids for RunQuery. nil until
+	// SetConnectionSource is called.
+	connections ConnectionSource
+
+	// authForms caches GetPluginAuthForms results by plugin name. Entries
+	// are dropped when a scan re-probes or removes the plugin. Guarded by mu.
+	authForms map[string]authFormsEntry
+
+	// warmups caches recent Warmup results; the zero value is ready to use.
+	warmups warmupCache
+
 	emitter    services.EventEmitter
 	appReadyCh chan struct{} // closed by SetApp once the Wails app is available
 
@@ -84,22 +144,29 @@ func (m *Manager) SetApp(app *application.App) {
 	close(m.appReadyCh)
 }
 
-// emitLog is a nil-safe helper that emits an app:log event via the EventEmitter.
+// emitLog records a log entry in the shared application log buffer and emits
+// it as an app:log event via the EventEmitter (if one is set).
 func (m *Manager) emitLog(level services.LogLevel, message string) {
-	if m.emitter == nil {
-		return
-	}
-	m.emitter.EmitEvent(services.EventAppLog, services.LogEntry{
+	entry := services.LogEntry{
 		Level:     level,
 		Message:   message,
 		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
-	})
+	}
+	services.RecordLog(entry)
+	if m.emitter == nil {
+		return
+	}
+	m.emitter.EmitEvent(services.EventAppLog, entry)
 }
 
 // Plugin command timeout constants.
 const (
 	defaultPluginTimeout = 30 * time.Second
 	fastPluginTimeout    = 15 * time.Second
+
+	// pluginStopGrace is how long an interrupted plugin may take to cancel
+	// its statement and exit before it is killed.
+	pluginStopGrace = 3 * time.Second
 )
 
 // exec request/response used for CLI JSON interchange with plugins.
@@ -115,6 +182,10 @@ type execRequest struct {
 	// explain-query=yes requests.  This mirrors the protobuf ExecRequest
 	// `options` field and allows the host to signal driver-specific flags.
 	Options    map[string]string `json:"options,omitempty"`
+	// Offset/Limit mirror the protobuf ExecRequest pagination fields and are
+	// omitted when zero so older plugins see an unchanged request.
+	Offset     int64             `json:"offset,omitempty"`
+	Limit      int64             `json:"limit,omitempty"`
 }
 
 // mutateRowRequest mirrors the protobuf MutateRowRequest but uses simple
@@ -149,6 +220,16 @@ func New() *Manager {
         plugins:    make(map[string]PluginInfo),
         appReadyCh: make(chan struct{}),
         fallbackDir: bundle,
+        execSlots:  make(chan struct{}, defaultMaxConcurrentExecs()),
+    }
+
+    if path, perr := settingsDBPath(); perr == nil {
+        // openBlocklist always returns a usable (possibly in-memory) list
+        m.disabled, _ = openBlocklist(path)
+        m.settingValues, _ = openSettingValues(path)
+    } else {
+        m.disabled = newMemoryBlocklist()
+        m.settingValues = newMemorySettingValues()
     }
 
     if err == nil && userDir != "" {
@@ -158,6 +239,9 @@ func New() *Manager {
         // will replace any existing copies.
         if err2 := os.MkdirAll(userDir, 0o755); err2 == nil {
             populateUserDir(userDir, bundle)
+        } else {
+            m.dirErrors = map[string]string{userDir: err2.Error()}
+            m.emitLog(services.LogLevelError, fmt.Sprintf("New: cannot create plugin directory %s: %v", userDir, err2))
         }
         m.dirs = append(m.dirs, userDir)
         m.Dir = userDir
@@ -212,17 +296,168 @@ func (m *Manager) emitPluginsReady() {
 	}
 }
 
-// Shutdown is a no-op; there is no background scanner to stop.
-// It is kept so Wails can still call the lifecycle method without error.
-func (m *Manager) Shutdown() {}
+// Shutdown releases the settings database backing the plugin blocklist and
+// setting values. There is no background scanner to stop.
+func (m *Manager) Shutdown() {
+	m.disabled.Close()
+	m.settingValues.Close()
+}
 
 // ListPlugins returns the discovered plugins (does not start them).
+// Executables that failed the handshake are left out; Diagnostics reports
+// them.
 func (m *Manager) ListPlugins() []PluginInfo {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	ret := make([]PluginInfo, 0, len(m.plugins))
 	for _, p := range m.plugins {
+		if p.rejected {
+			continue
+		}
 		ret = append(ret, p)
 	}
 	return ret
 }
+
+// GetPluginInfo returns the complete metadata recorded for the named plugin
+// during the last scan, including capabilities, tags, licence and icon, so
+// the Plugins window can render a detail panel without re-probing.
+func (m *Manager) GetPluginInfo(name string) (PluginInfo, error) {
+	name = driverid.Normalize(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	info, ok := m.plugins[name]
+	if !ok {
+		return PluginInfo{}, fmt.Errorf("GetPluginInfo: plugin %s not found", name)
+	}
+	return info, nil
+}
+
+// Diagnostics summarises the plugin manager's state for a support/debug
+// panel.
+type Diagnostics struct {
+	PluginDirs        []string          `json:"pluginDirs"`        // scanned in order of precedence
+	PluginCount       int               `json:"pluginCount"`       // entries in the registry, failed probes included
+	Errors            map[string]string `json:"errors"`            // plugin ID -> LastError, for plugins that have one
+	DirErrors         map[string]string `json:"dirErrors"`         // plugin dir -> error creating or reading it
+	CredentialBackend string            `json:"credentialBackend"` // "keyring", "sqlite", "memory" or "" when unknown
+}
+
+// SetCredentialBackend records the label of the active credential backend
+// (CredManager.Backend) so Diagnostics can report it; the plugin manager has
+// no access to the store itself.
+func (m *Manager) SetCredentialBackend(label string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.credBackend = label
+}
+
+// Diagnostics reports the directories scanned for plugins, how many were
+// discovered, the LastError of every plugin whose probe failed, the
+// directories that could not be created or read and the credential backend
+// label.
+func (m *Manager) Diagnostics() Diagnostics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := Diagnostics{
+		PluginDirs:        append([]string{}, m.dirs...),
+		PluginCount:       len(m.plugins),
+		Errors:            map[string]string{},
+		DirErrors:         map[string]string{},
+		CredentialBackend: m.credBackend,
+	}
+	for dir, msg := range m.dirErrors {
+		d.DirErrors[dir] = msg
+	}
+	for id, p := range m.plugins {
+		if p.LastError != "" {
+			d.Errors[id] = p.LastError
+		}
+	}
+	return d
+}
+
+// DisablePlugin adds the plugin to the persisted blocklist and removes it
+// from the registry. Disabled plugins are skipped by subsequent scans and
+// cannot be invoked until EnablePlugin is called.
+func (m *Manager) DisablePlugin(name string) error {
+	name = driverid.Normalize(name)
+	if name == "" {
+		return fmt.Errorf("DisablePlugin: plugin name is required")
+	}
+	m.mu.Lock()
+	if m.disabled == nil {
+		m.disabled = newMemoryBlocklist()
+	}
+	err := m.disabled.Add(name)
+	delete(m.plugins, name)
+	m.mu.Unlock()
+	if err != nil {
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("DisablePlugin: '%s' disabled for this session only: %v", name, err))
+		return nil
+	}
+	m.emitLog(services.LogLevelInfo, fmt.Sprintf("DisablePlugin: '%s' disabled", name))
+	return nil
+}
+
+// EnablePlugin removes the plugin from the blocklist. The plugin reappears in
+// ListPlugins after the next scan (e.g. Rescan from the Plugins window).
+func (m *Manager) EnablePlugin(name string) error {
+	name = driverid.Normalize(name)
+	if name == "" {
+		return fmt.Errorf("EnablePlugin: plugin name is required")
+	}
+	m.mu.Lock()
+	var err error
+	if m.disabled != nil {
+		err = m.disabled.Remove(name)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("EnablePlugin: '%s' enabled for this session only: %v", name, err))
+		return nil
+	}
+	m.emitLog(services.LogLevelInfo, fmt.Sprintf("EnablePlugin: '%s' enabled", name))
+	return nil
+}
+
+// GetPluginSettingValues returns the values stored for the named plugin's
+// settings (setting key -> value), for the Plugins window's settings panel.
+// Settings without a stored value are absent; the plugin applies their
+// default.
+func (m *Manager) GetPluginSettingValues(name string) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.settingValues.Get(name)
+}
+
+// SetPluginSettingValues replaces the stored setting values of the named
+// plugin. Every later exec request to the plugin carries them as
+// setting.<key> options (see plugin.SettingOptions); an empty map restores
+// the defaults.
+func (m *Manager) SetPluginSettingValues(name string, values map[string]string) error {
+	name = driverid.Normalize(name)
+	if name == "" {
+		return fmt.Errorf("SetPluginSettingValues: plugin name is required")
+	}
+	m.mu.Lock()
+	if m.settingValues == nil {
+		m.settingValues = newMemorySettingValues()
+	}
+	err := m.settingValues.Set(name, values)
+	m.mu.Unlock()
+	if err != nil {
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("SetPluginSettingValues: settings of '%s' kept for this session only: %v", name, err))
+		return nil
+	}
+	m.emitLog(services.LogLevelInfo, fmt.Sprintf("SetPluginSettingValues: %d setting(s) stored for '%s'", len(values), name))
+	return nil
+}
+
+// ListDisabledPlugins returns the names on the blocklist in sorted order so
+// the Plugins window can offer to re-enable them.
+func (m *Manager) ListDisabledPlugins() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.disabled.List()
+}
@@ -3,10 +3,20 @@
 
 package pluginmgr
 
-import "os/exec"
+import (
+	"os"
+	"os/exec"
+)
 
 // hideWindow is a no-op on non-Windows platforms. It exists so that the
 // main package can call the function unconditionally without build errors.
 func hideWindow(cmd *exec.Cmd) {
     // nothing to do
 }
+
+// interruptPlugin asks a plugin whose request was cancelled to stop by
+// sending SIGINT; ServeCLI turns it into a cancelled request context.  The
+// process is killed if it has not exited after pluginStopGrace.
+func interruptPlugin(p *os.Process) error {
+    return p.Signal(os.Interrupt)
+}
@@ -2,17 +2,24 @@ package pluginmgr
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/felixdotgo/querybox/pkg/plugin"
 	pluginpb "github.com/felixdotgo/querybox/rpc/contracts/plugin/v1"
+	"github.com/felixdotgo/querybox/services"
 )
 
 // pluginName returns a filename appropriate for the current OS. On Windows
@@ -25,6 +32,23 @@ func pluginName(base string) string {
 	return base
 }
 
+// writeScriptPlugin writes body as an executable shell script plugin called
+// name into a fresh temporary directory and returns a Manager with it
+// registered and that directory as its only scan location. Shell scripts
+// cannot be run as plugins on Windows, so the test is skipped there.
+func writeScriptPlugin(t *testing.T, name, body string) *Manager {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugin not supported on Windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, pluginName(name))
+	if err := os.WriteFile(path, []byte(body), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return &Manager{plugins: map[string]PluginInfo{name: {Path: path}}, dirs: []string{dir}}
+}
+
 func TestUserPluginsDirBehavior(t *testing.T) {
 	orig := userPluginDirFunc
 	defer func() { userPluginDirFunc = orig }()
@@ -176,7 +200,7 @@ func TestMutateRowMissingPlugin(t *testing.T) {
 
 func TestExecTreeActionForwardsOptions(t *testing.T) {
 	m := New()
-	_, err := m.ExecTreeAction("nonexistent", nil, "SELECT 1", map[string]string{"explain-query": "yes"})
+	_, err := m.ExecTreeAction(context.Background(), "nonexistent", "conn-1", nil, "select", "SELECT 1", map[string]string{"explain-query": "yes"})
 	if err == nil {
 		t.Errorf("expected error for missing plugin")
 	}
@@ -238,20 +262,62 @@ func TestGetPluginAuthFormsNonExecutable(t *testing.T) {
 	}
 }
 
-func TestDescribeSchemaParsesResponse(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		t.Skip("shell script plugin not supported on Windows")
+// Auth forms are fetched from the binary once and then served from the cache
+// until a scan re-probes the plugin.
+func TestGetPluginAuthFormsCaches(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	bin := fmt.Sprintf(`#!/bin/sh
+echo x >> %q
+echo '{"forms":{"basic":{"key":"basic","name":"Basic","fields":[{"name":"host"}]}}}'
+`, counter)
+	m := writeScriptPlugin(t, "dummy", bin)
+	calls := func() int {
+		b, _ := os.ReadFile(counter)
+		return strings.Count(string(b), "\n")
 	}
-	// create a dummy executable that handles the describe-schema command
-	dir, err := os.MkdirTemp("", "pmgrschema")
-	if err != nil {
-		t.Fatal(err)
+
+	// probes are injected so only authforms calls run the script
+	orig := probeInfoFunc
+	probeInfoFunc = func(string) (PluginInfo, error) { return PluginInfo{}, nil }
+	defer func() { probeInfoFunc = orig }()
+
+	m.appReadyCh = make(chan struct{})
+	close(m.appReadyCh)
+	m.scanOnce()
+
+	for i := 0; i < 2; i++ {
+		forms, err := m.GetPluginAuthForms("dummy")
+		if err != nil {
+			t.Fatalf("GetPluginAuthForms: %v", err)
+		}
+		if forms["basic"].GetName() != "Basic" || len(forms["basic"].GetFields()) != 1 || forms["basic"].GetFields()[0].GetName() != "host" {
+			t.Fatalf("forms = %v", forms)
+		}
+		// callers may edit the map and the forms they get without
+		// touching the cache
+		forms["basic"].Name = "Edited"
+		forms["basic"].Fields[0].Name = "edited"
+		forms["basic"].Fields = nil
+		delete(forms, "basic")
 	}
-	defer os.RemoveAll(dir)
+	if n := calls(); n != 1 {
+		t.Errorf("plugin ran %d times for two calls; want 1", n)
+	}
+
+	if err := m.Rescan(); err != nil {
+		t.Fatalf("Rescan: %v", err)
+	}
+	if _, err := m.GetPluginAuthForms("dummy"); err != nil {
+		t.Fatalf("GetPluginAuthForms: %v", err)
+	}
+	if n := calls(); n != 2 {
+		t.Errorf("plugin ran %d times after Rescan; want the cache dropped", n)
+	}
+}
 
-	name := pluginName("dummy")
-	req := strings.TrimSuffix(name, filepath.Ext(name))
-	script := filepath.Join(dir, name)
+func TestDescribeSchemaParsesResponse(t *testing.T) {
+	// create a dummy executable that handles the describe-schema command
 	bin := fmt.Sprintf(`#!/bin/sh
 if [ "$1" = "describe-schema" ]; then
   echo '{"tables":[{"name":"foo","columns":[{"name":"id","type":"int"}],"indexes":[]}]}';
@@ -259,11 +325,8 @@ else
   echo '{"nodes":[]}'
 fi
 `)
-	if err := os.WriteFile(script, []byte(bin), 0o755); err != nil {
-		t.Fatalf("write script: %v", err)
-	}
-
-	m := &Manager{plugins: map[string]PluginInfo{req: {Path: script}}}
+	m := writeScriptPlugin(t, "dummy", bin)
+	req, name := "dummy", pluginName("dummy")
 
 	// DescribeSchema expects the plugin name without extension.  Call with
 	// both trimmed and untrimmed inputs to ensure normalization logic works.
@@ -286,19 +349,7 @@ fi
 }
 
 func TestMutateRowParsesResponse(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		t.Skip("shell script plugin not supported on Windows")
-	}
 	// create a dummy executable that handles the mutate-row command
-	dir, err := os.MkdirTemp("", "pmgrmutate")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.RemoveAll(dir)
-
-	name := pluginName("dummy")
-	req := strings.TrimSuffix(name, filepath.Ext(name))
-	script := filepath.Join(dir, name)
 	bin := fmt.Sprintf(`#!/bin/sh
 if [ "$1" = "mutate-row" ]; then
   echo '{"success":true}';
@@ -306,11 +357,8 @@ else
   echo '{}' ;
 fi
 `)
-	if err := os.WriteFile(script, []byte(bin), 0o755); err != nil {
-		t.Fatalf("write script: %v", err)
-	}
-
-	m := &Manager{plugins: map[string]PluginInfo{req: {Path: script}}}
+	m := writeScriptPlugin(t, "dummy", bin)
+	req, name := "dummy", pluginName("dummy")
 
 	resp, err := m.MutateRow(req, nil, pluginpb.PluginV1_MutateRowRequest_INSERT, "t", nil, "")
 	if err != nil {
@@ -395,6 +443,7 @@ func TestScanOnceConcurrent(t *testing.T) {
 		t.Errorf("remaining plugin should be %s", "p2")
 	}
 }
+
 // TestPluginsReadyCallback ensures that the onPluginsReady hook is invoked
 // when the manager emits the ready event. By constructing a manager manually
 // we can set the hook before the notification is fired.
@@ -444,6 +493,65 @@ func TestRescanFiresPluginsReady(t *testing.T) {
 		t.Fatal("plugins ready callback not invoked after rescan")
 	}
 }
+
+// TestReloadReprobesChangedBinary ensures Reload re-probes a plugin whose
+// binary modtime changed and leaves unchanged plugins alone.
+func TestReloadReprobesChangedBinary(t *testing.T) {
+	dir := t.TempDir()
+	for _, base := range []string{"p1", "p2"} {
+		if err := os.WriteFile(filepath.Join(dir, pluginName(base)), []byte(""), 0o755); err != nil {
+			t.Fatalf("write dummy plugin %s: %v", base, err)
+		}
+	}
+
+	var mu sync.Mutex
+	probes := map[string]int{}
+	version := "1.0.0"
+	orig := probeInfoFunc
+	probeInfoFunc = func(fullpath string) (PluginInfo, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		base := filepath.Base(fullpath)
+		probes[strings.TrimSuffix(base, filepath.Ext(base))]++
+		return PluginInfo{Version: version}, nil
+	}
+	defer func() { probeInfoFunc = orig }()
+
+	m := &Manager{
+		plugins:    make(map[string]PluginInfo),
+		appReadyCh: make(chan struct{}),
+		dirs:       []string{dir},
+	}
+	close(m.appReadyCh)
+	m.scanOnce()
+
+	// nothing changed: no re-probe
+	if err := m.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if probes["p1"] != 1 || probes["p2"] != 1 {
+		t.Fatalf("probes after unchanged reload = %v; want one each", probes)
+	}
+
+	version = "2.0.0"
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, pluginName("p1")), later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := m.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if probes["p1"] != 2 || probes["p2"] != 1 {
+		t.Errorf("probes after touching p1 = %v; want p1 re-probed only", probes)
+	}
+	if v := m.plugins["p1"].Version; v != "2.0.0" {
+		t.Errorf("p1 version = %q; want 2.0.0", v)
+	}
+	if v := m.plugins["p2"].Version; v != "1.0.0" {
+		t.Errorf("p2 version = %q; want cached 1.0.0", v)
+	}
+}
+
 // TestPopulateUserDir verifies the standalone populateUserDir helper. It
 // simulates the bundle and user filesystem paths directly, avoiding New() so
 // the behaviour is easy to control.
@@ -605,65 +713,1476 @@ func TestUserDirPrecedence(t *testing.T) {
 	}
 }
 
+func TestValidatePluginMissingPlugin(t *testing.T) {
+	m := New()
+	if _, err := m.ValidatePlugin("nonexistent", nil, "SELECT 1"); err == nil {
+		t.Errorf("expected error for missing plugin")
+	}
+}
+
+// TestValidatePluginForwardsRequest checks that ValidatePlugin invokes the
+// `validate` command with the connection and query on stdin and decodes the
+// plugin's verdict.
+func TestValidatePluginForwardsRequest(t *testing.T) {
+	captured := filepath.Join(t.TempDir(), "stdin.json")
+	bin := fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "validate" ]; then
+  cat > %q
+  echo '{"valid":false,"message":"syntax error at or near \"SELEC\""}'
+else
+  exit 1
+fi
+`, captured)
+	m := writeScriptPlugin(t, "dummy", bin)
+	resp, err := m.ValidatePlugin("dummy", map[string]string{"credential_blob": "x"}, "SELEC 1")
+	if err != nil {
+		t.Fatalf("ValidatePlugin error: %v", err)
+	}
+	if resp.Valid || resp.Unsupported || !strings.Contains(resp.Message, "syntax error") {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	raw, err := os.ReadFile(captured)
+	if err != nil {
+		t.Fatalf("read captured stdin: %v", err)
+	}
+	var sent map[string]interface{}
+	if err := json.Unmarshal(raw, &sent); err != nil {
+		t.Fatalf("plugin received invalid json %q: %v", raw, err)
+	}
+	if sent["query"] != "SELEC 1" {
+		t.Errorf("query not forwarded: %#v", sent)
+	}
+	if conn, ok := sent["connection"].(map[string]interface{}); !ok || conn["credential_blob"] != "x" {
+		t.Errorf("connection not forwarded: %#v", sent)
+	}
+}
+
+// TestProbeInfoSurfacesPluginError ensures the JSON error envelope written by
+// ServeCLI on a failing `info` command ends up in the probe error instead of
+// a bare exit status.
+func TestProbeInfoSurfacesPluginError(t *testing.T) {
+	bin := `#!/bin/sh
+echo 'plugin: info error: driver library missing' >&2
+echo '{"error":"info error: driver library missing"}'
+exit 1
+`
+	script := writeScriptPlugin(t, "broken", bin).plugins["broken"].Path
+
+	_, err := probeInfo(script)
+	if err == nil {
+		t.Fatal("expected probe error")
+	}
+	if !strings.Contains(err.Error(), "driver library missing") {
+		t.Errorf("plugin message not surfaced: %v", err)
+	}
+}
+
+// TestProbeInfoKeepsExampleQueries checks that the editor snippets declared in
+// `info` reach PluginInfo under both the protojson and proto field names.
+func TestProbeInfoKeepsExampleQueries(t *testing.T) {
+	for _, field := range []string{"exampleQueries", "example_queries"} {
+		bin := fmt.Sprintf("#!/bin/sh\necho '{\"name\":\"Ex\",\"protocol\":\"%s\",\"%s\":[\"SHOW TABLES;\",\"SELECT 1;\"]}'\n", plugin.Protocol, field)
+		script := writeScriptPlugin(t, "examples", bin).plugins["examples"].Path
+
+		info, err := probeInfo(script)
+		if err != nil {
+			t.Fatalf("probeInfo (%s): %v", field, err)
+		}
+		want := []string{"SHOW TABLES;", "SELECT 1;"}
+		if !reflect.DeepEqual(info.ExampleQueries, want) {
+			t.Errorf("%s: ExampleQueries = %q, want %q", field, info.ExampleQueries, want)
+		}
+	}
+}
+
+// TestScanRejectsBinaryWithoutProtocolMarker ensures an executable whose
+// `info` output lacks the handshake marker, isn't JSON at all, or fails
+// without a CLIError envelope is flagged, unlisted and never run, while a
+// sibling that carries the marker is registered normally and a plugin that
+// reports its own info error stays listed.
+func TestScanRejectsBinaryWithoutProtocolMarker(t *testing.T) {
+	scripts := map[string]string{
+		"stray":  "#!/bin/sh\necho '{\"name\":\"stray\",\"version\":\"1.0\"}'\n",
+		"junk":   "#!/bin/sh\necho 'usage: junk [options]'\n",
+		"tool":   "#!/bin/sh\necho 'unknown command: info'\nexit 1\n",
+		"broken": "#!/bin/sh\necho '{\"error\":\"info error: driver library missing\"}'\nexit 1\n",
+		"real":   "#!/bin/sh\necho '{\"name\":\"Real\",\"protocol\":\"" + plugin.Protocol + "\"}'\n",
+	}
+	m := &Manager{
+		plugins:    make(map[string]PluginInfo),
+		appReadyCh: make(chan struct{}),
+		disabled:   newMemoryBlocklist(),
+	}
+	// writeScriptPlugin puts each script in a directory of its own; scan them all
+	for base, body := range scripts {
+		m.dirs = append(m.dirs, writeScriptPlugin(t, base, body).dirs...)
+	}
+	m.scanOnce()
+
+	stray := m.plugins["stray"]
+	if stray.LastError != "not a querybox plugin" {
+		t.Errorf("stray LastError = %q", stray.LastError)
+	}
+	if stray.Version != "" {
+		t.Errorf("metadata of rejected binary should be ignored: %+v", stray)
+	}
+	if real := m.plugins["real"]; real.LastError != "" || real.Name != "Real" {
+		t.Errorf("marked plugin not registered: %+v", real)
+	}
+
+	_, err := m.ExecPlugin(context.Background(), "stray", nil, "SELECT 1", nil)
+	if err == nil || !strings.Contains(err.Error(), "not a querybox plugin") {
+		t.Errorf("ExecPlugin on rejected binary: %v", err)
+	}
+
+	// output that isn't JSON keeps the decode error, and is refused as well
+	junk := m.plugins["junk"]
+	if !strings.HasPrefix(junk.LastError, "probe info failed: invalid info json: invalid character") {
+		t.Errorf("junk LastError = %q", junk.LastError)
+	}
+	_, err = m.ExecPlugin(context.Background(), "junk", nil, "SELECT 1", nil)
+	if err == nil || !strings.Contains(err.Error(), "was rejected: probe info failed: invalid info json") {
+		t.Errorf("ExecPlugin on binary with invalid info output: %v", err)
+	}
+
+	// a binary failing without the envelope is refused too
+	if tool := m.plugins["tool"]; tool.LastError != "probe info failed: exit status 1" {
+		t.Errorf("tool LastError = %q", tool.LastError)
+	}
+	_, err = m.ExecPlugin(context.Background(), "tool", nil, "SELECT 1", nil)
+	if err == nil || !strings.Contains(err.Error(), "was rejected: probe info failed: exit status 1") {
+		t.Errorf("ExecPlugin on binary failing without an envelope: %v", err)
+	}
+
+	var listed []string
+	for _, p := range m.ListPlugins() {
+		listed = append(listed, p.ID)
+	}
+	sort.Strings(listed)
+	if want := []string{"broken", "real"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("ListPlugins = %v, want %v", listed, want)
+	}
+	if broken := m.plugins["broken"]; broken.LastError != "probe info failed: info error: driver library missing" {
+		t.Errorf("broken LastError = %q", broken.LastError)
+	}
+	if d := m.Diagnostics(); len(d.Errors) != 4 {
+		t.Errorf("Diagnostics errors = %v, want all four failed probes", d.Errors)
+	}
+}
+
+// TestDisabledPluginExcludedFromListPlugins verifies that a blocklisted name
+// is skipped by scans, refused by the executor, and returns once re-enabled.
+func TestDisabledPluginExcludedFromListPlugins(t *testing.T) {
+	dir := t.TempDir()
+	for _, base := range []string{"p1", "p2"} {
+		if err := os.WriteFile(filepath.Join(dir, pluginName(base)), []byte(""), 0o755); err != nil {
+			t.Fatalf("write dummy plugin %s: %v", base, err)
+		}
+	}
+
+	orig := probeInfoFunc
+	probeInfoFunc = func(fullpath string) (PluginInfo, error) {
+		base := filepath.Base(fullpath)
+		trim := strings.TrimSuffix(base, filepath.Ext(base))
+		return PluginInfo{ID: trim, Name: trim}, nil
+	}
+	defer func() { probeInfoFunc = orig }()
+
+	m := &Manager{
+		plugins:    make(map[string]PluginInfo),
+		appReadyCh: make(chan struct{}),
+		dirs:       []string{dir},
+		disabled:   newMemoryBlocklist(),
+	}
+	m.scanOnce()
+	if len(m.ListPlugins()) != 2 {
+		t.Fatalf("expected 2 plugins before disabling, got %d", len(m.ListPlugins()))
+	}
+
+	if err := m.DisablePlugin("p1"); err != nil {
+		t.Fatalf("DisablePlugin: %v", err)
+	}
+	m.scanOnce()
+	for _, p := range m.ListPlugins() {
+		if p.ID == "p1" {
+			t.Errorf("disabled plugin still listed: %+v", p)
+		}
+	}
+	if got := m.ListDisabledPlugins(); len(got) != 1 || got[0] != "p1" {
+		t.Errorf("unexpected disabled list: %v", got)
+	}
+	if _, err := m.ExecPlugin(context.Background(), "p1", nil, "SELECT 1", nil); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("expected disabled error from ExecPlugin, got %v", err)
+	}
+
+	if err := m.EnablePlugin("p1"); err != nil {
+		t.Fatalf("EnablePlugin: %v", err)
+	}
+	m.scanOnce()
+	if len(m.ListPlugins()) != 2 {
+		t.Errorf("expected re-enabled plugin after scan, got %d plugins", len(m.ListPlugins()))
+	}
+}
+
+// TestBlocklistPersists ensures disabled names survive reopening the
+// settings database.
+func TestBlocklistPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.db")
+	b, err := openBlocklist(path)
+	if err != nil {
+		t.Fatalf("openBlocklist: %v", err)
+	}
+	if err := b.Add("mysql.exe"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	b.Close()
+
+	b2, err := openBlocklist(path)
+	if err != nil {
+		t.Fatalf("reopen blocklist: %v", err)
+	}
+	defer b2.Close()
+	if !b2.Contains("mysql") {
+		t.Errorf("disabled plugin lost after reopen: %v", b2.List())
+	}
+	if err := b2.Remove("mysql"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if b2.Contains("mysql") {
+		t.Errorf("plugin still disabled after Remove")
+	}
+}
+
+// TestSettingValuesPersist ensures stored setting values survive reopening
+// the settings database, next to the blocklist sharing the file.
+func TestSettingValuesPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.db")
+	b, err := openBlocklist(path)
+	if err != nil {
+		t.Fatalf("openBlocklist: %v", err)
+	}
+	defer b.Close()
+	s, err := openSettingValues(path)
+	if err != nil {
+		t.Fatalf("openSettingValues: %v", err)
+	}
+	if err := s.Set("mysql.exe", map[string]string{"row-cap": "50", "mode": "safe"}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set("sqlite", map[string]string{"row-cap": "10"}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	// replacing drops keys that are no longer set
+	if err := s.Set("mysql", map[string]string{"row-cap": "75"}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	s.Close()
+
+	s2, err := openSettingValues(path)
+	if err != nil {
+		t.Fatalf("reopen setting values: %v", err)
+	}
+	defer s2.Close()
+	if got := s2.Get("mysql"); !reflect.DeepEqual(got, map[string]string{"row-cap": "75"}) {
+		t.Errorf("mysql settings after reopen = %v", got)
+	}
+	if got := s2.Get("sqlite"); got["row-cap"] != "10" {
+		t.Errorf("sqlite settings after reopen = %v", got)
+	}
+	if err := s2.Set("sqlite", nil); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got := s2.Get("sqlite"); len(got) != 0 {
+		t.Errorf("sqlite settings after reset = %v", got)
+	}
+}
 
-// helper extracted from probeInfo so we can call without executing command
-func probeInfoFromRaw(raw map[string]interface{}) (PluginInfo, error) {
-	// copy logic from probeInfo, including normalization
-	// normalize camelCase keys like iconUrl -> icon_url
-	norm := make(map[string]interface{}, len(raw)+4)
-	for k, v := range raw {
-		norm[k] = v
-		switch k {
-		case "iconUrl":
-			norm["icon_url"] = v
+// TestExecPluginSendsStoredSettings checks the host-to-plugin round trip:
+// values stored for a plugin reach it as setting.<key> options, where
+// plugin.SettingValue reads them back, and caller options win.
+func TestExecPluginSendsStoredSettings(t *testing.T) {
+	captured := filepath.Join(t.TempDir(), "stdin.json")
+	bin := fmt.Sprintf("#!/bin/sh\ncat > %q\necho '{}'\n", captured)
+	m := writeScriptPlugin(t, "dummy", bin)
+	rowCap := &plugin.Setting{Key: "row-cap", DefaultValue: "1000"}
+	sent := func(options map[string]string) map[string]string {
+		t.Helper()
+		if _, err := m.ExecPlugin(context.Background(), "dummy", nil, "SELECT 1", options); err != nil {
+			t.Fatalf("ExecPlugin: %v", err)
+		}
+		raw, err := os.ReadFile(captured)
+		if err != nil {
+			t.Fatalf("read captured stdin: %v", err)
 		}
+		var req pluginpb.PluginV1_ExecRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			t.Fatalf("plugin could not decode %q: %v", raw, err)
+		}
+		return req.Options
+	}
+
+	if got := plugin.SettingValue(sent(nil), rowCap); got != "1000" {
+		t.Errorf("row-cap before storing = %q, want the default", got)
+	}
+
+	if err := m.SetPluginSettingValues("dummy", map[string]string{"row-cap": "50"}); err != nil {
+		t.Fatalf("SetPluginSettingValues: %v", err)
+	}
+	if got := m.GetPluginSettingValues("dummy"); !reflect.DeepEqual(got, map[string]string{"row-cap": "50"}) {
+		t.Errorf("GetPluginSettingValues = %v", got)
+	}
+	opts := map[string]string{"format": "table"}
+	got := sent(opts)
+	if plugin.SettingValue(got, rowCap) != "50" || got["format"] != "table" {
+		t.Errorf("plugin received options %v", got)
+	}
+	if len(opts) != 1 {
+		t.Errorf("caller's options were modified: %v", opts)
+	}
+	if got := plugin.SettingValue(sent(plugin.SettingOptions(map[string]string{"row-cap": "5"})), rowCap); got != "5" {
+		t.Errorf("row-cap passed by the caller = %q, want 5", got)
+	}
+
+	if err := m.SetPluginSettingValues("dummy", nil); err != nil {
+		t.Fatalf("SetPluginSettingValues: %v", err)
+	}
+	if got := plugin.SettingValue(sent(nil), rowCap); got != "1000" {
+		t.Errorf("row-cap after reset = %q, want the default", got)
+	}
+}
+
+// TestExecPluginHonorsContextCancel verifies that cancelling the caller's
+// context kills a long-running plugin long before the exec timeout.
+func TestExecPluginHonorsContextCancel(t *testing.T) {
+	// exec replaces the shell so killing the plugin also closes its stdout
+	bin := "#!/bin/sh\nexec sleep 30\n"
+	m := writeScriptPlugin(t, "slow", bin)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := m.ExecPlugin(ctx, "slow", nil, "SELECT 1", nil)
+	if err == nil {
+		t.Fatal("expected error after cancellation")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("plugin was not aborted promptly (took %s)", elapsed)
+	}
+}
+
+// Cancelling a call interrupts the plugin rather than killing it, so a
+// ServeCLI plugin gets the chance to cancel its statement server-side.
+func TestExecPluginInterruptsPluginOnCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are killed outright on Windows")
 	}
-	raw = norm
-
-	// interpret the type field
-	typ := 0
-	if v, ok := raw["type"]; ok {
-		switch vv := v.(type) {
-		case float64:
-			typ = int(vv)
-		case string:
-			if val, ok := pluginpb.PluginV1_Type_value[vv]; ok {
-				typ = int(val)
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	marker := filepath.Join(dir, "interrupted")
+	bin := fmt.Sprintf(`#!/bin/sh
+cat > /dev/null
+trap 'echo yes > %q; kill $! 2>/dev/null; exit 0' INT
+sleep 30 >/dev/null 2>&1 &
+touch %q
+wait
+`, marker, ready)
+	m := writeScriptPlugin(t, "slow", bin)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for i := 0; i < 500; i++ {
+			if _, err := os.Stat(ready); err == nil {
+				break
 			}
+			time.Sleep(10 * time.Millisecond)
 		}
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := m.ExecPlugin(ctx, "slow", nil, "SELECT 1", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("plugin did not receive SIGINT: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= pluginStopGrace {
+		t.Errorf("plugin was killed after the grace period instead of exiting on the interrupt (took %s)", elapsed)
+	}
+}
+
+// A plugin that ignores the interrupt is killed once pluginStopGrace runs out.
+func TestExecPluginKillsPluginIgnoringInterrupt(t *testing.T) {
+	bin := "#!/bin/sh\ncat > /dev/null\ntrap '' INT\nexec sleep 30\n"
+	m := writeScriptPlugin(t, "stubborn", bin)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := m.ExecPlugin(ctx, "stubborn", nil, "SELECT 1", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > pluginStopGrace+5*time.Second {
+		t.Errorf("plugin was not killed after the grace period (took %s)", elapsed)
+	}
+}
+
+func TestGetPluginInfoReturnsFullMetadata(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, pluginName("rich")), []byte(""), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := probeInfoFunc
+	defer func() { probeInfoFunc = orig }()
+	probeInfoFunc = func(fullpath string) (PluginInfo, error) {
+		return probeInfoFromRaw(map[string]interface{}{
+			"type":         "DRIVER",
+			"name":         "Rich",
+			"license":      "MIT",
+			"iconUrl":      "https://example.org/icon.png",
+			"capabilities": []interface{}{"explain", "transactions"},
+			"tags":         []interface{}{"sql"},
+		})
+	}
+
+	m := &Manager{
+		plugins:    make(map[string]PluginInfo),
+		appReadyCh: make(chan struct{}),
+	}
+	m.dirs = []string{dir}
+	m.Dir = dir
+	m.scanOnce()
+
+	info, err := m.GetPluginInfo(pluginName("rich"))
+	if err != nil {
+		t.Fatalf("GetPluginInfo: %v", err)
+	}
+	if !reflect.DeepEqual(info.Capabilities, []string{"explain", "transactions"}) {
+		t.Errorf("capabilities not retained: %v", info.Capabilities)
+	}
+	if !reflect.DeepEqual(info.Tags, []string{"sql"}) {
+		t.Errorf("tags not retained: %v", info.Tags)
+	}
+	if info.IconURL != "https://example.org/icon.png" || info.License != "MIT" {
+		t.Errorf("icon/license not retained: %+v", info)
+	}
+	if info.Type != int(pluginpb.PluginV1_DRIVER) {
+		t.Errorf("type not decoded: %d", info.Type)
 	}
 
-	var resp struct {
-		Name        string            `json:"name"`
-		Version     string            `json:"version"`
-		Description string            `json:"description"`
-		URL         string            `json:"url"`
-		Author      string            `json:"author"`
-		Capabilities []string         `json:"capabilities"`
-		Tags        []string          `json:"tags"`
-		License     string            `json:"license"`
-		IconURL     string            `json:"icon_url"`
-		Contact     string            `json:"contact"`
-		Metadata    map[string]string `json:"metadata"`
-		Settings    map[string]string `json:"settings"`
-	}
-	if b2, err2 := json.Marshal(raw); err2 == nil {
-		_ = json.Unmarshal(b2, &resp)
-	}
-
-	return PluginInfo{
-		Name:        resp.Name,
-		Type:        typ,
-		Version:     resp.Version,
-		Description: resp.Description,
-		URL:         resp.URL,
-		Author:      resp.Author,
-		Capabilities: resp.Capabilities,
-		Tags:        resp.Tags,
-		License:     resp.License,
-		IconURL:     resp.IconURL,
-		Contact:     resp.Contact,
-		Metadata:    resp.Metadata,
-		Settings:    resp.Settings,
-	}, nil
+	if _, err := m.GetPluginInfo("missing"); err == nil {
+		t.Error("expected error for unknown plugin")
+	}
+}
+
+func TestExecRequestMarshalsPagination(t *testing.T) {
+	b, err := json.Marshal(&execRequest{Query: "SELECT 1", Offset: 40, Limit: 20})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var req pluginpb.PluginV1_ExecRequest
+	if err := json.Unmarshal(b, &req); err != nil {
+		t.Fatalf("plugin could not decode %s: %v", b, err)
+	}
+	if req.GetOffset() != 40 || req.GetLimit() != 20 {
+		t.Errorf("offset/limit = %d/%d, want 40/20", req.GetOffset(), req.GetLimit())
+	}
+
+	// zero values are omitted so older plugins see the historical envelope
+	b, err = json.Marshal(&execRequest{Query: "SELECT 1"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(b), "offset") || strings.Contains(string(b), "limit") {
+		t.Errorf("unpaginated request should omit offset/limit: %s", b)
+	}
+}
+
+// recordingEmitter captures emitted events for assertions.
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []string
+	data   []interface{}
+}
+
+func (r *recordingEmitter) EmitEvent(name string, data interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, name)
+	r.data = append(r.data, data)
+}
+
+func TestExecTreeActionInvalidatesTreeAfterDDL(t *testing.T) {
+	bin := "#!/bin/sh\ncat >/dev/null\necho '{}'\n"
+	m := writeScriptPlugin(t, "ddl", bin)
+
+	tests := []struct {
+		actionType string
+		invalidate bool
+	}{
+		{"drop-table", true},
+		{"create-database", true},
+		{"select", false},
+		{"describe", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.actionType, func(t *testing.T) {
+			rec := &recordingEmitter{}
+			m.emitter = rec
+			if _, err := m.ExecTreeAction(context.Background(), "ddl", "conn-1", nil, tt.actionType, "DROP TABLE t;", nil); err != nil {
+				t.Fatalf("ExecTreeAction: %v", err)
+			}
+			var got []services.TreeInvalidateEvent
+			for i, name := range rec.events {
+				if name == services.EventTreeInvalidate {
+					got = append(got, rec.data[i].(services.TreeInvalidateEvent))
+				}
+			}
+			if !tt.invalidate {
+				if len(got) != 0 {
+					t.Errorf("unexpected invalidation: %+v", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].ConnectionID != "conn-1" || got[0].ActionType != tt.actionType {
+				t.Errorf("expected one tree:invalidate for conn-1, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestExecPluginConcurrencyLimit(t *testing.T) {
+	dir := t.TempDir()
+	trace := filepath.Join(dir, "trace")
+	// each run appends "s" on start and "e" on exit; with O_APPEND the
+	// file order reflects how many processes overlapped
+	bin := fmt.Sprintf("#!/bin/sh\ncat >/dev/null\necho s >> %q\nsleep 0.2\necho e >> %q\necho '{}'\n", trace, trace)
+	m := writeScriptPlugin(t, "busy", bin)
+
+	const limit = 2
+	m.SetMaxConcurrentExecs(limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.ExecPlugin(context.Background(), "busy", nil, "SELECT 1", nil); err != nil {
+				t.Errorf("ExecPlugin: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	raw, err := os.ReadFile(trace)
+	if err != nil {
+		t.Fatalf("read trace: %v", err)
+	}
+	active, maxActive, starts := 0, 0, 0
+	for _, line := range strings.Fields(string(raw)) {
+		switch line {
+		case "s":
+			active++
+			starts++
+		case "e":
+			active--
+		}
+		if active > maxActive {
+			maxActive = active
+		}
+	}
+	if starts != 6 {
+		t.Fatalf("expected 6 plugin runs, got %d", starts)
+	}
+	if maxActive > limit {
+		t.Errorf("%d plugin processes overlapped; limit is %d", maxActive, limit)
+	}
+}
+
+func TestExecPluginQueuedCallHonorsCancel(t *testing.T) {
+	m := &Manager{plugins: map[string]PluginInfo{}}
+	m.SetMaxConcurrentExecs(1)
+	release, err := m.acquireExecSlot(context.Background(), "test", "busy")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := m.ExecPlugin(ctx, "busy", nil, "SELECT 1", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected queued call to give up with the context, got %v", err)
+	}
+}
+
+func TestTestConnectionFormsReportsPerForm(t *testing.T) {
+	// only the "dsn" form connects successfully
+	bin := `#!/bin/sh
+if [ "$1" = "test-connection" ]; then
+  if grep -q '"form":"dsn"'; then
+    echo '{"ok":true,"message":"Connection successful"}'
+  else
+    echo '{"ok":false,"message":"ping error"}'
+  fi
+else
+  exit 1
+fi
+`
+	m := writeScriptPlugin(t, "dummy", bin)
+	results, err := m.TestConnectionForms("dummy", map[string]string{"credential_blob": "x"}, []string{"basic", "dsn"})
+	if err != nil {
+		t.Fatalf("TestConnectionForms error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results["basic"] == nil || results["basic"].Ok {
+		t.Errorf("basic form: unexpected result %+v", results["basic"])
+	}
+	if results["dsn"] == nil || !results["dsn"].Ok {
+		t.Errorf("dsn form: unexpected result %+v", results["dsn"])
+	}
+}
+
+func TestTestConnectionFormsMissingPlugin(t *testing.T) {
+	m := &Manager{plugins: map[string]PluginInfo{}}
+	if _, err := m.TestConnectionForms("nope", nil, []string{"basic"}); err == nil {
+		t.Fatal("expected error for missing plugin")
+	}
+}
+
+func TestExecPluginRendersNonEnvelopeOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		wantKV   map[string]string
+		wantKeys []string
+		wantCell string
+	}{
+		{
+			name:   "unrecognised object",
+			output: `{"status":"ok","count":3,"nested":{"a":[1,2]}}`,
+			wantKV: map[string]string{
+				"status": "ok",
+				"count":  "3",
+				"nested": "{\n  \"a\": [\n    1,\n    2\n  ]\n}",
+			},
+			wantKeys: []string{"status", "count", "nested"},
+		},
+		{
+			name:     "repeated key",
+			output:   `{"a":1,"b":2,"a":3}`,
+			wantKV:   map[string]string{"a": "3", "b": "2"},
+			wantKeys: []string{"a", "b"},
+		},
+		{name: "plain text", output: "server version 8.0.36", wantCell: "server version 8.0.36"},
+		{name: "json array", output: `[1,"two"]`, wantCell: "[\n  1,\n  \"two\"\n]"},
+		{name: "truncated json", output: `{"result":{"sql":`, wantCell: `{"result":{"sql":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := fmt.Sprintf("#!/bin/sh\ncat > /dev/null\nprintf '%%s\\n' %q\n", tt.output)
+			m := writeScriptPlugin(t, "dummy", bin)
+			resp, err := m.ExecPlugin(context.Background(), "dummy", nil, "q", nil)
+			if err != nil {
+				t.Fatalf("ExecPlugin error: %v", err)
+			}
+			if tt.wantKV != nil {
+				kv := resp.GetResult().GetKv()
+				if kv == nil {
+					t.Fatalf("expected kv result, got %v", resp.GetResult())
+				}
+				if !reflect.DeepEqual(kv.Data, tt.wantKV) {
+					t.Errorf("kv = %#v, want %#v", kv.Data, tt.wantKV)
+				}
+				if !reflect.DeepEqual(kv.Keys, tt.wantKeys) {
+					t.Errorf("kv keys = %v, want %v", kv.Keys, tt.wantKeys)
+				}
+				return
+			}
+			sqlRes := resp.GetResult().GetSql()
+			if sqlRes == nil || len(sqlRes.Columns) != 1 || len(sqlRes.Rows) != 1 {
+				t.Fatalf("expected single-cell table, got %v", resp.GetResult())
+			}
+			if got := sqlRes.Rows[0].Values[0]; got != tt.wantCell {
+				t.Errorf("cell = %q, want %q", got, tt.wantCell)
+			}
+		})
+	}
+}
+
+func TestExecPluginRoundTripsMetadata(t *testing.T) {
+	bin := `#!/bin/sh
+cat > /dev/null
+echo '{"result":{"sql":{"columns":[{"name":"n"}],"rows":[{"values":["1"]}]},"warnings":["w"],"metadata":{"duration_ms":"12","index_used":"idx_email"}}}'
+`
+	m := writeScriptPlugin(t, "dummy", bin)
+
+	resp, err := m.ExecPlugin(context.Background(), "dummy", nil, "SELECT 1", nil)
+	if err != nil {
+		t.Fatalf("ExecPlugin: %v", err)
+	}
+	want := map[string]string{"duration_ms": "12", "index_used": "idx_email"}
+	if got := resp.GetResult().GetMetadata(); !reflect.DeepEqual(got, want) {
+		t.Errorf("metadata = %v, want %v", got, want)
+	}
+	if rows := resp.GetResult().GetSql().GetRows(); len(rows) != 1 {
+		t.Errorf("payload lost alongside metadata: %v", resp.GetResult())
+	}
+}
+
+func TestGenerateDDLMissingPlugin(t *testing.T) {
+	m := &Manager{plugins: map[string]PluginInfo{}}
+	if _, err := m.GenerateDDL("nonexistent", nil, "public.users"); err == nil {
+		t.Fatal("expected error for missing plugin")
+	}
+}
+
+func TestGenerateDDLForwardsRequest(t *testing.T) {
+	dir := t.TempDir()
+	captured := filepath.Join(dir, "stdin.json")
+
+	bin := fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "ddl" ]; then
+  cat > %q
+  echo '{"ddl":"CREATE TABLE \"public\".\"users\" (id integer);"}'
+else
+  exit 1
+fi
+`, captured)
+	m := writeScriptPlugin(t, "dummy", bin)
+
+	resp, err := m.GenerateDDL("dummy", map[string]string{"credential_blob": "x"}, "public.users")
+	if err != nil {
+		t.Fatalf("GenerateDDL error: %v", err)
+	}
+	if !strings.HasPrefix(resp.Ddl, "CREATE TABLE") || resp.Unsupported {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	raw, err := os.ReadFile(captured)
+	if err != nil {
+		t.Fatalf("read captured stdin: %v", err)
+	}
+	var sent pluginpb.PluginV1_GenerateDDLRequest
+	if err := json.Unmarshal(raw, &sent); err != nil {
+		t.Fatalf("plugin could not decode %q: %v", raw, err)
+	}
+	if sent.NodeKey != "public.users" || sent.Connection["credential_blob"] != "x" {
+		t.Errorf("request not forwarded: %+v", &sent)
+	}
+}
+
+func TestGenerateDDLPluginError(t *testing.T) {
+	bin := "#!/bin/sh\ncat > /dev/null\necho '{\"error\":\"table \\\"nope\\\" not found\"}'\n"
+	m := writeScriptPlugin(t, "dummy", bin)
+
+	resp, err := m.GenerateDDL("dummy", nil, "nope")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected plugin error, got %v", err)
+	}
+	if resp == nil || resp.Error == "" {
+		t.Errorf("expected response carrying the error, got %+v", resp)
+	}
+}
+
+func TestExecPluginStripsLogLines(t *testing.T) {
+	bin := `#!/bin/sh
+cat >/dev/null
+echo '#log driver: server certificate expires in 3 days'
+echo '{"result":{"kv":{"data":{"k":"v"}}}}'
+echo '#log done'
+`
+	m := writeScriptPlugin(t, "noisy", bin)
+
+	rec := &recordingEmitter{}
+	m.emitter = rec
+	resp, err := m.ExecPlugin(context.Background(), "noisy", nil, "q", nil)
+	if err != nil {
+		t.Fatalf("ExecPlugin: %v", err)
+	}
+	if got := resp.GetResult().GetKv().GetData()["k"]; got != "v" {
+		t.Fatalf("log lines corrupted the response: %v", resp.GetResult())
+	}
+
+	var logged []string
+	for i, name := range rec.events {
+		if name == services.EventAppLog {
+			logged = append(logged, rec.data[i].(services.LogEntry).Message)
+		}
+	}
+	want := []string{
+		"ExecPlugin: plugin 'noisy': driver: server certificate expires in 3 days",
+		"ExecPlugin: plugin 'noisy': done",
+	}
+	for _, w := range want {
+		found := false
+		for _, l := range logged {
+			found = found || l == w
+		}
+		if !found {
+			t.Errorf("missing log entry %q in %q", w, logged)
+		}
+	}
+}
+
+func TestGetConnectionTreeStripsLogLines(t *testing.T) {
+	bin := "#!/bin/sh\ncat >/dev/null\necho '#log loading schemas'\necho '{\"nodes\":[{\"key\":\"db\",\"label\":\"db\"}]}'\n"
+	m := writeScriptPlugin(t, "noisy", bin)
+
+	resp, err := m.GetConnectionTree(context.Background(), "noisy", nil, 0)
+	if err != nil {
+		t.Fatalf("GetConnectionTree: %v", err)
+	}
+	if len(resp.Nodes) != 1 || resp.Nodes[0].Key != "db" {
+		t.Errorf("unexpected nodes: %v", resp.Nodes)
+	}
+}
+
+func TestGetConnectionTreeForwardsMaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	captured := filepath.Join(dir, "stdin.json")
+	bin := fmt.Sprintf("#!/bin/sh\ncat > %q\necho '{}'\n", captured)
+	m := writeScriptPlugin(t, "dummy", bin)
+
+	if _, err := m.GetConnectionTree(context.Background(), "dummy", map[string]string{"dsn": "x"}, 2); err != nil {
+		t.Fatalf("GetConnectionTree: %v", err)
+	}
+	raw, err := os.ReadFile(captured)
+	if err != nil {
+		t.Fatalf("read captured stdin: %v", err)
+	}
+	var req plugin.ConnectionTreeRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		t.Fatalf("decode request: %v\n%s", err, raw)
+	}
+	if req.GetMaxDepth() != 2 || req.GetConnection()["dsn"] != "x" {
+		t.Errorf("plugin received %s", raw)
+	}
+}
+
+func TestExecPluginForwardsFormatOption(t *testing.T) {
+	dir := t.TempDir()
+	captured := filepath.Join(dir, "stdin.json")
+	bin := fmt.Sprintf("#!/bin/sh\ncat > %q\necho '{}'\n", captured)
+	m := writeScriptPlugin(t, "dummy", bin)
+
+	if _, err := m.ExecPlugin(context.Background(), "dummy", nil, "SELECT 1", map[string]string{"format": "table"}); err != nil {
+		t.Fatalf("ExecPlugin: %v", err)
+	}
+	raw, err := os.ReadFile(captured)
+	if err != nil {
+		t.Fatalf("read captured stdin: %v", err)
+	}
+	var sent pluginpb.PluginV1_ExecRequest
+	if err := json.Unmarshal(raw, &sent); err != nil {
+		t.Fatalf("plugin could not decode %q: %v", raw, err)
+	}
+	if sent.Options["format"] != "table" {
+		t.Errorf("format option not forwarded: %q", raw)
+	}
+}
+
+// flakyExecPlugin registers a plugin that answers its first exec call with
+// firstResponse and every later call with a successful result. It returns
+// the manager and a func reporting how many times the plugin ran.
+func flakyExecPlugin(t *testing.T, firstResponse string) (*Manager, func() int) {
+	t.Helper()
+	counter := filepath.Join(t.TempDir(), "calls")
+	bin := fmt.Sprintf(`#!/bin/sh
+cat >/dev/null
+echo x >> %q
+if [ "$(wc -l < %q)" -eq 1 ]; then
+  echo %q
+else
+  echo '{"result":{"kv":{"data":{"ok":"yes"}}}}'
+fi
+`, counter, counter, firstResponse)
+	m := writeScriptPlugin(t, "flaky", bin)
+	calls := func() int {
+		b, _ := os.ReadFile(counter)
+		return strings.Count(string(b), "\n")
+	}
+	return m, calls
+}
+
+func TestExecPluginRetriesTransientFailure(t *testing.T) {
+	m, calls := flakyExecPlugin(t, `{"error":"dial tcp: connection refused","errorCode":"ERROR_CODE_CONNECTION_FAILED"}`)
+	m.SetRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	resp, err := m.ExecPlugin(context.Background(), "flaky", nil, "SELECT 1", nil)
+	if err != nil {
+		t.Fatalf("ExecPlugin: %v", err)
+	}
+	if resp.GetResult().GetKv().GetData()["ok"] != "yes" {
+		t.Errorf("expected the retried result, got %v", resp)
+	}
+	if n := calls(); n != 2 {
+		t.Errorf("plugin ran %d time(s), want 2", n)
+	}
+}
+
+func TestExecPluginDoesNotRetryDeterministicFailure(t *testing.T) {
+	m, calls := flakyExecPlugin(t, `{"error":"syntax error at or near \"SELEC\"","errorCode":"ERROR_CODE_SYNTAX_ERROR"}`)
+	m.SetRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	if _, err := m.ExecPlugin(context.Background(), "flaky", nil, "SELEC 1", nil); err == nil {
+		t.Fatal("expected the syntax error to be returned")
+	}
+	if n := calls(); n != 1 {
+		t.Errorf("plugin ran %d time(s), want 1", n)
+	}
+}
+
+// TestExecPluginDoesNotRetryDroppedConnection guards non-idempotent
+// statements: a connection lost mid-statement is CONNECTION_FAILED but the
+// statement may already have run, so it must not be repeated.
+func TestExecPluginDoesNotRetryDroppedConnection(t *testing.T) {
+	m, calls := flakyExecPlugin(t, `{"error":"read tcp 127.0.0.1:5432: connection reset by peer","errorCode":"ERROR_CODE_CONNECTION_FAILED"}`)
+	m.SetRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	if _, err := m.ExecPlugin(context.Background(), "flaky", nil, "INSERT INTO t VALUES (1)", nil); err == nil {
+		t.Fatal("expected the dropped connection to be returned")
+	}
+	if n := calls(); n != 1 {
+		t.Errorf("plugin ran %d time(s), want 1", n)
+	}
+}
+
+func TestExecPluginRetriesDisabledByDefault(t *testing.T) {
+	m, calls := flakyExecPlugin(t, `{"error":"dial tcp: connection refused","errorCode":"ERROR_CODE_CONNECTION_FAILED"}`)
+	if _, err := m.ExecPlugin(context.Background(), "flaky", nil, "SELECT 1", nil); err == nil {
+		t.Fatal("expected the connection failure to be returned")
+	}
+	if n := calls(); n != 1 {
+		t.Errorf("plugin ran %d time(s), want 1", n)
+	}
+}
+
+func TestTestConnectionMissingPlugin(t *testing.T) {
+	m := &Manager{plugins: map[string]PluginInfo{}}
+	resp, err := m.TestConnection("nosuch", map[string]string{"dsn": "x"})
+	if err == nil || !strings.Contains(err.Error(), "plugin nosuch not found") {
+		t.Errorf("expected a not-found error, got %v (resp %v)", err, resp)
+	}
+}
+
+// TestTestConnectionUnsavedConnection covers the connection form's "Test"
+// button: an arbitrary connection map goes to `test-connection` without any
+// stored connection behind it.
+func TestTestConnectionUnsavedConnection(t *testing.T) {
+	dir := t.TempDir()
+	captured := filepath.Join(dir, "stdin.json")
+	bin := fmt.Sprintf(`#!/bin/sh
+[ "$1" = "test-connection" ] || exit 1
+cat > %q
+echo '{"ok":true,"message":"Connection successful"}'
+`, captured)
+	m := writeScriptPlugin(t, "dummy", bin)
+
+	conn := map[string]string{"credential_blob": `{"form":"basic","values":{"host":"db"}}`}
+	resp, err := m.TestConnection("dummy", conn)
+	if err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+	if !resp.GetOk() || resp.GetMessage() != "Connection successful" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	raw, err := os.ReadFile(captured)
+	if err != nil {
+		t.Fatalf("read captured stdin: %v", err)
+	}
+	var req plugin.TestConnectionRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		t.Fatalf("decode request: %v\n%s", err, raw)
+	}
+	if !reflect.DeepEqual(req.GetConnection(), conn) {
+		t.Errorf("plugin received connection %v, want %v", req.GetConnection(), conn)
+	}
+}
+
+// TestTestConnectionDecodesLatencyAndServerInfo guards the protojson decode:
+// latency_ms is an int64, which protojson writes as a JSON string.
+func TestTestConnectionDecodesLatencyAndServerInfo(t *testing.T) {
+	bin := `#!/bin/sh
+[ "$1" = "test-connection" ] || exit 1
+cat > /dev/null
+echo '{"ok":true,"message":"Connection successful","latencyMs":"12","serverInfo":{"version":"16.2"}}'
+`
+	m := writeScriptPlugin(t, "dummy", bin)
+
+	resp, err := m.TestConnection("dummy", map[string]string{"dsn": "x"})
+	if err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+	if !resp.GetOk() {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.GetLatencyMs() != 12 {
+		t.Errorf("latency = %d, want 12", resp.GetLatencyMs())
+	}
+	if got := resp.GetServerInfo()["version"]; got != "16.2" {
+		t.Errorf("server version = %q, want 16.2", got)
+	}
+}
+
+func TestTestConnectionRetriesTransientFailure(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	bin := fmt.Sprintf(`#!/bin/sh
+cat >/dev/null
+echo x >> %q
+if [ "$(wc -l < %q)" -eq 1 ]; then
+  echo '{"ok":false,"message":"ping error: dial tcp 127.0.0.1:5432: connect: connection refused"}'
+else
+  echo '{"ok":true,"message":"Connection successful"}'
+fi
+`, counter, counter)
+	m := writeScriptPlugin(t, "flaky", bin)
+
+	m.SetRetryPolicy(RetryPolicy{Attempts: 2, Backoff: time.Millisecond})
+	resp, err := m.TestConnection("flaky", nil)
+	if err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+	if !resp.Ok {
+		t.Errorf("expected success on the second attempt, got %+v", resp)
+	}
+}
+
+func TestDiagnosticsReflectsRegistry(t *testing.T) {
+	m := &Manager{
+		dirs: []string{"/user/plugins", "/bundle/plugins"},
+		plugins: map[string]PluginInfo{
+			"mysql":  {ID: "mysql", Name: "MySQL"},
+			"sqlite": {ID: "sqlite", LastError: "probe info failed: exit status 1"},
+			"stray":  {ID: "stray", LastError: "not a querybox plugin"},
+		},
+	}
+	m.SetCredentialBackend("sqlite")
+
+	d := m.Diagnostics()
+	if !reflect.DeepEqual(d.PluginDirs, []string{"/user/plugins", "/bundle/plugins"}) {
+		t.Errorf("PluginDirs = %v", d.PluginDirs)
+	}
+	if d.PluginCount != 3 {
+		t.Errorf("PluginCount = %d, want 3", d.PluginCount)
+	}
+	wantErrs := map[string]string{
+		"sqlite": "probe info failed: exit status 1",
+		"stray":  "not a querybox plugin",
+	}
+	if !reflect.DeepEqual(d.Errors, wantErrs) {
+		t.Errorf("Errors = %v, want %v", d.Errors, wantErrs)
+	}
+	if d.CredentialBackend != "sqlite" {
+		t.Errorf("CredentialBackend = %q", d.CredentialBackend)
+	}
+
+	// the returned slice must not alias the manager's state
+	d.PluginDirs[0] = "changed"
+	if m.Diagnostics().PluginDirs[0] != "/user/plugins" {
+		t.Error("Diagnostics exposed the manager's dirs slice")
+	}
+}
+
+// TestDiagnosticsReportsUnreadablePluginDir points the manager at a path it
+// cannot list (a regular file, so the check also holds when running as
+// root) and expects the failure in Diagnostics instead of a silently empty
+// plugin list. A directory that simply does not exist is not reported.
+func TestDiagnosticsReportsUnreadablePluginDir(t *testing.T) {
+	tmp := t.TempDir()
+	unreadable := filepath.Join(tmp, "plugins")
+	if err := os.WriteFile(unreadable, []byte("not a directory"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(tmp, "missing")
+
+	m := &Manager{
+		plugins: make(map[string]PluginInfo),
+		dirs:    []string{unreadable, missing},
+	}
+	m.scanOnce()
+
+	d := m.Diagnostics()
+	if d.PluginCount != 0 {
+		t.Errorf("PluginCount = %d, want 0", d.PluginCount)
+	}
+	if _, ok := d.DirErrors[unreadable]; !ok {
+		t.Errorf("DirErrors = %v, want an entry for %s", d.DirErrors, unreadable)
+	}
+	if _, ok := d.DirErrors[missing]; ok {
+		t.Errorf("missing directory reported: %v", d.DirErrors)
+	}
+
+	// the error clears once the directory becomes readable
+	if err := os.Remove(unreadable); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(unreadable, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	m.scanOnce()
+	if d := m.Diagnostics(); len(d.DirErrors) != 0 {
+		t.Errorf("DirErrors after fix = %v, want none", d.DirErrors)
+	}
+}
+
+// fakeConnectionSource is an in-memory ConnectionSource keyed by id.
+type fakeConnectionSource struct {
+	conns    map[string]services.Connection
+	creds    map[string]string
+	statuses map[string]services.ConnectionStatus
+}
+
+func (f *fakeConnectionSource) GetConnection(_ context.Context, id string) (services.Connection, error) {
+	c, ok := f.conns[id]
+	if !ok {
+		return services.Connection{}, errors.New("database connection not found")
+	}
+	return c, nil
+}
+
+func (f *fakeConnectionSource) GetCredential(_ context.Context, id string) (string, error) {
+	return f.creds[id], nil
+}
+
+func (f *fakeConnectionSource) RecordConnectionStatus(_ context.Context, id string, ok bool, message string) (services.ConnectionStatus, error) {
+	st := services.ConnectionStatus{ConnectionID: id, OK: ok, Message: message}
+	if f.statuses == nil {
+		f.statuses = make(map[string]services.ConnectionStatus)
+	}
+	f.statuses[id] = st
+	return st, nil
+}
+
+func TestResolveConnection(t *testing.T) {
+	src := &fakeConnectionSource{
+		conns: map[string]services.Connection{
+			"c1":      {ID: "c1", DriverType: "postgresql"},
+			"no-cred": {ID: "no-cred", DriverType: "sqlite"},
+			"broken":  {ID: "broken"},
+		},
+		creds: map[string]string{"c1": `{"form":"basic","values":{"host":"db"}}`},
+	}
+	m := &Manager{}
+	if _, _, err := m.resolveConnection(context.Background(), "RunQuery", "c1"); err == nil {
+		t.Error("expected an error without a connection source")
+	}
+	m.SetConnectionSource(src)
+
+	tests := []struct {
+		id         string
+		wantDriver string
+		wantConn   map[string]string
+		wantErr    bool
+	}{
+		{"c1", "postgresql", map[string]string{"credential_blob": `{"form":"basic","values":{"host":"db"}}`}, false},
+		{"no-cred", "sqlite", map[string]string{}, false},
+		{"broken", "", nil, true},
+		{"missing", "", nil, true},
+	}
+	for _, tt := range tests {
+		stored, conn, err := m.resolveConnection(context.Background(), "RunQuery", tt.id)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.id, err, tt.wantErr)
+			continue
+		}
+		if driver := stored.DriverType; driver != tt.wantDriver || !reflect.DeepEqual(conn, tt.wantConn) {
+			t.Errorf("%s: got (%q, %v), want (%q, %v)", tt.id, driver, conn, tt.wantDriver, tt.wantConn)
+		}
+	}
+}
+
+// TestRunQueryResolvesStoredConnection runs a query by connection id and
+// checks the plugin matching the stored driver receives the stored
+// credential as credential_blob.
+func TestRunQueryResolvesStoredConnection(t *testing.T) {
+	dir := t.TempDir()
+	captured := filepath.Join(dir, "stdin.json")
+	bin := fmt.Sprintf("#!/bin/sh\ncat > %q\necho '{\"result\":{\"kv\":{\"data\":{\"ok\":\"yes\"}}}}'\n", captured)
+	m := writeScriptPlugin(t, "postgresql", bin)
+
+	const blob = `{"form":"basic","values":{"host":"db"}}`
+	m.SetConnectionSource(&fakeConnectionSource{
+		conns: map[string]services.Connection{"c1": {ID: "c1", DriverType: "postgresql"}},
+		creds: map[string]string{"c1": blob},
+	})
+
+	resp, err := m.RunQuery(context.Background(), "c1", "SELECT 1", map[string]string{"explain-query": "yes"})
+	if err != nil {
+		t.Fatalf("RunQuery: %v", err)
+	}
+	if resp.GetResult().GetKv().GetData()["ok"] != "yes" {
+		t.Errorf("unexpected response: %v", resp)
+	}
+	raw, err := os.ReadFile(captured)
+	if err != nil {
+		t.Fatalf("read captured stdin: %v", err)
+	}
+	var sent pluginpb.PluginV1_ExecRequest
+	if err := json.Unmarshal(raw, &sent); err != nil {
+		t.Fatalf("plugin could not decode %q: %v", raw, err)
+	}
+	if sent.Connection["credential_blob"] != blob || sent.Query != "SELECT 1" || sent.Options["explain-query"] != "yes" {
+		t.Errorf("plugin received %q", raw)
+	}
+
+	os.Remove(captured)
+	if _, err := m.RunQuery(context.Background(), "missing", "SELECT 1", nil); err == nil {
+		t.Error("expected an error for an unknown connection id")
+	}
+	if _, err := os.Stat(captured); err == nil {
+		t.Error("plugin should not run for an unknown connection id")
+	}
+}
+
+func TestWarmupForwardsAndCaches(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	bin := fmt.Sprintf(`#!/bin/sh
+cat >/dev/null
+if [ "$1" = "test-connection" ]; then
+  echo x >> %q
+  echo '{"ok":true,"message":"Connection successful"}'
+else
+  exit 1
+fi
+`, counter)
+	m := writeScriptPlugin(t, "warm", bin)
+	calls := func() int {
+		b, _ := os.ReadFile(counter)
+		return strings.Count(string(b), "\n")
+	}
+
+	conn := map[string]string{"credential_blob": "a"}
+	for i := 0; i < 2; i++ {
+		resp, err := m.Warmup("warm", conn)
+		if err != nil {
+			t.Fatalf("Warmup: %v", err)
+		}
+		if !resp.GetOk() {
+			t.Fatalf("expected ok response, got %v", resp)
+		}
+		// callers get their own copy; editing it leaves the cache intact
+		resp.Ok = false
+		resp.Message = "edited"
+	}
+	if n := calls(); n != 1 {
+		t.Fatalf("plugin ran %d time(s) for a repeated warmup, want 1", n)
+	}
+
+	// a different connection is not served from the cache
+	if _, err := m.Warmup("warm", map[string]string{"credential_blob": "b"}); err != nil {
+		t.Fatalf("Warmup: %v", err)
+	}
+	if n := calls(); n != 2 {
+		t.Fatalf("plugin ran %d time(s), want 2", n)
+	}
+
+	// once the entry has expired the plugin is asked again
+	key := warmupKey("warm", conn)
+	e := m.warmups.entries[key]
+	e.at = e.at.Add(-warmupTTL)
+	m.warmups.entries[key] = e
+	if _, err := m.Warmup("warm", conn); err != nil {
+		t.Fatalf("Warmup: %v", err)
+	}
+	if n := calls(); n != 3 {
+		t.Fatalf("plugin ran %d time(s) after expiry, want 3", n)
+	}
+
+	// failures to run the plugin are returned and not cached
+	if _, err := m.Warmup("missing", conn); err == nil {
+		t.Fatal("expected error for missing plugin")
+	}
+	if _, ok := m.warmups.entries[warmupKey("missing", conn)]; ok {
+		t.Error("error result should not be cached")
+	}
+}
+
+func TestWarmupConnectionRecordsStatus(t *testing.T) {
+	bin := `#!/bin/sh
+cat >/dev/null
+echo '{"ok":false,"message":"password authentication failed"}'
+`
+	m := writeScriptPlugin(t, "warm", bin)
+	src := &fakeConnectionSource{
+		conns: map[string]services.Connection{
+			"c1":   {ID: "c1", DriverType: "warm"},
+			"gone": {ID: "gone", DriverType: "missing"},
+		},
+	}
+	m.SetConnectionSource(src)
+
+	resp, err := m.WarmupConnection(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("WarmupConnection: %v", err)
+	}
+	if resp.GetOk() {
+		t.Fatalf("expected failed response, got %v", resp)
+	}
+	want := services.ConnectionStatus{ConnectionID: "c1", OK: false, Message: "password authentication failed"}
+	if got := src.statuses["c1"]; got != want {
+		t.Errorf("status = %+v, want %+v", got, want)
+	}
+
+	// a plugin that cannot run is recorded as a failed check
+	if _, err := m.WarmupConnection(context.Background(), "gone"); err == nil {
+		t.Fatal("expected error for missing plugin")
+	}
+	if st, ok := src.statuses["gone"]; !ok || st.OK || st.Message == "" {
+		t.Errorf("status = %+v, want a failure with the error message", st)
+	}
+}
+
+func TestExecPluginErrorCarriesQueryContext(t *testing.T) {
+	bin := `#!/bin/sh
+cat >/dev/null
+echo '{"error":"syntax error at or near \"SELEC\"","errorCode":"ERROR_CODE_SYNTAX_ERROR"}'
+`
+	m := writeScriptPlugin(t, "failing", bin)
+
+	query := "SELEC " + strings.Repeat("x", 100)
+	_, err := m.ExecPlugin(context.Background(), "failing", nil, query, nil)
+	var execErr *ExecError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("expected *ExecError, got %T: %v", err, err)
+	}
+	if execErr.Driver != "failing" {
+		t.Errorf("Driver = %q", execErr.Driver)
+	}
+	if execErr.Query != query[:80]+"..." {
+		t.Errorf("Query = %q, want the first 80 bytes", execErr.Query)
+	}
+	if execErr.Code != plugin.ErrorCodeSyntaxError.String() {
+		t.Errorf("Code = %q", execErr.Code)
+	}
+	if !strings.Contains(execErr.Error(), `syntax error at or near "SELEC"`) {
+		t.Errorf("message lost: %v", execErr)
+	}
+
+	// failures to run the plugin are wrapped too and stay unwrappable
+	_, err = m.ExecPlugin(context.Background(), "missing", nil, "SELECT 1", nil)
+	if !errors.As(err, &execErr) || execErr.Driver != "missing" || execErr.Query != "SELECT 1" {
+		t.Fatalf("expected ExecError for a missing plugin, got %v", err)
+	}
+	if execErr.Unwrap() == nil {
+		t.Error("ExecError should wrap the underlying error")
+	}
+}
+
+// TestPluginEnvCarriesConnectionLabel checks that the id-based entry points
+// tell the plugin which stored connection it serves, and that direct calls
+// set nothing.
+func TestPluginEnvCarriesConnectionLabel(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	bin := fmt.Sprintf(`#!/bin/sh
+cat >/dev/null
+echo "id=$QUERYBOX_CONNECTION_ID name=$QUERYBOX_CONNECTION_NAME" > %q
+if [ "$1" = "connection-tree" ]; then
+  echo '{"nodes":[]}'
+else
+  echo '{"result":{"kv":{"data":{"ok":"yes"}}}}'
+fi
+`, envFile)
+	m := writeScriptPlugin(t, "sqlite", bin)
+	readEnv := func() string {
+		t.Helper()
+		b, err := os.ReadFile(envFile)
+		if err != nil {
+			t.Fatalf("read env: %v", err)
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	m.SetConnectionSource(&fakeConnectionSource{
+		conns: map[string]services.Connection{"c1": {ID: "c1", Name: "Local cache", DriverType: "sqlite"}},
+	})
+
+	if _, err := m.RunQuery(context.Background(), "c1", "SELECT 1", nil); err != nil {
+		t.Fatalf("RunQuery: %v", err)
+	}
+	if got := readEnv(); got != "id=c1 name=Local cache" {
+		t.Errorf("RunQuery env = %q", got)
+	}
+
+	if _, err := m.ExecTreeAction(context.Background(), "sqlite", "c1", nil, "select", "SELECT 1", nil); err != nil {
+		t.Fatalf("ExecTreeAction: %v", err)
+	}
+	if got := readEnv(); got != "id=c1 name=Local cache" {
+		t.Errorf("ExecTreeAction env = %q", got)
+	}
+
+	if _, err := m.GetStoredConnectionTree(context.Background(), "c1", 0); err != nil {
+		t.Fatalf("GetStoredConnectionTree: %v", err)
+	}
+	if got := readEnv(); got != "id=c1 name=Local cache" {
+		t.Errorf("GetStoredConnectionTree env = %q", got)
+	}
+
+	// an id the source doesn't know still reaches the plugin, without a name
+	if _, err := m.ExecTreeAction(context.Background(), "sqlite", "gone", nil, "select", "SELECT 1", nil); err != nil {
+		t.Fatalf("ExecTreeAction: %v", err)
+	}
+	if got := readEnv(); got != "id=gone name=" {
+		t.Errorf("ExecTreeAction env for an unknown id = %q", got)
+	}
+
+	if _, err := m.ExecPlugin(context.Background(), "sqlite", nil, "SELECT 1", nil); err != nil {
+		t.Fatalf("ExecPlugin: %v", err)
+	}
+	if got := readEnv(); got != "id= name=" {
+		t.Errorf("ExecPlugin without a stored connection should set nothing, got %q", got)
+	}
+}
+
+func TestGetPluginSettings(t *testing.T) {
+	scripts := map[string]string{
+		"declares": `#!/bin/sh
+if [ "$1" = "settings" ]; then
+  echo '{"settings":[{"key":"row-cap","label":"Default row cap","type":"NUMBER","defaultValue":"1000"}]}'
+else
+  exit 1
+fi
+`,
+		// predates the settings command
+		"legacy": "#!/bin/sh\nexit 1\n",
+		// its interpreter is missing, so the process never starts
+		"unstartable": "#!/nonexistent/interpreter\n",
+		// implements the command but reports a failure
+		"failing": "#!/bin/sh\necho '{\"error\":\"settings unavailable\"}'\nexit 1\n",
+	}
+	m := &Manager{plugins: map[string]PluginInfo{}}
+	for name, body := range scripts {
+		m.plugins[name] = writeScriptPlugin(t, name, body).plugins[name]
+	}
+
+	settings, err := m.GetPluginSettings("declares")
+	if err != nil {
+		t.Fatalf("GetPluginSettings: %v", err)
+	}
+	if len(settings) != 1 || settings[0].GetKey() != "row-cap" || settings[0].GetDefaultValue() != "1000" || settings[0].GetType() != plugin.AuthFieldNumber {
+		t.Errorf("unexpected settings: %v", settings)
+	}
+
+	settings, err = m.GetPluginSettings("legacy")
+	if err != nil || len(settings) != 0 {
+		t.Errorf("legacy plugin: got %v, %v; want no settings", settings, err)
+	}
+
+	if _, err := m.GetPluginSettings("nosuch"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing plugin: got %v, want a not-found error", err)
+	}
+	if _, err := m.GetPluginSettings("unstartable"); err == nil || !strings.Contains(err.Error(), "start error") {
+		t.Errorf("unstartable plugin: got %v, want a start error", err)
+	}
+	if _, err := m.GetPluginSettings("failing"); err == nil || !strings.Contains(err.Error(), "settings unavailable") {
+		t.Errorf("failing plugin: got %v, want the plugin's error", err)
+	}
 }
@@ -4,6 +4,7 @@
 package pluginmgr
 
 import (
+	"os"
 	"os/exec"
 	"syscall"
 )
@@ -21,3 +22,9 @@ func hideWindow(cmd *exec.Cmd) {
         cmd.SysProcAttr.HideWindow = true
     }
 }
+
+// interruptPlugin stops a plugin whose request was cancelled. Windows has no
+// signal a console-less child can catch, so the process is killed outright.
+func interruptPlugin(p *os.Process) error {
+    return p.Kill()
+}
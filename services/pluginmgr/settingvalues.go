@@ -0,0 +1,141 @@
+package pluginmgr
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/felixdotgo/querybox/pkg/driverid"
+)
+
+// settingValues records the values the user chose for the settings plugins
+// declare (see GetPluginSettings), keyed by plugin name and setting key.
+// Like the blocklist it is cached in memory and mirrored to the
+// plugin_settings table of settings.db; when the database cannot be opened
+// the values are kept for the session only.
+type settingValues struct {
+	mu     sync.RWMutex
+	values map[string]map[string]string
+	// db is nil when persistence is unavailable (or in tests).
+	db *sql.DB
+}
+
+// newMemorySettingValues returns a setting store that is never persisted.
+func newMemorySettingValues() *settingValues {
+	return &settingValues{values: make(map[string]map[string]string)}
+}
+
+// openSettingValues opens (creating if necessary) the plugin_settings table
+// in the SQLite file at dbPath and loads its contents. On any error it
+// returns an in-memory store together with the error so callers can log it.
+func openSettingValues(dbPath string) (*settingValues, error) {
+	s := newMemorySettingValues()
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+		return s, fmt.Errorf("create settings directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return s, fmt.Errorf("open settings database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+
+	create := `CREATE TABLE IF NOT EXISTS plugin_settings (
+		plugin TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (plugin, key)
+	);`
+	if _, err := db.Exec(create); err != nil {
+		_ = db.Close()
+		return s, fmt.Errorf("initialize plugin_settings schema: %w", err)
+	}
+
+	rows, err := db.Query(`SELECT plugin, key, value FROM plugin_settings`)
+	if err != nil {
+		_ = db.Close()
+		return s, fmt.Errorf("load plugin settings: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, key, value string
+		if err := rows.Scan(&name, &key, &value); err != nil {
+			_ = db.Close()
+			return newMemorySettingValues(), fmt.Errorf("load plugin settings: %w", err)
+		}
+		if s.values[name] == nil {
+			s.values[name] = make(map[string]string)
+		}
+		s.values[name][key] = value
+	}
+	s.db = db
+	return s, nil
+}
+
+// Get returns a copy of the values stored for the plugin. A nil store holds
+// nothing so hand-built Managers in tests need not set one.
+func (s *settingValues) Get(name string) map[string]string {
+	if s == nil {
+		return map[string]string{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stored := s.values[driverid.Normalize(name)]
+	out := make(map[string]string, len(stored))
+	for k, v := range stored {
+		out[k] = v
+	}
+	return out
+}
+
+// Set replaces the values stored for the plugin; an empty map clears them.
+// The in-memory copy is updated even if persisting fails.
+func (s *settingValues) Set(name string, values map[string]string) error {
+	name = driverid.Normalize(name)
+	copied := make(map[string]string, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(copied) == 0 {
+		delete(s.values, name)
+	} else {
+		s.values[name] = copied
+	}
+	if s.db == nil {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("persist plugin settings: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM plugin_settings WHERE plugin = ?`, name); err != nil {
+		return fmt.Errorf("persist plugin settings: %w", err)
+	}
+	for k, v := range copied {
+		if _, err := tx.Exec(`INSERT INTO plugin_settings (plugin, key, value) VALUES (?, ?, ?)`, name, k, v); err != nil {
+			return fmt.Errorf("persist plugin settings: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("persist plugin settings: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying database, if any.
+func (s *settingValues) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db != nil {
+		_ = s.db.Close()
+		s.db = nil
+	}
+}
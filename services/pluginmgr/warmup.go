@@ -0,0 +1,96 @@
+package pluginmgr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/felixdotgo/querybox/pkg/plugin"
+	"github.com/felixdotgo/querybox/services"
+	"google.golang.org/protobuf/proto"
+)
+
+// warmupTTL is how long a Warmup result is reused before the plugin is asked
+// again. Short enough that the status shown in the editor stays current.
+const warmupTTL = 30 * time.Second
+
+// warmupCache holds recent Warmup results keyed by driver and connection.
+type warmupCache struct {
+	mu      sync.Mutex
+	entries map[string]warmupEntry
+}
+
+type warmupEntry struct {
+	resp *plugin.TestConnectionResponse
+	at   time.Time
+}
+
+// warmupKey identifies a connection for the cache. ConnectionFingerprint
+// hashes the connection, so credentials are not kept in memory as map keys.
+func warmupKey(name string, connection map[string]string) string {
+	return name + "\x00" + plugin.ConnectionFingerprint(connection)
+}
+
+// Warmup connects to the data store ahead of the first query by running the
+// plugin's test-connection command, so the editor can show the connection
+// status early. Results, successful or not, are cached for warmupTTL per
+// driver and connection; calls within that window return the cached response
+// without starting the plugin. Each call returns its own copy of the
+// response. An error (plugin missing or failing to run) is returned as-is and
+// not cached.
+func (m *Manager) Warmup(name string, connection map[string]string) (*plugin.TestConnectionResponse, error) {
+	key := warmupKey(name, connection)
+
+	m.warmups.mu.Lock()
+	e, ok := m.warmups.entries[key]
+	m.warmups.mu.Unlock()
+	if ok && time.Since(e.at) < warmupTTL {
+		m.emitLog(services.LogLevelDebug, fmt.Sprintf("Warmup: using cached status (driver: %s)", name))
+		return proto.Clone(e.resp).(*plugin.TestConnectionResponse), nil
+	}
+
+	resp, err := m.TestConnection(name, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	m.warmups.mu.Lock()
+	if m.warmups.entries == nil {
+		m.warmups.entries = make(map[string]warmupEntry)
+	}
+	// drop stale entries so connections that are no longer used don't linger
+	for k, old := range m.warmups.entries {
+		if time.Since(old.at) >= warmupTTL {
+			delete(m.warmups.entries, k)
+		}
+	}
+	m.warmups.entries[key] = warmupEntry{resp: proto.Clone(resp).(*plugin.TestConnectionResponse), at: time.Now()}
+	m.warmups.mu.Unlock()
+	return resp, nil
+}
+
+// WarmupConnection is Warmup for the stored connection connectionID: like
+// RunQuery, the driver and credential are resolved on the Go side. The
+// outcome is recorded as the connection's status through the
+// ConnectionSource, so the connections list can show it without testing
+// again; a plugin that fails to run counts as a failed check.
+func (m *Manager) WarmupConnection(ctx context.Context, connectionID string) (*plugin.TestConnectionResponse, error) {
+	conn, connection, err := m.resolveConnection(ctx, "WarmupConnection", connectionID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.Warmup(conn.DriverType, connection)
+
+	m.mu.Lock()
+	src := m.connections
+	m.mu.Unlock()
+	ok, msg := resp.GetOk(), resp.GetMessage()
+	if err != nil {
+		ok, msg = false, err.Error()
+	}
+	if _, rerr := src.RecordConnectionStatus(ctx, conn.ID, ok, msg); rerr != nil {
+		m.emitLog(services.LogLevelWarn, fmt.Sprintf("WarmupConnection: failed to record status for '%s': %v", conn.ID, rerr))
+	}
+	return resp, err
+}